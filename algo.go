@@ -50,6 +50,18 @@ func (as AlgoStack) Run(s StrategyHandler) (bool, error) {
 	return true, nil
 }
 
+// Reset implements Reseter to reset all algos of the stack, which hold a state.
+func (as AlgoStack) Reset() error {
+	for _, algo := range as.stack {
+		if r, ok := algo.(Reseter); ok {
+			if err := r.Reset(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // RunAlways set the runAlways property on the AlgoHandler
 func RunAlways(a AlgoHandler) AlgoHandler {
 	a.SetAlways()
@@ -19,3 +19,24 @@ func BoolAlgo(b bool) gbt.AlgoHandler {
 func (a boolAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	return a.bool, nil
 }
+
+// constantAlgo is a base Algo with a constant value.
+type constantAlgo struct {
+	gbt.Algo
+	value float64
+}
+
+// Constant returns an algo with a constant value, e.g. to compare an indicator against a fixed level.
+func Constant(v float64) gbt.AlgoHandler {
+	return &constantAlgo{value: v}
+}
+
+// Run runs the algo, always returns true.
+func (a constantAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	return true, nil
+}
+
+// Value returns the constant value of the algo.
+func (a *constantAlgo) Value() float64 {
+	return a.value
+}
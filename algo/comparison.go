@@ -1,6 +1,8 @@
 package algo
 
 import (
+	"math"
+
 	gbt "github.com/dirkolbrich/gobacktest"
 )
 
@@ -38,6 +40,11 @@ func (algo biggerThanAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	return result, nil
 }
 
+// Reset implements Reseter to reset both compared algos.
+func (algo biggerThanAlgo) Reset() error {
+	return reset(algo.first, algo.second)
+}
+
 type smallerThanAlgo struct {
 	gbt.Algo
 	first, second gbt.AlgoHandler
@@ -72,17 +79,30 @@ func (algo smallerThanAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	return result, nil
 }
 
+// Reset implements Reseter to reset both compared algos.
+func (algo smallerThanAlgo) Reset() error {
+	return reset(algo.first, algo.second)
+}
+
 type equalAlgo struct {
 	gbt.Algo
 	first, second gbt.AlgoHandler
+	tolerance     float64
 }
 
-// Equal compares the value of two algos.
-func Equal(first, second gbt.AlgoHandler) gbt.AlgoHandler {
-	return &equalAlgo{
-		first:  first,
-		second: second,
+// Equal compares the value of two algos. The values are equal, if their difference is within the tolerance,
+// without a given tolerance a small default tolerance of 1e-9 absorbs floating point errors.
+func Equal(first, second gbt.AlgoHandler, tolerance ...float64) gbt.AlgoHandler {
+	algo := &equalAlgo{
+		first:     first,
+		second:    second,
+		tolerance: 1e-9,
+	}
+	if len(tolerance) > 0 {
+		algo.tolerance = tolerance[0]
 	}
+
+	return algo
 }
 
 // Run runs the algo, returns the bool value of the algo
@@ -101,7 +121,74 @@ func (algo equalAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 		return false, nil
 	}
 
-	result := algo.first.Value() == algo.second.Value()
+	result := math.Abs(algo.first.Value()-algo.second.Value()) <= algo.tolerance
 
 	return result, nil
 }
+
+// Reset implements Reseter to reset both compared algos.
+func (algo equalAlgo) Reset() error {
+	return reset(algo.first, algo.second)
+}
+
+type constantCompareAlgo struct {
+	gbt.Algo
+	algo     gbt.AlgoHandler
+	constant float64
+	above    bool
+}
+
+// AboveConstant checks if the value of the algo is above a constant, e.g. the RSI above 70.
+func AboveConstant(algo gbt.AlgoHandler, c float64) gbt.AlgoHandler {
+	return &constantCompareAlgo{algo: algo, constant: c, above: true}
+}
+
+// BelowConstant checks if the value of the algo is below a constant, e.g. the RSI below 30.
+func BelowConstant(algo gbt.AlgoHandler, c float64) gbt.AlgoHandler {
+	return &constantCompareAlgo{algo: algo, constant: c}
+}
+
+// Run runs the algo, returns the bool value of the algo
+func (algo constantCompareAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	ok, err := algo.algo.Run(s)
+	if !ok || (err != nil) {
+		return false, err
+	}
+
+	if algo.above {
+		return algo.algo.Value() > algo.constant, nil
+	}
+	return algo.algo.Value() < algo.constant, nil
+}
+
+// Reset implements Reseter to reset the compared algo.
+func (algo constantCompareAlgo) Reset() error {
+	return reset(algo.algo)
+}
+
+type betweenAlgo struct {
+	gbt.Algo
+	algo      gbt.AlgoHandler
+	low, high float64
+}
+
+// Between checks if the value of the algo is within the range from low to high, including both limits.
+func Between(algo gbt.AlgoHandler, low, high float64) gbt.AlgoHandler {
+	return &betweenAlgo{algo: algo, low: low, high: high}
+}
+
+// Run runs the algo, returns the bool value of the algo
+func (algo betweenAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	ok, err := algo.algo.Run(s)
+	if !ok || (err != nil) {
+		return false, err
+	}
+
+	value := algo.algo.Value()
+	return (value >= algo.low) && (value <= algo.high), nil
+}
+
+// Reset implements Reseter to reset the compared algo.
+func (algo betweenAlgo) Reset() error {
+	return reset(algo.algo)
+}
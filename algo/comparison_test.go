@@ -0,0 +1,49 @@
+package algo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+func TestComparisonAlgos(t *testing.T) {
+	var testCases = []struct {
+		msg    string
+		algo   gbt.AlgoHandler
+		expOk  bool
+		expErr error
+	}{
+		{"testing bigger than", BiggerThan(Constant(2), Constant(1)), true, nil},
+		{"testing smaller than", SmallerThan(Constant(2), Constant(1)), false, nil},
+		{"testing equal", Equal(Constant(0.1+0.2), Constant(0.3)), true, nil},
+		{"testing equal with tolerance", Equal(Constant(1), Constant(1.05), 0.1), true, nil},
+		{"testing not equal with tolerance", Equal(Constant(1), Constant(1.2), 0.1), false, nil},
+		{"testing above constant", AboveConstant(Constant(71), 70), true, nil},
+		{"testing not above constant", AboveConstant(Constant(70), 70), false, nil},
+		{"testing below constant", BelowConstant(Constant(29), 30), true, nil},
+		{"testing between", Between(Constant(5), 1, 5), true, nil},
+		{"testing not between", Between(Constant(6), 1, 5), false, nil},
+		{"testing not", Not(BoolAlgo(false)), true, nil},
+		{"testing not true", Not(BoolAlgo(true)), false, nil},
+		{"testing not with error", Not(&errorAlgo{}), false, fmt.Errorf("error")},
+		{"testing above constant with error", AboveConstant(&errorAlgo{}, 0), false, fmt.Errorf("error")},
+	}
+
+	for _, tc := range testCases {
+		ok, err := tc.algo.Run(&gbt.Strategy{})
+		if (ok != tc.expOk) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v", tc.msg, tc.expOk, tc.expErr, ok, err)
+		}
+	}
+}
+
+// errorAlgo is an algo which always fails with an error.
+type errorAlgo struct {
+	gbt.Algo
+}
+
+func (a errorAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	return false, fmt.Errorf("error")
+}
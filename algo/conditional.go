@@ -34,6 +34,11 @@ func (algo ifAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	return true, nil // always return true
 }
 
+// Reset implements Reseter to reset the condition and the action algo.
+func (algo ifAlgo) Reset() error {
+	return reset(algo.condition, algo.action)
+}
+
 type andAlgo struct {
 	gbt.Algo
 	a, b gbt.AlgoHandler
@@ -66,6 +71,11 @@ func (algo andAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	return true, nil
 }
 
+// Reset implements Reseter to reset both algos.
+func (algo andAlgo) Reset() error {
+	return reset(algo.a, algo.b)
+}
+
 type orAlgo struct {
 	gbt.Algo
 	a, b gbt.AlgoHandler
@@ -98,6 +108,11 @@ func (algo orAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	return true, nil
 }
 
+// Reset implements Reseter to reset both algos.
+func (algo orAlgo) Reset() error {
+	return reset(algo.a, algo.b)
+}
+
 type xorAlgo struct {
 	gbt.Algo
 	a, b gbt.AlgoHandler
@@ -129,3 +144,33 @@ func (algo xorAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 
 	return true, nil
 }
+
+// Reset implements Reseter to reset both algos.
+func (algo xorAlgo) Reset() error {
+	return reset(algo.a, algo.b)
+}
+
+type notAlgo struct {
+	gbt.Algo
+	algo gbt.AlgoHandler
+}
+
+// Not negates the result of an algo. An error of the algo is passed on and returns false.
+func Not(algo gbt.AlgoHandler) gbt.AlgoHandler {
+	return &notAlgo{algo: algo}
+}
+
+// Run runs the algo, returns the bool value of the algo.
+func (algo notAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	ok, err := algo.algo.Run(s)
+	if err != nil {
+		return false, err
+	}
+
+	return !ok, nil
+}
+
+// Reset implements Reseter to reset the negated algo.
+func (algo notAlgo) Reset() error {
+	return reset(algo.algo)
+}
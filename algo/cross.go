@@ -0,0 +1,114 @@
+package algo
+
+import (
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// crossAlgo checks if the value of the first algo crosses the value of the second algo.
+type crossAlgo struct {
+	gbt.Algo
+	first, second gbt.AlgoHandler
+	above         bool
+	diff          *history
+}
+
+// CrossAbove returns true, if the value of the first algo moved above the value of the second algo
+// since the last data event of the symbol, e.g. a short moving average crossing above a long one.
+func CrossAbove(first, second gbt.AlgoHandler) gbt.AlgoHandler {
+	return &crossAlgo{first: first, second: second, above: true, diff: newHistory(2)}
+}
+
+// CrossBelow returns true, if the value of the first algo moved below the value of the second algo
+// since the last data event of the symbol.
+func CrossBelow(first, second gbt.AlgoHandler) gbt.AlgoHandler {
+	return &crossAlgo{first: first, second: second, diff: newHistory(2)}
+}
+
+// Run runs the algo, returns the bool value of the algo.
+func (algo *crossAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	okFirst, err := algo.first.Run(s)
+	if err != nil {
+		return false, err
+	}
+
+	okSecond, err := algo.second.Run(s)
+	if err != nil {
+		return false, err
+	}
+
+	if !okFirst || !okSecond {
+		return false, nil
+	}
+
+	values := algo.diff.add(s, algo.first.Value()-algo.second.Value())
+	// no previous value to compare with
+	if len(values) < 2 {
+		return false, nil
+	}
+
+	last, current := values[0], values[1]
+	if algo.above {
+		return (last <= 0) && (current > 0), nil
+	}
+	return (last >= 0) && (current < 0), nil
+}
+
+// Reset implements Reseter to forget the previous values and reset both compared algos.
+func (algo *crossAlgo) Reset() error {
+	algo.diff.reset()
+	return reset(algo.first, algo.second)
+}
+
+// trendAlgo checks if the value of an algo moved in one direction over the last n data events.
+type trendAlgo struct {
+	gbt.Algo
+	algo    gbt.AlgoHandler
+	rising  bool
+	history *history
+}
+
+// Rising returns true, if the value of the algo increased with each of the last n data events of the symbol.
+func Rising(algo gbt.AlgoHandler, n int) gbt.AlgoHandler {
+	return &trendAlgo{algo: algo, rising: true, history: newHistory(n + 1)}
+}
+
+// Falling returns true, if the value of the algo decreased with each of the last n data events of the symbol.
+func Falling(algo gbt.AlgoHandler, n int) gbt.AlgoHandler {
+	return &trendAlgo{algo: algo, history: newHistory(n + 1)}
+}
+
+// Run runs the algo, returns the bool value of the algo.
+func (algo *trendAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	ok, err := algo.algo.Run(s)
+	if !ok || (err != nil) {
+		return false, err
+	}
+
+	values := algo.history.add(s, algo.algo.Value())
+	// not enough values yet
+	if len(values) < algo.history.size {
+		return false, nil
+	}
+
+	for i := 1; i < len(values); i++ {
+		if algo.rising && (values[i] <= values[i-1]) {
+			return false, nil
+		}
+		if !algo.rising && (values[i] >= values[i-1]) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Value returns the value of the wrapped algo.
+func (algo *trendAlgo) Value() float64 {
+	return algo.algo.Value()
+}
+
+// Reset implements Reseter to forget the previous values and reset the wrapped algo.
+func (algo *trendAlgo) Reset() error {
+	algo.history.reset()
+	return reset(algo.algo)
+}
@@ -0,0 +1,115 @@
+package algo
+
+import (
+	"reflect"
+	"testing"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// testHelperRunSeries runs an algo on every data event of the price series
+// and returns the results of each run per symbol.
+func testHelperRunSeries(algo gbt.AlgoHandler, prices map[string][]float64) map[string][]bool {
+	data, _ := testHelperMockPrices(prices)
+	data.Reset()
+
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+
+	results := make(map[string][]bool)
+	for event, ok := data.Next(); ok; event, ok = data.Next() {
+		strategy.SetEvent(event)
+		ok, _ := algo.Run(strategy)
+		results[event.Symbol()] = append(results[event.Symbol()], ok)
+	}
+
+	return results
+}
+
+func TestCrossAlgos(t *testing.T) {
+	// A crosses 10 upwards on the third event, B crosses 10 downwards on the third event
+	prices := map[string][]float64{
+		"A": {8, 9, 11, 12, 9},
+		"B": {12, 11, 9, 8, 11},
+	}
+
+	var testCases = []struct {
+		msg      string
+		algo     gbt.AlgoHandler
+		expected map[string][]bool
+	}{
+		{"testing cross above",
+			CrossAbove(Price(), Constant(10)),
+			map[string][]bool{
+				"A": {false, false, true, false, false},
+				"B": {false, false, false, false, true},
+			},
+		},
+		{"testing cross below",
+			CrossBelow(Price(), Constant(10)),
+			map[string][]bool{
+				"A": {false, false, false, false, true},
+				"B": {false, false, true, false, false},
+			},
+		},
+		{"testing rising",
+			Rising(Price(), 2),
+			map[string][]bool{
+				"A": {false, false, true, true, false},
+				"B": {false, false, false, false, false},
+			},
+		},
+		{"testing falling",
+			Falling(Price(), 2),
+			map[string][]bool{
+				"A": {false, false, false, false, false},
+				"B": {false, false, true, true, false},
+			},
+		},
+		{"testing cross with indicator",
+			CrossAbove(SMA(1), SMA(2)),
+			map[string][]bool{
+				"A": {false, false, false, false, false},
+				"B": {false, false, false, false, true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		results := testHelperRunSeries(tc.algo, prices)
+		if !reflect.DeepEqual(results, tc.expected) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expected, results)
+		}
+
+		// after a reset the algo does not remember the values of the last run
+		if err := tc.algo.(gbt.Reseter).Reset(); err != nil {
+			t.Fatal(err)
+		}
+		results = testHelperRunSeries(tc.algo, prices)
+		if !reflect.DeepEqual(results, tc.expected) {
+			t.Errorf("%v after reset: \nexpected %v, \nactual   %v", tc.msg, tc.expected, results)
+		}
+	}
+}
+
+func TestCrossAlgoSameTimestamp(t *testing.T) {
+	data, _ := testHelperMockPrices(map[string][]float64{"A": {8, 11}})
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+
+	algo := CrossAbove(Price(), Constant(10))
+	data.Reset()
+	event, _ := data.Next()
+	strategy.SetEvent(event)
+	algo.Run(strategy)
+
+	event, _ = data.Next()
+	strategy.SetEvent(event)
+
+	// running twice on the same event compares with the event before both times
+	for i := 0; i < 2; i++ {
+		if ok, err := algo.Run(strategy); !ok || (err != nil) {
+			t.Errorf("testing cross run %v on same event: \nexpected %v %v, \nactual   %v %v", i+1, true, nil, ok, err)
+		}
+	}
+}
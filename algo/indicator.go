@@ -2,50 +2,386 @@ package algo
 
 import (
 	"fmt"
+	"strings"
 
 	gbt "github.com/dirkolbrich/gobacktest"
 	"github.com/dirkolbrich/gobacktest/ta"
 )
 
-// smaAlgo is an algo which calculates the simple moving average.
-type smaAlgo struct {
+// SMA returns a simple moving average algo ready to use.
+func SMA(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("SMA%d", period), func() *stream {
+		ind := ta.NewSMA(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// indicatorAlgo is a generic algo, which feeds the data events of the current symbol into a streaming indicator
+// and saves its value to the event metrics under its name. It keeps one indicator per symbol and strategy,
+// so each data event is only processed once, regardless of the length of the data list.
+type indicatorAlgo struct {
 	gbt.Algo
-	period int
-	sma    float64
+	name    string
+	create  func() *stream
+	streams map[scope]*stream
+	current *stream // stream of the last run
 }
 
-// SMA returns a sma algo ready to use.
-func SMA(i int) gbt.AlgoHandler {
-	return &smaAlgo{period: i}
+// newIndicator creates a generic indicator algo, which creates a new stream for each symbol.
+func newIndicator(name string, create func() *stream) *indicatorAlgo {
+	return &indicatorAlgo{name: name, create: create}
 }
 
 // Run runs the algo.
-func (a *smaAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+func (a *indicatorAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 	data, _ := s.Data()
 	event, _ := s.Event()
 	symbol := event.Symbol()
 
-	// prepare list of floats
+	if a.streams == nil {
+		a.streams = make(map[scope]*stream)
+	}
+	key := scopeOf(s)
+	st, ok := a.streams[key]
+	if !ok {
+		st = a.create()
+		a.streams[key] = st
+	}
+	a.current = st
+
+	// a data list shorter than the already processed data events means the data was reset
 	list := data.List(symbol)
-	var values []float64
+	if st.count > len(list) {
+		st.reset()
+		st.count = 0
+	}
 
-	if len(list) < a.period {
-		return false, fmt.Errorf("invalid value length for indicator sma")
+	// feed all data events, which are new since the last run
+	for _, e := range list[st.count:] {
+		st.update(e)
 	}
+	st.count = len(list)
+
+	if !st.ready() {
+		return false, fmt.Errorf("invalid value length for indicator %v", kind(a.name))
+	}
+
+	// save the calculated value to the event metrics
+	event.Add(a.name, st.value())
+
+	return true, nil
+}
 
-	for i := 0; i < a.period; i++ {
-		values = append(values, list[len(list)-i-1].Price())
+// Value returns the value of this Algo for the symbol of the last run.
+func (a *indicatorAlgo) Value() float64 {
+	if (a.current == nil) || !a.current.ready() {
+		return 0
 	}
+	return a.current.value()
+}
+
+// Reset implements Reseter to drop the indicators of all symbols.
+func (a *indicatorAlgo) Reset() error {
+	a.streams = nil
+	a.current = nil
+	return nil
+}
+
+// stream adapts a streaming indicator of package ta to the data events of a single symbol.
+type stream struct {
+	update func(gbt.DataEvent)
+	value  func() float64
+	ready  func() bool
+	reset  func()
+	count  int // number of data events fed into the indicator
+}
+
+// priceStream creates a stream, which feeds the price of each data event into the indicator.
+// The value func selects the output of the indicator.
+func priceStream(ind ta.Indicator, value func() float64) *stream {
+	return &stream{
+		update: func(e gbt.DataEvent) { ind.Update(e.Price()) },
+		value:  value,
+		ready:  ind.Ready,
+		reset:  ind.Reset,
+	}
+}
+
+// barStream creates a stream, which feeds each data event as bar into the indicator.
+// The value func selects the output of the indicator.
+func barStream(ind ta.BarIndicator, value func() float64) *stream {
+	return &stream{
+		update: func(e gbt.DataEvent) { ind.Update(bar(e)) },
+		value:  value,
+		ready:  ind.Ready,
+		reset:  ind.Reset,
+	}
+}
 
-	// calculate SMA
-	a.sma = ta.Mean(values)
-	// save the calculated sma to the event metrics
-	event.Add(fmt.Sprintf("SMA%d", a.period), a.sma)
+// kind returns the lower case indicator name without its parameters, e.g. "sma" for "SMA20".
+func kind(name string) string {
+	return strings.TrimRight(strings.ToLower(name), "0123456789_.")
+}
+
+// priceAlgo returns the price of the current data event.
+type priceAlgo struct {
+	gbt.Algo
+	price float64
+}
+
+// Price returns an algo with the price of the current data event as value,
+// e.g. to compare the price with an indicator.
+func Price() gbt.AlgoHandler {
+	return &priceAlgo{}
+}
 
+// Run runs the algo.
+func (a *priceAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	event, ok := s.Event()
+	if !ok {
+		return false, nil
+	}
+
+	a.price = event.Price()
 	return true, nil
 }
 
 // Value returns the value of this Algo.
-func (a *smaAlgo) Value() float64 {
-	return a.sma
+func (a *priceAlgo) Value() float64 {
+	return a.price
+}
+
+// EMA returns an exponential moving average algo ready to use.
+func EMA(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("EMA%d", period), func() *stream {
+		ind := ta.NewEMA(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// WMA returns a weighted moving average algo ready to use.
+func WMA(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("WMA%d", period), func() *stream {
+		ind := ta.NewWMA(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// DEMA returns a double exponential moving average algo ready to use.
+func DEMA(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("DEMA%d", period), func() *stream {
+		ind := ta.NewDEMA(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// TEMA returns a triple exponential moving average algo ready to use.
+func TEMA(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("TEMA%d", period), func() *stream {
+		ind := ta.NewTEMA(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// RSI returns a relative strength index algo ready to use.
+func RSI(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("RSI%d", period), func() *stream {
+		ind := ta.NewRSI(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// MACD returns an algo with the macd line of the moving average convergence divergence.
+func MACD(fast, slow, signal int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("MACD%d_%d_%d", fast, slow, signal), func() *stream {
+		ind := ta.NewMACD(fast, slow, signal)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// MACDSignal returns an algo with the signal line of the moving average convergence divergence.
+func MACDSignal(fast, slow, signal int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("MACDSIGNAL%d_%d_%d", fast, slow, signal), func() *stream {
+		ind := ta.NewMACD(fast, slow, signal)
+		return priceStream(ind, ind.Signal)
+	})
+}
+
+// MACDHist returns an algo with the histogram of the moving average convergence divergence.
+func MACDHist(fast, slow, signal int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("MACDHIST%d_%d_%d", fast, slow, signal), func() *stream {
+		ind := ta.NewMACD(fast, slow, signal)
+		return priceStream(ind, ind.Hist)
+	})
+}
+
+// BollingerUpper returns an algo with the upper Bollinger Band.
+func BollingerUpper(period int, k float64) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("BBUPPER%d_%v", period, k), func() *stream {
+		ind := ta.NewBollinger(period, k)
+		return priceStream(ind, ind.Upper)
+	})
+}
+
+// BollingerMiddle returns an algo with the middle Bollinger Band.
+func BollingerMiddle(period int, k float64) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("BBMIDDLE%d_%v", period, k), func() *stream {
+		ind := ta.NewBollinger(period, k)
+		return priceStream(ind, ind.Middle)
+	})
+}
+
+// BollingerLower returns an algo with the lower Bollinger Band.
+func BollingerLower(period int, k float64) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("BBLOWER%d_%v", period, k), func() *stream {
+		ind := ta.NewBollinger(period, k)
+		return priceStream(ind, ind.Lower)
+	})
+}
+
+// ATR returns an average true range algo ready to use.
+func ATR(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("ATR%d", period), func() *stream {
+		ind := ta.NewATR(period)
+		return barStream(ind, ind.Value)
+	})
+}
+
+// ADX returns an average directional index algo ready to use.
+func ADX(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("ADX%d", period), func() *stream {
+		ind := ta.NewADX(period)
+		return barStream(ind, ind.Value)
+	})
+}
+
+// PlusDI returns an algo with the positive directional indicator of the ADX.
+func PlusDI(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("PLUSDI%d", period), func() *stream {
+		ind := ta.NewADX(period)
+		return barStream(ind, ind.PlusDI)
+	})
+}
+
+// MinusDI returns an algo with the negative directional indicator of the ADX.
+func MinusDI(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("MINUSDI%d", period), func() *stream {
+		ind := ta.NewADX(period)
+		return barStream(ind, ind.MinusDI)
+	})
+}
+
+// StochK returns an algo with the %K line of the stochastic oscillator.
+func StochK(kPeriod, dPeriod int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("STOCHK%d_%d", kPeriod, dPeriod), func() *stream {
+		ind := ta.NewStochastic(kPeriod, dPeriod)
+		return barStream(ind, ind.Value)
+	})
+}
+
+// StochD returns an algo with the %D line of the stochastic oscillator.
+func StochD(kPeriod, dPeriod int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("STOCHD%d_%d", kPeriod, dPeriod), func() *stream {
+		ind := ta.NewStochastic(kPeriod, dPeriod)
+		return barStream(ind, ind.D)
+	})
+}
+
+// CCI returns a commodity channel index algo ready to use.
+func CCI(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("CCI%d", period), func() *stream {
+		ind := ta.NewCCI(period)
+		return barStream(ind, ind.Value)
+	})
+}
+
+// OBV returns an on balance volume algo ready to use.
+func OBV() gbt.AlgoHandler {
+	return newIndicator("OBV", func() *stream {
+		ind := ta.NewOBV()
+		return barStream(ind, ind.Value)
+	})
+}
+
+// VWAP returns a rolling volume weighted average price algo ready to use.
+func VWAP(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("VWAP%d", period), func() *stream {
+		ind := ta.NewVWAP(period)
+		return barStream(ind, ind.Value)
+	})
+}
+
+// DonchianUpper returns an algo with the upper band of the Donchian Channel.
+func DonchianUpper(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("DONCHIANUPPER%d", period), func() *stream {
+		ind := ta.NewDonchian(period)
+		return barStream(ind, ind.Upper)
+	})
+}
+
+// DonchianLower returns an algo with the lower band of the Donchian Channel.
+func DonchianLower(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("DONCHIANLOWER%d", period), func() *stream {
+		ind := ta.NewDonchian(period)
+		return barStream(ind, ind.Lower)
+	})
+}
+
+// KeltnerUpper returns an algo with the upper band of the Keltner Channel.
+func KeltnerUpper(period int, k float64) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("KELTNERUPPER%d_%v", period, k), func() *stream {
+		ind := ta.NewKeltner(period, k)
+		return barStream(ind, ind.Upper)
+	})
+}
+
+// KeltnerMiddle returns an algo with the middle band of the Keltner Channel.
+func KeltnerMiddle(period int, k float64) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("KELTNERMIDDLE%d_%v", period, k), func() *stream {
+		ind := ta.NewKeltner(period, k)
+		return barStream(ind, ind.Middle)
+	})
+}
+
+// KeltnerLower returns an algo with the lower band of the Keltner Channel.
+func KeltnerLower(period int, k float64) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("KELTNERLOWER%d_%v", period, k), func() *stream {
+		ind := ta.NewKeltner(period, k)
+		return barStream(ind, ind.Lower)
+	})
+}
+
+// StdDev returns a rolling standard deviation algo ready to use.
+func StdDev(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("STDDEV%d", period), func() *stream {
+		ind := ta.NewStdDev(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// ZScore returns a rolling z-score algo ready to use.
+func ZScore(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("ZSCORE%d", period), func() *stream {
+		ind := ta.NewZScore(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// ROC returns a rate of change algo ready to use.
+func ROC(period int) gbt.AlgoHandler {
+	return newIndicator(fmt.Sprintf("ROC%d", period), func() *stream {
+		ind := ta.NewROC(period)
+		return priceStream(ind, ind.Value)
+	})
+}
+
+// bar converts a data event into an OHLCV bar.
+// Data events which are not a bar, e.g. ticks, use their price for all OHLC values.
+func bar(event gbt.DataEvent) ta.Bar {
+	if e, ok := event.(*gbt.Bar); ok {
+		return ta.Bar{Open: e.Open, High: e.High, Low: e.Low, Close: e.Close, Volume: float64(e.Volume)}
+	}
+
+	price := event.Price()
+	return ta.Bar{Open: price, High: price, Low: price, Close: price}
 }
@@ -2,6 +2,7 @@ package algo
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
@@ -76,3 +77,125 @@ func TestSMAIntegration(t *testing.T) {
 	}
 
 }
+
+func TestIndicatorAlgos(t *testing.T) {
+	// set up mock data events with a linear close price from 1 to 30
+	var dates []string
+	for i := 1; i <= 30; i++ {
+		dates = append(dates, fmt.Sprintf("2018-07-%02d", i))
+	}
+	mockdata := testHelperMockData(dates)
+	for i, data := range mockdata {
+		bar := data.(*gbt.Bar)
+		bar.Metric = gbt.Metric{}
+		bar.Close = float64(i + 1)
+		bar.High = bar.Close + 1
+		bar.Low = bar.Close - 1
+		bar.Volume = 100
+	}
+
+	var testCases = []struct {
+		algo     gbt.AlgoHandler
+		name     string
+		expValue float64
+	}{
+		{EMA(3), "EMA3", 29},
+		{WMA(3), "WMA3", (28 + 2*29 + 3*30) / 6.0},
+		{DEMA(3), "DEMA3", 30},
+		{TEMA(3), "TEMA3", 30},
+		{RSI(14), "RSI14", 100},
+		{MACD(2, 3, 2), "MACD2_3_2", 0.5},
+		{MACDSignal(2, 3, 2), "MACDSIGNAL2_3_2", 0.5},
+		{MACDHist(2, 3, 2), "MACDHIST2_3_2", 0},
+		{BollingerMiddle(3, 2), "BBMIDDLE3_2", 29},
+		{ATR(3), "ATR3", 2},
+		{DonchianUpper(3), "DONCHIANUPPER3", 31},
+		{DonchianLower(3), "DONCHIANLOWER3", 27},
+		{KeltnerMiddle(3, 2), "KELTNERMIDDLE3_2", 29},
+		{KeltnerUpper(3, 2), "KELTNERUPPER3_2", 33},
+		{OBV(), "OBV", 2900},
+		{VWAP(3), "VWAP3", 29},
+		{ROC(1), "ROC1", 100.0 / 29},
+		{ZScore(3), "ZSCORE3", 1.224744871391589},
+		{StochK(3, 2), "STOCHK3_2", 75},
+		{CCI(3), "CCI3", 100},
+		{ADX(3), "ADX3", 100},
+		{MinusDI(3), "MINUSDI3", 0},
+	}
+
+	for _, tc := range testCases {
+		data := &gbt.Data{}
+		data.SetStream(mockdata)
+		var event gbt.DataEvent
+		for e, ok := data.Next(); ok; e, ok = data.Next() {
+			event = e
+		}
+
+		strategy := &gbt.Strategy{}
+		strategy.SetData(data)
+		strategy.SetEvent(event)
+
+		ok, err := tc.algo.Run(strategy)
+		metric, found := event.Get(tc.name)
+		if !ok || (err != nil) || !found || math.Abs(tc.algo.Value()-tc.expValue) > 1e-9 || (metric != tc.algo.Value()) {
+			t.Errorf("%v: \nexpected %v %v %v %v, \nactual   %v %v %v %v",
+				tc.name, true, nil, true, tc.expValue, ok, err, found, tc.algo.Value())
+		}
+	}
+}
+
+func TestIndicatorAlgoStreams(t *testing.T) {
+	// stream the events one by one and run the algo on each event of both symbols
+	data, _ := testHelperMockPrices(map[string][]float64{
+		"A": {1, 2, 3, 4, 5},
+		"B": {10, 20, 30, 40, 50},
+	})
+	data.Reset()
+
+	algo := SMA(3)
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+
+	run := func() map[string][]float64 {
+		values := make(map[string][]float64)
+		for event, ok := data.Next(); ok; event, ok = data.Next() {
+			strategy.SetEvent(event)
+			if ok, _ := algo.Run(strategy); ok {
+				values[event.Symbol()] = append(values[event.Symbol()], algo.Value())
+			}
+		}
+		return values
+	}
+
+	expected := map[string][]float64{
+		"A": {2, 3, 4},
+		"B": {20, 30, 40},
+	}
+
+	first := run()
+	if !reflect.DeepEqual(first, expected) {
+		t.Errorf("testing sma per symbol: \nexpected %v, \nactual   %v", expected, first)
+	}
+
+	// a reset data handler restarts the indicators, even without resetting the algo
+	data.Reset()
+	second := run()
+	if !reflect.DeepEqual(second, expected) {
+		t.Errorf("testing sma after data reset: \nexpected %v, \nactual   %v", expected, second)
+	}
+
+	// an algo which skipped events catches up with the data list on its next run
+	data.Reset()
+	if err := algo.(gbt.Reseter).Reset(); err != nil {
+		t.Fatal(err)
+	}
+	var last gbt.DataEvent
+	for event, ok := data.Next(); ok; event, ok = data.Next() {
+		last = event
+	}
+	strategy.SetEvent(last)
+	ok, err := algo.Run(strategy)
+	if !ok || (err != nil) || (algo.Value() != 40) {
+		t.Errorf("testing sma catch up: \nexpected %v %v %v, \nactual   %v %v %v", true, nil, 40, ok, err, algo.Value())
+	}
+}
@@ -6,10 +6,10 @@ import (
 	gbt "github.com/dirkolbrich/gobacktest"
 )
 
-// runOnce which returns true once and then returns false.
+// runOnce which returns true once for each symbol and strategy and then returns false.
 type runOnce struct {
 	gbt.Algo
-	hasRun bool
+	hasRun map[scope]bool
 }
 
 // RunOnce returns a runOnce algo ready to use.
@@ -19,14 +19,25 @@ func RunOnce() gbt.AlgoHandler {
 
 // Run runs the RunOnce() algo.
 func (ro *runOnce) Run(s gbt.StrategyHandler) (bool, error) {
-	if ro.hasRun {
+	if ro.hasRun == nil {
+		ro.hasRun = make(map[scope]bool)
+	}
+
+	key := scopeOf(s)
+	if ro.hasRun[key] {
 		return false, nil
 	}
 
-	ro.hasRun = true
+	ro.hasRun[key] = true
 	return true, nil
 }
 
+// Reset implements Reseter, so the algo runs once again.
+func (ro *runOnce) Reset() error {
+	ro.hasRun = nil
+	return nil
+}
+
 // PeriodRunner defines how the function to compare two dates.
 type PeriodRunner interface {
 	CompareDates(time.Time, time.Time) (bool, error)
@@ -63,9 +74,12 @@ type runPeriod struct {
 // Run runs the algo. It retrieves the current date and the date before.
 // Then calls the specific implementations to compare these two dates.
 func (rp *runPeriod) Run(s gbt.StrategyHandler) (bool, error) {
-	now := rp.getNow(s)
+	now, ok := rp.getNow(s)
+	if !ok {
+		return false, nil
+	}
 
-	toCompare, ok := rp.getDateToCompare(s)
+	toCompare, ok := rp.getDateToCompare(s, now)
 	if !ok {
 		return false, nil
 	}
@@ -74,15 +88,11 @@ func (rp *runPeriod) Run(s gbt.StrategyHandler) (bool, error) {
 	return rp.CompareDates(now, toCompare)
 }
 
-func (rp *runPeriod) getNow(s gbt.StrategyHandler) time.Time {
-	// get current data event date
-	event, _ := s.Event()
-	now := event.Time()
-
-	return now
+func (rp *runPeriod) getNow(s gbt.StrategyHandler) (time.Time, bool) {
+	return now(s)
 }
 
-func (rp *runPeriod) getDateToCompare(s gbt.StrategyHandler) (time.Time, bool) {
+func (rp *runPeriod) getDateToCompare(s gbt.StrategyHandler, now time.Time) (time.Time, bool) {
 	data, ok := s.Data()
 	// no history yet, so nothing to compare
 	if !ok {
@@ -90,13 +100,18 @@ func (rp *runPeriod) getDateToCompare(s gbt.StrategyHandler) (time.Time, bool) {
 	}
 
 	history := data.History()
-	// no more elemtns in history, so nothing to compare
-	if len(history) <= 1 {
-		return time.Time{}, false
+
+	// with multiple symbols, the events before can share the same timestamp,
+	// and the history can already hold events after the current date,
+	// walk the history back to the last date before the current date
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Time().Before(now) {
+			return history[i].Time(), true
+		}
 	}
-	dateToCompare := history[len(history)-2].Time()
 
-	return dateToCompare, true
+	// no earlier date in history, so nothing to compare
+	return time.Time{}, false
 }
 
 func runPeriodWithOptions(opt ...string) runPeriod {
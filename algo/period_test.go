@@ -11,16 +11,41 @@ import (
 
 func TestAlgoRunOnce(t *testing.T) {
 	algo := RunOnce()
+	strategy := &gbt.Strategy{}
 
-	ok, err := algo.Run(&gbt.Strategy{})
+	ok, err := algo.Run(strategy)
 	if (ok == false) || (err != nil) {
 		t.Errorf("first RunOnce(): \nexpected %v %#v, \nactual   %v %#v", true, nil, ok, err)
 	}
 
-	ok, err = algo.Run(&gbt.Strategy{})
+	ok, err = algo.Run(strategy)
 	if (ok == true) || (err != nil) {
 		t.Errorf("second RunOnce(): \nexpected %v %#v, \nactual   %v %#v", false, nil, ok, err)
 	}
+
+	// another strategy node has its own state
+	ok, err = algo.Run(&gbt.Strategy{})
+	if (ok == false) || (err != nil) {
+		t.Errorf("RunOnce() on other strategy: \nexpected %v %#v, \nactual   %v %#v", true, nil, ok, err)
+	}
+
+	// another symbol has its own state
+	event := &gbt.Bar{}
+	event.SetSymbol("TEST")
+	strategy.SetEvent(event)
+	ok, err = algo.Run(strategy)
+	if (ok == false) || (err != nil) {
+		t.Errorf("RunOnce() on other symbol: \nexpected %v %#v, \nactual   %v %#v", true, nil, ok, err)
+	}
+
+	// a reset strategy runs the algo once again
+	strategy.SetAlgo(algo)
+	strategy.Reset()
+	strategy.SetEvent(event)
+	ok, err = algo.Run(strategy)
+	if (ok == false) || (err != nil) {
+		t.Errorf("RunOnce() after reset: \nexpected %v %#v, \nactual   %v %#v", true, nil, ok, err)
+	}
 }
 
 func TestRunPeriodWithOptions(t *testing.T) {
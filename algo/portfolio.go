@@ -1,6 +1,9 @@
 package algo
 
 import (
+	"fmt"
+	"sort"
+
 	gbt "github.com/dirkolbrich/gobacktest"
 )
 
@@ -75,3 +78,99 @@ func (algo notInvestedAlgo) Run(s gbt.StrategyHandler) (bool, error) {
 
 	return !invested, nil
 }
+
+// rebalanceAlgo turns the weights of the strategy into target signals.
+type rebalanceAlgo struct {
+	gbt.Algo
+	targeted map[scope]map[string]bool
+}
+
+// Rebalance creates a target weight signal for every weight set on the strategy.
+// The weights are scaled to the capital of the strategy relative to the portfolio value.
+// Symbols targeted by an earlier rebalance, which have no weight anymore, are closed.
+func Rebalance() gbt.AlgoHandler {
+	return &rebalanceAlgo{}
+}
+
+// Run runs the algo, returns false if no weights are set.
+func (algo *rebalanceAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	current, ok := weightsOf(s)
+	if !ok {
+		return false, nil
+	}
+
+	// copy the weights of the strategy, symbols to close are added with weight 0
+	weights := make(map[string]float64, len(current))
+	for symbol, weight := range current {
+		weights[symbol] = weight
+	}
+
+	portfolio, ok := s.Portfolio()
+	if !ok {
+		return false, fmt.Errorf("no portfolio to rebalance")
+	}
+
+	// scale the weights to the share of the strategy in the portfolio
+	scale := 1.0
+	if capital := capital(s); (capital > 0) && (portfolio.Value() > 0) {
+		scale = capital / portfolio.Value()
+	}
+
+	var event gbt.EventHandler
+	if slice, ok := dataSlice(s); ok {
+		event = slice
+	} else if e, ok := s.Event(); ok {
+		event = e
+	}
+	if event == nil {
+		return false, nil
+	}
+
+	// symbols targeted by this strategy node, the rebalance covers all symbols of the node
+	if algo.targeted == nil {
+		algo.targeted = make(map[scope]map[string]bool)
+	}
+	key := scope{node: s}
+	targeted, ok := algo.targeted[key]
+	if !ok {
+		targeted = make(map[string]bool)
+		algo.targeted[key] = targeted
+	}
+
+	// close symbols without weight
+	for symbol := range targeted {
+		if _, ok := weights[symbol]; !ok {
+			weights[symbol] = 0
+		}
+	}
+
+	var symbols []string
+	for symbol := range weights {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		e := &gbt.Event{}
+		e.SetTime(event.Time())
+		e.SetSymbol(symbol)
+
+		if err := s.AddSignal(gbt.NewTargetWeight(e, weights[symbol]*scale)); err != nil {
+			return false, err
+		}
+
+		if weights[symbol] == 0 {
+			delete(targeted, symbol)
+			continue
+		}
+		targeted[symbol] = true
+	}
+
+	return true, nil
+}
+
+// Reset implements Reseter to forget all targeted symbols.
+func (algo *rebalanceAlgo) Reset() error {
+	algo.targeted = nil
+	return nil
+}
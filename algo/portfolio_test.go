@@ -0,0 +1,100 @@
+package algo
+
+import (
+	"testing"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+func TestRebalance(t *testing.T) {
+	_, slice := testHelperMockPrices(map[string][]float64{
+		"A": {10},
+		"B": {20},
+	})
+
+	portfolio := gbt.NewPortfolio()
+	portfolio.SetCash(10000)
+
+	strategy := gbt.NewStrategy("test")
+	strategy.SetPortfolio(portfolio)
+	strategy.SetDataSlice(slice)
+
+	rebalance := Rebalance()
+
+	// no weights set
+	ok, err := rebalance.Run(strategy)
+	if ok || err != nil {
+		t.Errorf("Rebalance() without weights: \nexpected %v %v, \nactual   %v %v", false, nil, ok, err)
+	}
+
+	// strategy holds half of the portfolio value
+	strategy.SetCapital(5000)
+	strategy.SetWeights(map[string]float64{"A": 0.5, "B": 0.5})
+	ok, err = rebalance.Run(strategy)
+	signals, _ := strategy.Signals()
+	if !ok || err != nil || len(signals) != 2 {
+		t.Fatalf("Rebalance(): \nexpected %v %v %v signals, \nactual   %v %v %v", true, nil, 2, ok, err, len(signals))
+	}
+	for _, signal := range signals {
+		weight, _ := signal.(gbt.TargetEvent).TargetWeight()
+		if weight != 0.25 {
+			t.Errorf("Rebalance() %v: expected weight %v, actual %v", signal.Symbol(), 0.25, weight)
+		}
+	}
+
+	// B drops out of the weights and is closed
+	strategy.OnDataSlice(slice) // empty the signals of the last run
+	strategy.SetCapital(5000)
+	weights := map[string]float64{"A": 1}
+	strategy.SetWeights(weights)
+	rebalance.Run(strategy)
+	signals, _ = strategy.Signals()
+
+	// the weights of the strategy are not changed by the close out
+	if _, ok := weights["B"]; ok || (len(weights) != 1) {
+		t.Errorf("Rebalance() weights of the strategy: \nexpected %v, \nactual   %v", map[string]float64{"A": 1}, weights)
+	}
+
+	var closed bool
+	for _, signal := range signals {
+		weight, _ := signal.(gbt.TargetEvent).TargetWeight()
+		if signal.Symbol() == "B" && weight == 0 {
+			closed = true
+		}
+	}
+	if !closed {
+		t.Errorf("Rebalance() dropped symbol: expected target weight 0 for B, actual %v", signals)
+	}
+}
+
+// testSliceStrategy hides all optional interfaces of the wrapped strategy but the DataSlicer and Weigher.
+type testSliceStrategy struct {
+	gbt.StrategyHandler
+	gbt.DataSlicer
+	gbt.Weigher
+}
+
+func TestRebalanceWithoutAllocator(t *testing.T) {
+	_, slice := testHelperMockPrices(map[string][]float64{
+		"A": {10},
+	})
+
+	portfolio := gbt.NewPortfolio()
+	portfolio.SetCash(10000)
+
+	strategy := gbt.NewStrategy("test")
+	strategy.SetPortfolio(portfolio)
+	strategy.SetDataSlice(slice)
+	strategy.SetCapital(5000)
+	strategy.SetWeights(map[string]float64{"A": 0.5})
+
+	// the weights are not scaled without the capital of the strategy
+	ok, err := Rebalance().Run(testSliceStrategy{strategy, strategy, strategy})
+	signals, _ := strategy.Signals()
+	if !ok || err != nil || len(signals) != 1 {
+		t.Fatalf("Rebalance() without Allocator: \nexpected %v %v %v signals, \nactual   %v %v %v", true, nil, 1, ok, err, len(signals))
+	}
+	if weight, _ := signals[0].(gbt.TargetEvent).TargetWeight(); weight != 0.5 {
+		t.Errorf("Rebalance() without Allocator: \nexpected %v, \nactual   %v", 0.5, weight)
+	}
+}
@@ -0,0 +1,192 @@
+package algo
+
+import (
+	"fmt"
+	"sort"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// selectAllAlgo selects all symbols with data in the current data slice.
+type selectAllAlgo struct {
+	gbt.Algo
+}
+
+// SelectAll selects all symbols of the current data slice.
+// Without a data slice, it selects the symbol of the current data event.
+func SelectAll() gbt.AlgoHandler {
+	return &selectAllAlgo{}
+}
+
+// Run runs the algo, returns false if there is nothing to select.
+func (algo selectAllAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	symbols := available(s)
+	if len(symbols) == 0 {
+		return false, nil
+	}
+
+	if err := setSelected(s, symbols...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// selectNAlgo selects the first n symbols sorted by a metric.
+type selectNAlgo struct {
+	gbt.Algo
+	n          int
+	metric     string
+	descending bool
+}
+
+// SelectN selects n symbols of the current selection, sorted by a metric of their data event.
+// The metric has to be stored on the data event before, e.g. by an indicator algo.
+// With descending true the symbols with the highest metric values are selected.
+func SelectN(n int, metric string, descending bool) gbt.AlgoHandler {
+	return &selectNAlgo{n: n, metric: metric, descending: descending}
+}
+
+// Run runs the algo, returns false if no symbol has the metric.
+func (algo selectNAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	if algo.n <= 0 {
+		return false, fmt.Errorf("invalid number %v of symbols to select", algo.n)
+	}
+
+	type candidate struct {
+		symbol string
+		value  float64
+	}
+
+	var candidates []candidate
+	for _, symbol := range universe(s) {
+		event, ok := eventOf(s, symbol)
+		if !ok {
+			continue
+		}
+		value, ok := event.Get(algo.metric)
+		if !ok {
+			continue
+		}
+		candidates = append(candidates, candidate{symbol: symbol, value: value})
+	}
+
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if algo.descending {
+			return candidates[i].value > candidates[j].value
+		}
+		return candidates[i].value < candidates[j].value
+	})
+
+	if len(candidates) > algo.n {
+		candidates = candidates[:algo.n]
+	}
+
+	var symbols []string
+	for _, c := range candidates {
+		symbols = append(symbols, c.symbol)
+	}
+	sort.Strings(symbols)
+
+	if err := setSelected(s, symbols...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// selectWhereAlgo selects all symbols for which a condition algo is true.
+type selectWhereAlgo struct {
+	gbt.Algo
+	condition gbt.AlgoHandler
+}
+
+// SelectWhere selects the symbols of the current selection for which the condition algo returns true.
+// The condition runs once for each symbol with the strategy event set to the data event of this symbol.
+func SelectWhere(condition gbt.AlgoHandler) gbt.AlgoHandler {
+	return &selectWhereAlgo{condition: condition}
+}
+
+// Run runs the algo, returns false if no symbol matches the condition.
+func (algo selectWhereAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	// restore the original event after iterating the symbols
+	if event, ok := s.Event(); ok {
+		defer s.SetEvent(event)
+	}
+
+	var symbols []string
+	for _, symbol := range universe(s) {
+		event, ok := eventOf(s, symbol)
+		if !ok {
+			continue
+		}
+		s.SetEvent(event)
+
+		ok, err := algo.condition.Run(s)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	if err := setSelected(s, symbols...); err != nil {
+		return false, err
+	}
+	if len(symbols) == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Reset implements Reseter to reset the condition algo.
+func (algo selectWhereAlgo) Reset() error {
+	return reset(algo.condition)
+}
+
+// available returns the symbols with data for the current run of the strategy.
+func available(s gbt.StrategyHandler) []string {
+	if slice, ok := dataSlice(s); ok {
+		return slice.Symbols()
+	}
+
+	if event, ok := s.Event(); ok {
+		return []string{event.Symbol()}
+	}
+
+	return nil
+}
+
+// universe returns the current selection of the strategy, if nothing is selected all available symbols.
+func universe(s gbt.StrategyHandler) []string {
+	if selected, ok := selected(s); ok {
+		return selected
+	}
+
+	return available(s)
+}
+
+// eventOf returns the current data event of a symbol.
+func eventOf(s gbt.StrategyHandler, symbol string) (gbt.DataEvent, bool) {
+	if slice, ok := dataSlice(s); ok {
+		if event, ok := slice.Get(symbol); ok {
+			return event, true
+		}
+	}
+
+	if event, ok := s.Event(); ok && event.Symbol() == symbol {
+		return event, true
+	}
+
+	if data, ok := s.Data(); ok {
+		if event := data.Latest(symbol); event != nil {
+			return event, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,101 @@
+package algo
+
+import (
+	"reflect"
+	"testing"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+func TestSelectAll(t *testing.T) {
+	_, slice := testHelperMockPrices(map[string][]float64{
+		"B": {10, 11},
+		"A": {20, 21},
+	})
+
+	strategy := &gbt.Strategy{}
+	strategy.SetDataSlice(slice)
+
+	ok, err := SelectAll().Run(strategy)
+	selected, _ := strategy.Selected()
+	if exp := []string{"A", "B"}; !ok || err != nil || !reflect.DeepEqual(selected, exp) {
+		t.Errorf("SelectAll(): \nexpected %v %v %v, \nactual   %v %v %v", true, nil, exp, ok, err, selected)
+	}
+}
+
+func TestSelectN(t *testing.T) {
+	_, slice := testHelperMockPrices(map[string][]float64{
+		"A": {10},
+		"B": {20},
+		"C": {30},
+		"D": {40},
+	})
+	for i, event := range slice.Events() {
+		event.Add("momentum", float64([]int{3, 1, 4, 2}[i]))
+	}
+
+	var testCases = []struct {
+		msg        string
+		n          int
+		metric     string
+		descending bool
+		expOk      bool
+		expErr     bool
+		expSymbols []string
+	}{
+		{"test top 2 descending:", 2, "momentum", true, true, false, []string{"A", "C"}},
+		{"test bottom 2 ascending:", 2, "momentum", false, true, false, []string{"B", "D"}},
+		{"test n larger than universe:", 10, "momentum", true, true, false, []string{"A", "B", "C", "D"}},
+		{"test unknown metric:", 2, "unknown", true, false, false, nil},
+		{"test zero n:", 0, "momentum", true, false, true, nil},
+		{"test negative n:", -1, "momentum", true, false, true, nil},
+	}
+
+	for _, tc := range testCases {
+		strategy := &gbt.Strategy{}
+		strategy.SetDataSlice(slice)
+
+		ok, err := SelectN(tc.n, tc.metric, tc.descending).Run(strategy)
+		selected, _ := strategy.Selected()
+		if (ok != tc.expOk) || ((err != nil) != tc.expErr) || !reflect.DeepEqual(selected, tc.expSymbols) {
+			t.Errorf("%v SelectN(%v, %v, %v): \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.n, tc.metric, tc.descending, tc.expOk, tc.expSymbols, ok, selected)
+		}
+	}
+}
+
+// priceAbove is a condition algo, which is true if the price of the current event is above a value.
+type priceAbove struct {
+	gbt.Algo
+	value float64
+}
+
+func (a priceAbove) Run(s gbt.StrategyHandler) (bool, error) {
+	event, _ := s.Event()
+	return event.Price() > a.value, nil
+}
+
+func TestSelectWhere(t *testing.T) {
+	_, slice := testHelperMockPrices(map[string][]float64{
+		"A": {10},
+		"B": {20},
+		"C": {30},
+	})
+
+	strategy := &gbt.Strategy{}
+	strategy.SetDataSlice(slice)
+
+	// select from the existing selection
+	strategy.SetSelected("A", "C")
+
+	ok, err := SelectWhere(&priceAbove{value: 15}).Run(strategy)
+	selected, _ := strategy.Selected()
+	if exp := []string{"C"}; !ok || err != nil || !reflect.DeepEqual(selected, exp) {
+		t.Errorf("SelectWhere(): \nexpected %v %v %v, \nactual   %v %v %v", true, nil, exp, ok, err, selected)
+	}
+
+	ok, err = SelectWhere(&priceAbove{value: 50}).Run(strategy)
+	if ok || err != nil {
+		t.Errorf("SelectWhere() no match: \nexpected %v %v, \nactual   %v %v", false, nil, ok, err)
+	}
+}
@@ -0,0 +1,49 @@
+package algo
+
+import (
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// forEachAlgo runs a stack of algos for every data event of the current data slice.
+type forEachAlgo struct {
+	gbt.Algo
+	algos []gbt.AlgoHandler
+}
+
+// ForEach runs the given algos once for every data event of the current data slice.
+// Before each run the event of the strategy is set to the data event of the symbol,
+// so per event algos like SMA or CreateSignal can be used on a data slice.
+func ForEach(algos ...gbt.AlgoHandler) gbt.AlgoHandler {
+	return &forEachAlgo{algos: algos}
+}
+
+// Run runs the algo, returns false if no data slice is available.
+func (algo forEachAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	slice, ok := dataSlice(s)
+	if !ok {
+		return false, nil
+	}
+
+	// restore the original event after iterating the data slice
+	if event, ok := s.Event(); ok {
+		defer s.SetEvent(event)
+	}
+
+	for _, event := range slice.Events() {
+		s.SetEvent(event)
+
+		// a failing algo, e.g. with too few data points, only stops the run for this symbol
+		for _, a := range algo.algos {
+			if ok, _ := a.Run(s); !ok {
+				break
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Reset implements Reseter to reset all wrapped algos.
+func (algo forEachAlgo) Reset() error {
+	return reset(algo.algos...)
+}
@@ -0,0 +1,54 @@
+package algo
+
+import (
+	"testing"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// symbolRecorder records the symbol of every event it runs on.
+type symbolRecorder struct {
+	gbt.Algo
+	symbols *[]string
+}
+
+func (sr symbolRecorder) Run(s gbt.StrategyHandler) (bool, error) {
+	event, _ := s.Event()
+	*sr.symbols = append(*sr.symbols, event.Symbol())
+	return true, nil
+}
+
+func TestForEach(t *testing.T) {
+	timestamp, _ := time.Parse("2006-01-02", "2018-07-01")
+
+	var events []gbt.DataEvent
+	for _, symbol := range []string{"TEST.B", "TEST.A"} {
+		bar := &gbt.Bar{}
+		bar.SetSymbol(symbol)
+		bar.SetTime(timestamp)
+		events = append(events, bar)
+	}
+
+	strategy := &gbt.Strategy{}
+
+	// no data slice set
+	var symbols []string
+	ok, err := ForEach(&symbolRecorder{symbols: &symbols}).Run(strategy)
+	if ok || err != nil || len(symbols) != 0 {
+		t.Errorf("ForEach() without data slice: \nexpected %v %v %v, \nactual   %v %v %v", false, nil, 0, ok, err, len(symbols))
+	}
+
+	strategy.SetEvent(events[0])
+	strategy.SetDataSlice(gbt.NewDataSlice(events...))
+
+	ok, err = ForEach(&symbolRecorder{symbols: &symbols}).Run(strategy)
+	if !ok || err != nil || len(symbols) != 2 || symbols[0] != "TEST.A" || symbols[1] != "TEST.B" {
+		t.Errorf("ForEach(): \nexpected %v %v %v, \nactual   %v %v %v", true, nil, []string{"TEST.A", "TEST.B"}, ok, err, symbols)
+	}
+
+	// original event is restored
+	if event, _ := strategy.Event(); event != events[0] {
+		t.Errorf("ForEach() event not restored: \nexpected %#v, \nactual   %#v", events[0], event)
+	}
+}
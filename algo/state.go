@@ -0,0 +1,164 @@
+package algo
+
+import (
+	"fmt"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// scope identifies the state of an algo for a single symbol within a single strategy node.
+// An algo instance can be used by several strategies and for several symbols,
+// so any state it keeps between runs has to be stored per scope.
+type scope struct {
+	node   gbt.StrategyHandler
+	symbol string
+}
+
+// scopeOf returns the scope of the current run of a strategy.
+func scopeOf(s gbt.StrategyHandler) scope {
+	var symbol string
+	if event, ok := s.Event(); ok {
+		symbol = event.Symbol()
+	}
+
+	return scope{node: s, symbol: symbol}
+}
+
+// reset resets all algos, which implement the gbt.Reseter interface.
+// It is used by algos which wrap other algos to pass a reset on.
+func reset(algos ...gbt.AlgoHandler) error {
+	for _, algo := range algos {
+		if r, ok := algo.(gbt.Reseter); ok {
+			if err := r.Reset(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dataSlice returns the data slice of the current run of a strategy, false if the strategy does not carry a data slice.
+func dataSlice(s gbt.StrategyHandler) (*gbt.DataSlice, bool) {
+	slicer, ok := s.(gbt.DataSlicer)
+	if !ok {
+		return nil, false
+	}
+	return slicer.DataSlice()
+}
+
+// selected returns the symbols selected on a strategy, false if the strategy does not carry a selection.
+func selected(s gbt.StrategyHandler) ([]string, bool) {
+	selector, ok := s.(gbt.Selector)
+	if !ok {
+		return nil, false
+	}
+	return selector.Selected()
+}
+
+// setSelected sets the selected symbols of a strategy, it returns an error if the strategy does not carry a selection.
+func setSelected(s gbt.StrategyHandler, symbols ...string) error {
+	selector, ok := s.(gbt.Selector)
+	if !ok {
+		return fmt.Errorf("strategy does not implement the Selector interface")
+	}
+	selector.SetSelected(symbols...)
+	return nil
+}
+
+// weightsOf returns the target weights of a strategy, false if the strategy does not carry weights.
+func weightsOf(s gbt.StrategyHandler) (map[string]float64, bool) {
+	weigher, ok := s.(gbt.Weigher)
+	if !ok {
+		return nil, false
+	}
+	return weigher.Weights()
+}
+
+// setWeights sets the target weights of a strategy, it returns an error if the strategy does not carry weights.
+func setWeights(s gbt.StrategyHandler, weights map[string]float64) error {
+	weigher, ok := s.(gbt.Weigher)
+	if !ok {
+		return fmt.Errorf("strategy does not implement the Weigher interface")
+	}
+	weigher.SetWeights(weights)
+	return nil
+}
+
+// capital returns the capital allocated to a strategy, 0 if the strategy does not take part in the allocation.
+func capital(s gbt.StrategyHandler) float64 {
+	allocator, ok := s.(gbt.Allocator)
+	if !ok {
+		return 0
+	}
+	return allocator.Capital()
+}
+
+// now returns the time of the current run of a strategy,
+// the time of the data slice or else the time of the data event.
+func now(s gbt.StrategyHandler) (time.Time, bool) {
+	// get current data slice date
+	if slice, ok := dataSlice(s); ok {
+		return slice.Time(), true
+	}
+
+	// get current data event date
+	event, ok := s.Event()
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return event.Time(), true
+}
+
+// history keeps the last values of an algo for each scope, one value per timestamp.
+// Running an algo twice for the same timestamp replaces the value instead of adding a new one.
+type history struct {
+	size   int
+	series map[scope]*series
+}
+
+// series is the value history of a single scope.
+type series struct {
+	time   time.Time
+	values []float64
+}
+
+// newHistory creates a history, which keeps the last size values.
+func newHistory(size int) *history {
+	return &history{size: size}
+}
+
+// add adds the value of the current run and returns the history of the scope, from the oldest to the newest value.
+func (h *history) add(s gbt.StrategyHandler, value float64) []float64 {
+	if h.series == nil {
+		h.series = make(map[scope]*series)
+	}
+
+	key := scopeOf(s)
+	ser, ok := h.series[key]
+	if !ok {
+		ser = &series{}
+		h.series[key] = ser
+	}
+
+	t, _ := now(s)
+	if (len(ser.values) > 0) && t.Equal(ser.time) {
+		ser.values[len(ser.values)-1] = value
+		return ser.values
+	}
+
+	ser.time = t
+	ser.values = append(ser.values, value)
+	if len(ser.values) > h.size {
+		ser.values = ser.values[len(ser.values)-h.size:]
+	}
+
+	return ser.values
+}
+
+// reset forgets the history of all scopes.
+func (h *history) reset() {
+	h.series = nil
+}
@@ -0,0 +1,65 @@
+package algo
+
+import (
+	"reflect"
+	"testing"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+func TestAlgoStatePerSymbol(t *testing.T) {
+	// A is rising, B is falling, so the short sma is only above the long sma for A
+	data, _ := testHelperMockPrices(map[string][]float64{
+		"A": {1, 2, 3, 4, 5},
+		"B": {5, 4, 3, 2, 1},
+	})
+
+	short, long := SMA(1), SMA(2)
+	cross := BiggerThan(short, long)
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+
+	run := func() map[string][]bool {
+		results := make(map[string][]bool)
+		data.Reset()
+		for event, ok := data.Next(); ok; event, ok = data.Next() {
+			strategy.SetEvent(event)
+			if ok, _ := cross.Run(strategy); ok || (long.Value() != 0) {
+				results[event.Symbol()] = append(results[event.Symbol()], ok)
+			}
+		}
+		return results
+	}
+
+	expected := map[string][]bool{
+		"A": {true, true, true, true},
+		"B": {false, false, false, false},
+	}
+
+	if results := run(); !reflect.DeepEqual(results, expected) {
+		t.Errorf("testing cross per symbol: \nexpected %v, \nactual   %v", expected, results)
+	}
+
+	// a reset is passed on to the wrapped algos
+	wrapped := If(cross, CreateSignal("buy"))
+	if err := wrapped.(gbt.Reseter).Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if short.Value() != 0 || long.Value() != 0 {
+		t.Errorf("testing reset of wrapped algos: \nexpected %v %v, \nactual   %v %v", 0, 0, short.Value(), long.Value())
+	}
+
+	// the same algos run on a second strategy node with its own state
+	other := &gbt.Strategy{}
+	other.SetData(data)
+	data.Reset()
+	for event, ok := data.Next(); ok; event, ok = data.Next() {
+		strategy.SetEvent(event)
+		other.SetEvent(event)
+		short.Run(strategy)
+		long.Run(other)
+	}
+	if short.Value() != 1 || long.Value() != 1.5 {
+		t.Errorf("testing state per strategy: \nexpected %v %v, \nactual   %v %v", 1, 1.5, short.Value(), long.Value())
+	}
+}
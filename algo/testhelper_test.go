@@ -32,3 +32,33 @@ func testHelperMockData(dates []string) []gbt.DataEvent {
 	}
 	return mockdata
 }
+
+// testHelperMockPrices creates a data handler with a price series for each symbol and
+// pulls all events from the stream. It returns the data handler and the data slice of the last date.
+func testHelperMockPrices(prices map[string][]float64) (*gbt.Data, *gbt.DataSlice) {
+	start, _ := time.Parse("2006-01-02", "2018-07-01")
+
+	var stream []gbt.DataEvent
+	for symbol, series := range prices {
+		for i, price := range series {
+			bar := &gbt.Bar{Close: price, Metric: gbt.Metric{}}
+			bar.SetSymbol(symbol)
+			bar.SetTime(start.AddDate(0, 0, i))
+			stream = append(stream, bar)
+		}
+	}
+
+	data := &gbt.Data{}
+	data.SetStream(stream)
+	data.SortStream()
+
+	slice := &gbt.DataSlice{}
+	for event, ok := data.Next(); ok; event, ok = data.Next() {
+		if !event.Time().Equal(slice.Time()) {
+			slice = gbt.NewDataSlice()
+		}
+		slice.Add(event)
+	}
+
+	return data, slice
+}
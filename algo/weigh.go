@@ -0,0 +1,361 @@
+package algo
+
+import (
+	"fmt"
+	"math"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// weighEquallyAlgo sets equal weights for all selected symbols.
+type weighEquallyAlgo struct {
+	gbt.Algo
+}
+
+// WeighEqually sets an equal weight for each selected symbol.
+func WeighEqually() gbt.AlgoHandler {
+	return &weighEquallyAlgo{}
+}
+
+// Run runs the algo, returns false if no symbol is selected.
+func (algo weighEquallyAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	symbols := universe(s)
+	if len(symbols) == 0 {
+		return false, nil
+	}
+
+	weights := make(map[string]float64)
+	for _, symbol := range symbols {
+		weights[symbol] = 1 / float64(len(symbols))
+	}
+
+	if err := setWeights(s, weights); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// weighSpecifiedAlgo sets fixed weights.
+type weighSpecifiedAlgo struct {
+	gbt.Algo
+	weights map[string]float64
+}
+
+// WeighSpecified sets the given fixed weights, regardless of the selection.
+func WeighSpecified(weights map[string]float64) gbt.AlgoHandler {
+	return &weighSpecifiedAlgo{weights: weights}
+}
+
+// Run runs the algo, returns false if no weights are specified.
+func (algo weighSpecifiedAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	if len(algo.weights) == 0 {
+		return false, nil
+	}
+
+	weights := make(map[string]float64)
+	for symbol, weight := range algo.weights {
+		weights[symbol] = weight
+	}
+
+	if err := setWeights(s, weights); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// weighInvVolAlgo sets weights inverse proportional to the volatility of each symbol.
+type weighInvVolAlgo struct {
+	gbt.Algo
+	lookback int
+}
+
+// WeighInvVol sets the weight of each selected symbol inverse proportional to the
+// standard deviation of its returns over the lookback period.
+func WeighInvVol(lookback int) gbt.AlgoHandler {
+	return &weighInvVolAlgo{lookback: lookback}
+}
+
+// Run runs the algo.
+func (algo weighInvVolAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	symbols := universe(s)
+	if len(symbols) == 0 {
+		return false, nil
+	}
+
+	inverse := make([]float64, len(symbols))
+	for i, symbol := range symbols {
+		r, err := returns(s, symbol, algo.lookback)
+		if err != nil {
+			return false, err
+		}
+
+		vol := stat.StdDev(r, nil)
+		if vol == 0 || math.IsNaN(vol) {
+			return false, fmt.Errorf("invalid volatility for %v", symbol)
+		}
+		inverse[i] = 1 / vol
+	}
+
+	weights, err := normalize(symbols, inverse)
+	if err != nil {
+		return false, err
+	}
+
+	if err := setWeights(s, weights); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// weighMeanVarAlgo sets the weights of the maximum sharpe ratio portfolio.
+type weighMeanVarAlgo struct {
+	gbt.Algo
+	lookback int
+	riskfree float64
+}
+
+// WeighMeanVar sets the weights of the long only portfolio with the maximum sharpe ratio,
+// based on the mean and covariance of the returns over the lookback period.
+// The riskfree rate is given per period of the returns.
+func WeighMeanVar(lookback int, riskfree float64) gbt.AlgoHandler {
+	return &weighMeanVarAlgo{lookback: lookback, riskfree: riskfree}
+}
+
+// Run runs the algo.
+func (algo weighMeanVarAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	symbols := universe(s)
+	if len(symbols) == 0 {
+		return false, nil
+	}
+
+	cov, means, err := covariance(s, symbols, algo.lookback)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range means {
+		means[i] -= algo.riskfree
+	}
+
+	w, err := meanVarWeights(cov, means)
+	if err != nil {
+		return false, err
+	}
+
+	weights, err := normalize(symbols, w)
+	if err != nil {
+		return false, err
+	}
+
+	if err := setWeights(s, weights); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// weighERCAlgo sets the weights of the equal risk contribution portfolio.
+type weighERCAlgo struct {
+	gbt.Algo
+	lookback int
+}
+
+// WeighERC sets the weights of the equal risk contribution (risk parity) portfolio,
+// where each selected symbol contributes the same amount of risk to the portfolio,
+// based on the covariance of the returns over the lookback period.
+func WeighERC(lookback int) gbt.AlgoHandler {
+	return &weighERCAlgo{lookback: lookback}
+}
+
+// Run runs the algo.
+func (algo weighERCAlgo) Run(s gbt.StrategyHandler) (bool, error) {
+	symbols := universe(s)
+	if len(symbols) == 0 {
+		return false, nil
+	}
+
+	cov, _, err := covariance(s, symbols, algo.lookback)
+	if err != nil {
+		return false, err
+	}
+
+	w, err := ercWeights(cov)
+	if err != nil {
+		return false, err
+	}
+
+	weights, err := normalize(symbols, w)
+	if err != nil {
+		return false, err
+	}
+
+	if err := setWeights(s, weights); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// returns calculates the simple returns of a symbol over the lookback period.
+func returns(s gbt.StrategyHandler, symbol string, lookback int) ([]float64, error) {
+	data, ok := s.Data()
+	if !ok {
+		return nil, fmt.Errorf("no data for %v", symbol)
+	}
+
+	list := data.List(symbol)
+	if (lookback < 2) || (len(list) < lookback+1) {
+		return nil, fmt.Errorf("invalid value length for returns of %v", symbol)
+	}
+	list = list[len(list)-lookback-1:]
+
+	r := make([]float64, lookback)
+	for i := 1; i < len(list); i++ {
+		last := list[i-1].Price()
+		if last == 0 {
+			return nil, fmt.Errorf("invalid price for returns of %v", symbol)
+		}
+		r[i-1] = (list[i].Price() - last) / last
+	}
+
+	return r, nil
+}
+
+// covariance returns the covariance matrix and the mean returns of the symbols over the lookback period.
+func covariance(s gbt.StrategyHandler, symbols []string, lookback int) (*mat.SymDense, []float64, error) {
+	series := mat.NewDense(lookback, len(symbols), nil)
+	means := make([]float64, len(symbols))
+
+	for j, symbol := range symbols {
+		r, err := returns(s, symbol, lookback)
+		if err != nil {
+			return nil, nil, err
+		}
+		series.SetCol(j, r)
+		means[j] = stat.Mean(r, nil)
+	}
+
+	cov := mat.NewSymDense(len(symbols), nil)
+	stat.CovarianceMatrix(cov, series, nil)
+
+	return cov, means, nil
+}
+
+// meanVarWeights solves for the long only tangency portfolio.
+// Symbols with a negative weight are removed and the problem is solved again for the remaining symbols.
+func meanVarWeights(cov *mat.SymDense, excess []float64) ([]float64, error) {
+	n := len(excess)
+	active := make([]bool, n)
+	for i := range active {
+		active[i] = true
+	}
+
+	for {
+		var index []int
+		for i, a := range active {
+			if a {
+				index = append(index, i)
+			}
+		}
+		if len(index) == 0 {
+			return nil, fmt.Errorf("no positive excess return for mean variance weights")
+		}
+
+		// reduced covariance matrix and excess returns of the active symbols
+		sub := mat.NewSymDense(len(index), nil)
+		mu := mat.NewVecDense(len(index), nil)
+		for a, i := range index {
+			mu.SetVec(a, excess[i])
+			for b, j := range index {
+				sub.SetSym(a, b, cov.At(i, j))
+			}
+		}
+
+		var chol mat.Cholesky
+		if ok := chol.Factorize(sub); !ok {
+			return nil, fmt.Errorf("covariance matrix is not positive definite")
+		}
+
+		// w = cov^-1 * mu
+		var w mat.VecDense
+		if err := chol.SolveVecTo(&w, mu); err != nil {
+			return nil, err
+		}
+
+		weights := make([]float64, n)
+		negative := false
+		for a, i := range index {
+			weights[i] = w.AtVec(a)
+			if weights[i] <= 0 {
+				active[i] = false
+				negative = true
+			}
+		}
+
+		if !negative {
+			return weights, nil
+		}
+	}
+}
+
+// ercWeights calculates the equal risk contribution weights with cyclical coordinate descent.
+// It minimises 1/2 y'*cov*y - b'*ln(y) with the risk budget b_i = 1/n, which keeps y positive even
+// with negative correlations. normalize scales y to the weights with a sum of 1.
+func ercWeights(cov *mat.SymDense) ([]float64, error) {
+	n, _ := cov.Dims()
+	budget := 1 / float64(n)
+
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		if cov.At(i, i) <= 0 {
+			return nil, fmt.Errorf("invalid covariance matrix for erc weights")
+		}
+		y[i] = 1 / math.Sqrt(cov.At(i, i))
+	}
+
+	for iteration := 0; iteration < 10000; iteration++ {
+		for i := 0; i < n; i++ {
+			// solve cov_ii * y_i^2 + c * y_i - b_i = 0 for the positive root, with c the covariance to the other symbols
+			var c float64
+			for j := 0; j < n; j++ {
+				if j != i {
+					c += cov.At(i, j) * y[j]
+				}
+			}
+			y[i] = (-c + math.Sqrt(c*c+4*cov.At(i, i)*budget)) / (2 * cov.At(i, i))
+		}
+
+		// the risk contribution y_i * (cov * y)_i of each symbol equals the budget at the optimum
+		var maxDiff float64
+		for i := 0; i < n; i++ {
+			var sigma float64
+			for j := 0; j < n; j++ {
+				sigma += cov.At(i, j) * y[j]
+			}
+			maxDiff = math.Max(maxDiff, math.Abs(y[i]*sigma-budget))
+		}
+		if maxDiff < 1e-12 {
+			return y, nil
+		}
+	}
+
+	return nil, fmt.Errorf("erc weights did not converge")
+}
+
+// normalize maps the values to the symbols, scaled to a sum of 1.
+func normalize(symbols []string, values []float64) (map[string]float64, error) {
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	if !(total > 0) || math.IsInf(total, 1) {
+		return nil, fmt.Errorf("invalid sum %v of weights", total)
+	}
+
+	weights := make(map[string]float64)
+	for i, symbol := range symbols {
+		weights[symbol] = values[i] / total
+	}
+
+	return weights, nil
+}
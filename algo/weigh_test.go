@@ -0,0 +1,175 @@
+package algo
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestWeighEqually(t *testing.T) {
+	strategy := &gbt.Strategy{}
+	strategy.SetSelected("A", "B", "C", "D")
+
+	ok, err := WeighEqually().Run(strategy)
+	weights, _ := strategy.Weights()
+	if !ok || err != nil || len(weights) != 4 || weights["C"] != 0.25 {
+		t.Errorf("WeighEqually(): \nexpected %v %v %v, \nactual   %v %v %v", true, nil, 0.25, ok, err, weights)
+	}
+}
+
+func TestWeighSpecified(t *testing.T) {
+	strategy := &gbt.Strategy{}
+
+	ok, err := WeighSpecified(map[string]float64{"A": 0.6, "B": 0.4}).Run(strategy)
+	weights, _ := strategy.Weights()
+	if !ok || err != nil || weights["A"] != 0.6 || weights["B"] != 0.4 {
+		t.Errorf("WeighSpecified(): \nexpected %v %v %v %v, \nactual   %v %v %v", true, nil, 0.6, 0.4, ok, err, weights)
+	}
+}
+
+func TestWeighWithoutWeigher(t *testing.T) {
+	strategy := gbt.NewStrategy("test")
+
+	// the wrapper hides the Weigher of the strategy
+	wrapper := struct{ gbt.StrategyHandler }{strategy}
+	ok, err := WeighSpecified(map[string]float64{"A": 1}).Run(wrapper)
+	expErr := fmt.Errorf("strategy does not implement the Weigher interface")
+	if ok || !reflect.DeepEqual(err, expErr) {
+		t.Errorf("WeighSpecified() without Weigher: \nexpected %v %v, \nactual   %v %v", false, expErr, ok, err)
+	}
+}
+
+// testWeighPrices has two price series, where B has about twice the volatility of A.
+var testWeighPrices = map[string][]float64{
+	"A": {100, 101, 100, 101, 100, 101, 100},
+	"B": {100, 102, 100, 102, 100, 102, 100},
+}
+
+func TestWeighInvVol(t *testing.T) {
+	data, slice := testHelperMockPrices(testWeighPrices)
+
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+	strategy.SetDataSlice(slice)
+	strategy.SetSelected("A", "B")
+
+	ok, err := WeighInvVol(6).Run(strategy)
+	weights, _ := strategy.Weights()
+	if !ok || err != nil || math.Abs(weights["A"]-2*weights["B"]) > 0.05 || math.Abs(weights["A"]+weights["B"]-1) > 1e-9 {
+		t.Errorf("WeighInvVol(): \nexpected A about twice B, \nactual   %v %v %v", ok, err, weights)
+	}
+
+	// not enough data
+	ok, err = WeighInvVol(20).Run(strategy)
+	if ok || err == nil {
+		t.Errorf("WeighInvVol() too few data points: \nexpected %v error, \nactual   %v %v", false, ok, err)
+	}
+}
+
+func TestWeighERC(t *testing.T) {
+	data, slice := testHelperMockPrices(testWeighPrices)
+
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+	strategy.SetDataSlice(slice)
+	strategy.SetSelected("A", "B")
+
+	ok, err := WeighERC(6).Run(strategy)
+	weights, _ := strategy.Weights()
+
+	// compare the risk contributions w_i * (cov * w)_i of both symbols
+	cov, _, _ := covariance(strategy, []string{"A", "B"}, 6)
+	wA, wB := weights["A"], weights["B"]
+	rcA := wA * (cov.At(0, 0)*wA + cov.At(0, 1)*wB)
+	rcB := wB * (cov.At(1, 0)*wA + cov.At(1, 1)*wB)
+
+	if !ok || err != nil || math.Abs(rcA-rcB) > 1e-9 || math.Abs(wA+wB-1) > 1e-9 {
+		t.Errorf("WeighERC(): \nexpected equal risk contribution, \nactual   %v %v %v %v %v", ok, err, weights, rcA, rcB)
+	}
+}
+
+func TestErcWeights(t *testing.T) {
+	var testCases = []struct {
+		msg string
+		cov []float64
+	}{
+		{"testing uncorrelated symbols", []float64{1, 0, 0, 0.25}},
+		{"testing positive correlation", []float64{1, 0.09, 0.09, 0.01}},
+		{"testing negative correlation", []float64{1, -0.09, -0.09, 0.01}},
+	}
+
+	for _, tc := range testCases {
+		cov := mat.NewSymDense(2, tc.cov)
+		w, err := ercWeights(cov)
+		if err != nil {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, nil, err)
+			continue
+		}
+
+		// both symbols have a positive weight and the same risk contribution w_i * (cov * w)_i
+		rcA := w[0] * (cov.At(0, 0)*w[0] + cov.At(0, 1)*w[1])
+		rcB := w[1] * (cov.At(1, 0)*w[0] + cov.At(1, 1)*w[1])
+		if !(w[0] > 0) || !(w[1] > 0) || math.Abs(rcA-rcB) > 1e-9 {
+			t.Errorf("%v: \nexpected equal risk contribution, \nactual   %v %v %v", tc.msg, w, rcA, rcB)
+		}
+	}
+
+	// a symbol without variance has no equal risk contribution weight
+	if _, err := ercWeights(mat.NewSymDense(2, []float64{1, 0, 0, 0})); err == nil {
+		t.Errorf("testing symbol without variance: \nexpected %v, \nactual   %v", "error", err)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	var testCases = []struct {
+		msg        string
+		values     []float64
+		expWeights map[string]float64
+		expErr     bool
+	}{
+		{"testing positive values", []float64{3, 1}, map[string]float64{"A": 0.75, "B": 0.25}, false},
+		{"testing zero values", []float64{0, 0}, nil, true},
+		{"testing negative sum", []float64{1, -2}, nil, true},
+		{"testing NaN values", []float64{math.NaN(), 1}, nil, true},
+	}
+
+	for _, tc := range testCases {
+		weights, err := normalize([]string{"A", "B"}, tc.values)
+		if ((err != nil) != tc.expErr) || !reflect.DeepEqual(weights, tc.expWeights) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v", tc.msg, tc.expWeights, tc.expErr, weights, err)
+		}
+	}
+}
+
+func TestWeighMeanVar(t *testing.T) {
+	data, slice := testHelperMockPrices(map[string][]float64{
+		"A": {100, 102, 103, 105, 106, 108, 109},
+		"B": {100, 101, 100, 101, 100, 101, 100},
+		"C": {100, 99, 98, 97, 96, 95, 94},
+	})
+
+	strategy := &gbt.Strategy{}
+	strategy.SetData(data)
+	strategy.SetDataSlice(slice)
+	strategy.SetSelected("A", "B", "C")
+
+	ok, err := WeighMeanVar(6, 0).Run(strategy)
+	weights, _ := strategy.Weights()
+
+	var total float64
+	for _, w := range weights {
+		if w < 0 {
+			t.Errorf("WeighMeanVar(): expected long only weights, actual %v", weights)
+		}
+		total += w
+	}
+
+	// the falling symbol C has a negative excess return and is excluded
+	if !ok || err != nil || weights["C"] != 0 || weights["A"] <= 0 || math.Abs(total-1) > 1e-9 {
+		t.Errorf("WeighMeanVar(): \nexpected %v %v, \nactual   %v %v %v", true, nil, ok, err, weights)
+	}
+}
@@ -0,0 +1,257 @@
+package gobacktest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Allocator defines the capital allocation of a strategy node within the tree.
+// It is not part of StrategyHandler, a strategy implementing it takes part in the allocation of its parent.
+type Allocator interface {
+	Capital() float64
+	SetCapital(float64)
+	Value() float64
+	ProfitLoss() float64
+	Performance() []NodePerformance
+}
+
+// NodePerformance records the capital, value and profit/loss of a strategy node at a point in time.
+type NodePerformance struct {
+	Time       time.Time
+	Capital    float64
+	Value      float64
+	ProfitLoss float64
+}
+
+// Capital returns the capital allocated to this strategy.
+func (s *Strategy) Capital() float64 {
+	return s.capital
+}
+
+// SetCapital sets the capital allocated to this strategy.
+func (s *Strategy) SetCapital(c float64) {
+	s.capital = c
+}
+
+// Value returns the current market value of all positions held by the assets of this strategy and its sub strategies.
+func (s *Strategy) Value() float64 {
+	var value float64
+
+	if assets, ok := s.Assets(); ok {
+		for _, asset := range assets {
+			pos, _ := s.position(asset.Name())
+			value += float64(pos.qty) * pos.marketPrice
+		}
+	}
+
+	if strategies, ok := s.Strategies(); ok {
+		for _, strategy := range strategies {
+			if a, ok := strategy.(Allocator); ok {
+				value += a.Value()
+			}
+		}
+	}
+
+	return value
+}
+
+// ProfitLoss returns the total profit/loss of all positions held by the assets of this strategy and its sub strategies.
+func (s *Strategy) ProfitLoss() float64 {
+	var pnl float64
+
+	if assets, ok := s.Assets(); ok {
+		for _, asset := range assets {
+			pos, _ := s.position(asset.Name())
+			pnl += pos.totalProfitLoss
+		}
+	}
+
+	if strategies, ok := s.Strategies(); ok {
+		for _, strategy := range strategies {
+			if a, ok := strategy.(Allocator); ok {
+				pnl += a.ProfitLoss()
+			}
+		}
+	}
+
+	return pnl
+}
+
+// Performance returns the tracked performance history of this strategy node.
+func (s *Strategy) Performance() []NodePerformance {
+	return s.performance
+}
+
+// allocate sets the capital of the root strategy to the portfolio value
+// and hands down the weighted capital to each sub strategy.
+func (s *Strategy) allocate() {
+	if s.Root() {
+		if portfolio, ok := s.Portfolio(); ok {
+			s.SetCapital(portfolio.Value())
+		}
+	}
+
+	if strategies, ok := s.Strategies(); ok {
+		for _, strategy := range strategies {
+			a, ok := strategy.(Allocator)
+			if !ok {
+				continue
+			}
+			var weight float64
+			if w, ok := strategy.(WeightHandler); ok {
+				weight = w.Weight()
+			}
+			a.SetCapital(weight * s.capital)
+		}
+	}
+}
+
+// checkAssets returns an error, if a symbol is held by more than one node of the strategy tree.
+// The nodes value their assets by the shared position of the portfolio,
+// a symbol of two nodes would be counted twice by their parent.
+func (s *Strategy) checkAssets(nodes map[string]string) error {
+	if assets, ok := s.Assets(); ok {
+		for _, asset := range assets {
+			if node, ok := nodes[asset.Name()]; ok {
+				return fmt.Errorf("asset %v of strategy %v is already held by strategy %v", asset.Name(), s.Name(), node)
+			}
+			nodes[asset.Name()] = s.Name()
+		}
+	}
+
+	children, _ := s.Children()
+	for _, child := range children {
+		if strategy, ok := child.(*Strategy); ok {
+			if err := strategy.checkAssets(nodes); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// rebalance creates a target signal with the target qty for each child asset.
+// It only rebalances, if any asset drifted from its target weight by more than its tolerance.
+func (s *Strategy) rebalance() []SignalEvent {
+	targets, ok := s.targets()
+	if !ok || !s.drifted() {
+		return nil
+	}
+
+	var symbols []string
+	for symbol := range targets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var signals []SignalEvent
+	for _, symbol := range symbols {
+		event := &Event{timestamp: s.dataSlice.Time(), symbol: symbol}
+		signals = append(signals, NewTargetQty(event, targets[symbol]))
+	}
+
+	return signals
+}
+
+// targets returns the target qty of each child asset, based on the asset weight and the capital of the strategy.
+// A strategy only allocates, if any of its assets has a weight set.
+func (s *Strategy) targets() (map[string]int64, bool) {
+	assets, ok := s.Assets()
+	if !ok || s.capital == 0 {
+		return nil, false
+	}
+
+	var allocating bool
+	for _, asset := range assets {
+		if asset.Weight() != 0 {
+			allocating = true
+		}
+	}
+	if !allocating {
+		return nil, false
+	}
+
+	targets := make(map[string]int64)
+	for _, asset := range assets {
+		price, ok := s.price(asset.Name())
+		if !ok || price == 0 {
+			continue
+		}
+		// weight * capital / price, truncated towards zero
+		targets[asset.Name()] = int64(asset.Weight() * s.capital / price)
+	}
+
+	return targets, true
+}
+
+// drifted checks if the current weight of any child asset is outside of its tolerance band.
+func (s *Strategy) drifted() bool {
+	assets, ok := s.Assets()
+	if !ok || s.capital == 0 {
+		return false
+	}
+
+	for _, asset := range assets {
+		price, ok := s.price(asset.Name())
+		if !ok {
+			continue
+		}
+		pos, _ := s.position(asset.Name())
+		weight := float64(pos.qty) * price / s.capital
+
+		if math.Abs(weight-asset.Weight()) > asset.Tolerance() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// track appends the current performance of this strategy node to its history.
+func (s *Strategy) track(t time.Time) {
+	s.performance = append(s.performance, NodePerformance{
+		Time:       t,
+		Capital:    s.capital,
+		Value:      s.Value(),
+		ProfitLoss: s.ProfitLoss(),
+	})
+}
+
+// position returns the portfolio position of a symbol.
+func (s *Strategy) position(symbol string) (Position, bool) {
+	portfolio, ok := s.Portfolio()
+	if !ok {
+		return Position{}, false
+	}
+
+	return portfolio.IsInvested(symbol)
+}
+
+// price returns the current price of a symbol, first from the data slice, then from the data handler.
+func (s *Strategy) price(symbol string) (float64, bool) {
+	if s.dataSlice != nil {
+		if event, ok := s.dataSlice.Get(symbol); ok {
+			return event.Price(), true
+		}
+	}
+
+	if s.data != nil {
+		if event := s.data.Latest(symbol); event != nil {
+			return event.Price(), true
+		}
+	}
+
+	return 0, false
+}
+
+// sortSellsFirst sorts sell and exit signals before all other signals, keeping their order otherwise.
+func sortSellsFirst(signals []SignalEvent) {
+	sort.SliceStable(signals, func(i, j int) bool {
+		sellI := (signals[i].Direction() == SLD) || (signals[i].Direction() == EXT)
+		sellJ := (signals[j].Direction() == SLD) || (signals[j].Direction() == EXT)
+		return sellI && !sellJ
+	})
+}
@@ -0,0 +1,180 @@
+package gobacktest
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testAllocationSlice returns a data slice with the given symbol prices.
+func testAllocationSlice(prices map[string]float64) *DataSlice {
+	timestamp := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	slice := &DataSlice{}
+	for symbol, price := range prices {
+		slice.Add(&Bar{Event: Event{timestamp: timestamp, symbol: symbol}, Close: price})
+	}
+	return slice
+}
+
+// testAllocationSignals converts signals into a comparable string representation.
+func testAllocationSignals(signals []SignalEvent) []string {
+	var result []string
+	for _, signal := range signals {
+		qty, _ := signal.(TargetEvent).TargetQty()
+		result = append(result, fmt.Sprintf("%v %v", signal.Symbol(), qty))
+	}
+	return result
+}
+
+func TestStrategyAllocation(t *testing.T) {
+	prices := map[string]float64{"X": 10, "Y": 20, "Z": 50}
+
+	var testCases = []struct {
+		msg        string
+		portfolio  *Portfolio
+		tolerance  float64
+		weights    map[string]float64
+		expSignals []string
+	}{
+		{"test allocation of empty portfolio:",
+			&Portfolio{cash: 10000},
+			0,
+			map[string]float64{"X": 1, "Y": 0.5, "Z": 0.5},
+			[]string{"X 600", "Y 100", "Z 40"},
+		},
+		{"test drift within tolerance:",
+			&Portfolio{cash: 100, holdings: map[string]Position{
+				"X": {qty: 590, marketPrice: 10, marketValue: 5900},
+				"Y": {qty: 100, marketPrice: 20, marketValue: 2000},
+				"Z": {qty: 40, marketPrice: 50, marketValue: 2000},
+			}},
+			0.05,
+			map[string]float64{"X": 1, "Y": 0.5, "Z": 0.5},
+			nil,
+		},
+		{"test close unweighted asset:",
+			&Portfolio{cash: 6000, holdings: map[string]Position{
+				"Y": {qty: 200, marketPrice: 20, marketValue: 4000},
+			}},
+			0,
+			map[string]float64{"X": 1, "Y": 0, "Z": 1},
+			[]string{"X 600", "Y 0", "Z 80"},
+		},
+	}
+
+	for _, tc := range testCases {
+		assetX := NewAsset("X")
+		assetX.SetWeight(tc.weights["X"])
+		assetX.SetTolerance(tc.tolerance)
+		subA := NewStrategy("subA")
+		subA.SetWeight(0.6)
+		subA.SetChildren(assetX)
+
+		assetY := NewAsset("Y")
+		assetY.SetWeight(tc.weights["Y"])
+		assetY.SetTolerance(tc.tolerance)
+		assetZ := NewAsset("Z")
+		assetZ.SetWeight(tc.weights["Z"])
+		assetZ.SetTolerance(tc.tolerance)
+		subB := NewStrategy("subB")
+		subB.SetWeight(0.4)
+		subB.SetChildren(assetY, assetZ)
+
+		root := NewStrategy("root")
+		root.SetChildren(subA, subB)
+		root.SetPortfolio(tc.portfolio)
+
+		signals, err := root.OnDataSlice(testAllocationSlice(prices))
+		if err != nil {
+			t.Errorf("%v OnDataSlice(): unexpected error %v", tc.msg, err)
+		}
+
+		result := testAllocationSignals(signals)
+		if !reflect.DeepEqual(result, tc.expSignals) {
+			t.Errorf("%v OnDataSlice(): \nexpected %v, \nactual   %v", tc.msg, tc.expSignals, result)
+		}
+
+		if (root.Capital() != 10000) || (subA.Capital() != 6000) || (subB.Capital() != 4000) {
+			t.Errorf("%v Capital(): \nexpected %v %v %v, \nactual   %v %v %v",
+				tc.msg, 10000, 6000, 4000, root.Capital(), subA.Capital(), subB.Capital())
+		}
+	}
+}
+
+func TestStrategyProfitLoss(t *testing.T) {
+	portfolio := &Portfolio{holdings: map[string]Position{
+		"X": {qty: 100, marketPrice: 12, totalProfitLoss: 200},
+		"Y": {qty: 0, totalProfitLoss: -50},
+	}}
+
+	subA := NewStrategy("subA")
+	subA.SetChildren(NewAsset("X"))
+	subB := NewStrategy("subB")
+	subB.SetChildren(NewAsset("Y"))
+	root := NewStrategy("root")
+	root.SetChildren(subA, subB)
+	root.SetPortfolio(portfolio)
+
+	if pnl := subA.ProfitLoss(); pnl != 200 {
+		t.Errorf("ProfitLoss() subA: expected %v, actual %v", 200, pnl)
+	}
+	if pnl := subB.ProfitLoss(); pnl != -50 {
+		t.Errorf("ProfitLoss() subB: expected %v, actual %v", -50, pnl)
+	}
+	if pnl := root.ProfitLoss(); pnl != 150 {
+		t.Errorf("ProfitLoss() root: expected %v, actual %v", 150, pnl)
+	}
+	if value := root.Value(); value != 1200 {
+		t.Errorf("Value() root: expected %v, actual %v", 1200, value)
+	}
+
+	root.OnDataSlice(testAllocationSlice(map[string]float64{"X": 12}))
+	perf := subA.Performance()
+	if len(perf) != 1 || perf[0].ProfitLoss != 200 || perf[0].Value != 1200 {
+		t.Errorf("Performance() subA: \nexpected %v, \nactual   %+v", 1, perf)
+	}
+}
+
+func TestStrategyDuplicateAssets(t *testing.T) {
+	var testCases = []struct {
+		msg    string
+		subA   []string
+		subB   []string
+		expErr error
+	}{
+		{"test distinct assets:",
+			[]string{"X"},
+			[]string{"Y", "Z"},
+			nil,
+		},
+		{"test asset held by two sub strategies:",
+			[]string{"X", "Y"},
+			[]string{"Y"},
+			fmt.Errorf("asset Y of strategy subB is already held by strategy subA"),
+		},
+		{"test asset held twice by one strategy:",
+			[]string{"X", "X"},
+			[]string{"Y"},
+			fmt.Errorf("asset X of strategy subA is already held by strategy subA"),
+		},
+	}
+
+	for _, tc := range testCases {
+		subA := NewStrategy("subA")
+		for _, symbol := range tc.subA {
+			subA.SetChildren(append(subA.children, NewAsset(symbol))...)
+		}
+		subB := NewStrategy("subB")
+		for _, symbol := range tc.subB {
+			subB.SetChildren(append(subB.children, NewAsset(symbol))...)
+		}
+		root := NewStrategy("root")
+		root.SetChildren(subA, subB)
+
+		err := root.SetPortfolio(&Portfolio{})
+		if !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("%v SetPortfolio(): \nexpected %v, \nactual   %v", tc.msg, tc.expErr, err)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package gobacktest
 
+import (
+	"sort"
+	"time"
+)
+
 // DP sets the the precision of rounded floating numbers
 // used after calculations to format
 const DP = 4 // DP
@@ -17,7 +22,12 @@ type Backtest struct {
 	portfolio  PortfolioHandler
 	exchange   ExecutionHandler
 	statistic  StatisticHandler
+	breaker    BreakerHandler
 	eventQueue []EventHandler
+	dataSlice  *DataSlice // data slice of the current timestamp
+	latest     DataEvent  // latest data event of the current timestamp
+	risks      int        // number of risk decisions passed to the statistic
+	start      time.Time  // start of trading, earlier data events only warm up the strategy
 }
 
 // New creates a default backtest with sensible defaults ready for use.
@@ -27,6 +37,7 @@ func New() *Backtest {
 			initialCash: 100000,
 			sizeManager: &Size{DefaultSize: 100, DefaultValue: 1000},
 			riskManager: &Risk{},
+			rebalancer:  &Rebalancer{},
 		},
 		exchange: &Exchange{
 			Symbol:      "TEST",
@@ -67,11 +78,34 @@ func (t *Backtest) SetStatistic(statistic StatisticHandler) {
 	t.statistic = statistic
 }
 
+// SetBreaker sets the circuit breaker to be used within the backtest.
+func (t *Backtest) SetBreaker(breaker BreakerHandler) {
+	t.breaker = breaker
+}
+
+// SetStart sets the start of the backtest. Data events before the start warm up the strategy,
+// their signals are dropped and the portfolio, the exchange and the statistic are not updated.
+func (t *Backtest) SetStart(start time.Time) {
+	t.start = start
+}
+
 // Reset the backtest into a clean state with loaded data.
 func (t *Backtest) Reset() error {
 	t.eventQueue = nil
+	t.dataSlice = nil
+	t.latest = nil
+	t.risks = 0
 	t.data.Reset()
 	t.portfolio.Reset()
+	if r, ok := t.strategy.(Reseter); ok {
+		r.Reset()
+	}
+	if r, ok := t.exchange.(Reseter); ok {
+		r.Reset()
+	}
+	if t.breaker != nil {
+		t.breaker.Reset()
+	}
 	t.statistic.Reset()
 	return nil
 }
@@ -94,7 +128,7 @@ func (t *Backtest) Run() error {
 		// no event in the queue
 		if !ok {
 			// poll data stream
-			data, ok := t.data.Next()
+			data, ok := t.nextData()
 			// no more data, exit event loop
 			if !ok {
 				break
@@ -105,6 +139,11 @@ func (t *Backtest) Run() error {
 			continue
 		}
 
+		// data events before the start only warm up the strategy
+		if t.warmUp(event) {
+			continue
+		}
+
 		// processing event
 		err := t.eventLoop(event)
 		if err != nil {
@@ -145,7 +184,8 @@ func (t *Backtest) setup() error {
 
 // teardown performs any cleaning operations at the end of the backtest.
 func (t *Backtest) teardown() error {
-	// no implementation yet
+	// sample the statistics of the last timestamp
+	t.sample()
 	return nil
 }
 
@@ -163,37 +203,164 @@ func (t *Backtest) nextEvent() (e EventHandler, ok bool) {
 	return e, true
 }
 
+// nextData polls the data stream for the next event. If the next data event of the stream has a new
+// timestamp, the collected data slice of the current timestamp is returned first, before the data
+// handler moves on to the next timestamp.
+func (t *Backtest) nextData() (e EventHandler, ok bool) {
+	// release the complete data slice of the current timestamp
+	if t.dataSlice != nil {
+		stream := t.data.Stream()
+		if (len(stream) == 0) || !stream[0].Time().Equal(t.dataSlice.Time()) {
+			e, t.dataSlice = t.dataSlice, nil
+			return e, true
+		}
+	}
+
+	data, ok := t.data.Next()
+	// no more data
+	if !ok {
+		return e, false
+	}
+
+	// first data event of a timestamp
+	if t.dataSlice == nil {
+		t.dataSlice = NewDataSlice(data)
+		return data, true
+	}
+
+	t.dataSlice.Add(data)
+	return data, true
+}
+
+// queueSignals adds the signals of the strategy to the event queue. Target signals are bundled
+// and passed to the portfolio, which diffs them against its holdings and creates the orders.
+func (t *Backtest) queueSignals(signals []SignalEvent) {
+	var targets []TargetEvent
+	for _, signal := range signals {
+		if target, ok := signal.(TargetEvent); ok {
+			targets = append(targets, target)
+			continue
+		}
+		t.eventQueue = append(t.eventQueue, signal)
+	}
+
+	if len(targets) == 0 {
+		return
+	}
+
+	targeter, ok := t.portfolio.(OnTargeter)
+	if !ok {
+		t.rejectTargets(targets, "portfolio does not handle target signals")
+		return
+	}
+
+	orders, err := targeter.OnTargets(targets, t.data)
+	t.trackRisk()
+	if err != nil {
+		t.rejectTargets(targets, "targets: "+err.Error())
+		return
+	}
+	for _, order := range orders {
+		t.eventQueue = append(t.eventQueue, order)
+	}
+}
+
+// rejectTargets tracks the target signals, which could not be turned into orders, as rejected by the statistic.
+func (t *Backtest) rejectTargets(targets []TargetEvent, reason string) {
+	tracker, ok := t.statistic.(RiskTracker)
+	if !ok {
+		return
+	}
+	for _, target := range targets {
+		qty, _ := target.TargetQty()
+		tracker.TrackRisk(RiskDecision{
+			Time:      target.Time(),
+			Symbol:    target.Symbol(),
+			Direction: target.Direction(),
+			Qty:       qty,
+			Reason:    reason,
+		})
+	}
+}
+
+// warmUp passes a data event or data slice before the start of the backtest to the strategy
+// and drops its signals. It returns false for all events from the start on.
+func (t *Backtest) warmUp(e EventHandler) bool {
+	if t.start.IsZero() || !e.Time().Before(t.start) {
+		return false
+	}
+
+	switch event := e.(type) {
+	case DataEvent:
+		t.strategy.OnData(event)
+	case *DataSlice:
+		if slicer, ok := t.strategy.(OnDataSlicer); ok {
+			slicer.OnDataSlice(event)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
 // eventLoop directs the different events to their handler.
 func (t *Backtest) eventLoop(e EventHandler) error {
 	// type check for event type
 	switch event := e.(type) {
 	case DataEvent:
+		// sample the statistics of the previous timestamp after all its events are processed
+		if (t.latest != nil) && !event.Time().Equal(t.latest.Time()) {
+			t.sample()
+		}
+		t.latest = event
+
 		// update portfolio to the last known price data
 		t.portfolio.Update(event)
 		// update statistics
 		t.statistic.Update(event, t.portfolio)
+		// feed the circuit breaker with the equity curve
+		t.updateBreaker()
 		// check if any orders are filled before proceding
-		t.exchange.OnData(event)
+		fills, _ := t.exchange.OnData(event, t.data)
+		for _, fill := range fills {
+			t.eventQueue = append(t.eventQueue, fill)
+		}
 
 		// run strategy with this data event
 		signals, err := t.strategy.OnData(event)
 		if err != nil {
 			break
 		}
-		for _, signal := range signals {
-			t.eventQueue = append(t.eventQueue, signal)
+		t.queueSignals(signals)
+
+	case *DataSlice:
+		// run strategy with all data events of this timestamp
+		slicer, ok := t.strategy.(OnDataSlicer)
+		if !ok {
+			break
+		}
+		signals, err := slicer.OnDataSlice(event)
+		if err != nil {
+			break
 		}
+		t.queueSignals(signals)
 
 	case *Signal:
 		order, err := t.portfolio.OnSignal(event, t.data)
+		t.trackRisk()
 		if err != nil {
 			break
 		}
 		t.eventQueue = append(t.eventQueue, order)
 
 	case *Order:
+		// new entries are blocked by a tripped circuit breaker
+		if t.blocked(event) {
+			break
+		}
 		fill, err := t.exchange.OnOrder(event, t.data)
-		if err != nil {
+		// resting orders are filled later by the exchange
+		if (err != nil) || (fill == nil) {
 			break
 		}
 		t.eventQueue = append(t.eventQueue, fill)
@@ -208,3 +375,126 @@ func (t *Backtest) eventLoop(e EventHandler) error {
 
 	return nil
 }
+
+// sample records the equity point of the latest timestamp again, after the fills of the timestamp are processed.
+func (t *Backtest) sample() {
+	if t.latest == nil {
+		return
+	}
+	if sampler, ok := t.statistic.(EquitySampler); ok {
+		sampler.Sample(t.latest, t.portfolio)
+		return
+	}
+	t.statistic.Update(t.latest, t.portfolio)
+}
+
+// trackRisk passes the new decisions of the risk manager of the portfolio to the statistic.
+func (t *Backtest) trackRisk() {
+	risker, ok := t.portfolio.(Risker)
+	if !ok {
+		return
+	}
+	recorder, ok := risker.RiskManager().(RiskRecorder)
+	if !ok {
+		return
+	}
+	tracker, ok := t.statistic.(RiskTracker)
+	if !ok {
+		return
+	}
+
+	decisions := recorder.Decisions()
+	if t.risks > len(decisions) {
+		t.risks = 0
+	}
+	for _, d := range decisions[t.risks:] {
+		tracker.TrackRisk(d)
+	}
+	t.risks = len(decisions)
+}
+
+// updateBreaker passes the last equity point of the statistic to the circuit breaker.
+// If the breaker trips with flatten, orders to close all positions are added to the event queue.
+func (t *Backtest) updateBreaker() {
+	if t.breaker == nil {
+		return
+	}
+	curver, ok := t.statistic.(EquityCurver)
+	if !ok {
+		return
+	}
+	curve := curver.EquityCurve()
+	if len(curve) == 0 {
+		return
+	}
+
+	event, ok := t.breaker.Update(curve[len(curve)-1])
+	if !ok {
+		return
+	}
+	if tracker, ok := t.statistic.(BreakerTracker); ok {
+		tracker.TrackBreaker(event)
+	}
+
+	holder, ok := t.portfolio.(Holder)
+	if !event.Flatten || !ok {
+		return
+	}
+	holdings := holder.Holdings()
+	symbols := make([]string, 0, len(holdings))
+	for symbol := range holdings {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		pos := holdings[symbol]
+		if pos.qty == 0 {
+			continue
+		}
+		t.eventQueue = append(t.eventQueue, &Order{
+			Event:     Event{timestamp: event.Time, symbol: symbol},
+			direction: exitDirection(pos.qty),
+			orderType: MarketOrder,
+			qty:       abs(pos.qty),
+		})
+	}
+}
+
+// blocked checks an order against a tripped circuit breaker. Orders opening or increasing a position
+// are scaled down to the part closing the position or blocked, the decision is tracked by the statistic.
+func (t *Backtest) blocked(order *Order) bool {
+	if t.breaker == nil {
+		return false
+	}
+	reason, ok := t.breaker.Blocked()
+	if !ok {
+		return false
+	}
+
+	var current int64
+	if pos, ok := t.portfolio.IsInvested(order.Symbol()); ok {
+		current = pos.qty
+	}
+	qty := capQty(current, order, 0)
+	if qty >= order.Qty() {
+		return false
+	}
+
+	if tracker, ok := t.statistic.(RiskTracker); ok {
+		tracker.TrackRisk(RiskDecision{
+			Time:      order.Time(),
+			Symbol:    order.Symbol(),
+			Direction: order.Direction(),
+			Qty:       order.Qty(),
+			Approved:  qty,
+			Reason:    "circuit breaker: " + reason,
+		})
+	}
+
+	if qty == 0 {
+		return true
+	}
+	order.SetQty(qty)
+	return false
+}
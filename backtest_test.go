@@ -1,6 +1,8 @@
 package gobacktest
 
 import (
+	"math"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -45,3 +47,367 @@ func TestNextEvent(t *testing.T) {
 		}
 	}
 }
+
+func TestNextData(t *testing.T) {
+	day1 := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	data := &Data{}
+	data.SetStream([]DataEvent{
+		&Bar{Event: Event{timestamp: day1, symbol: "TEST.A"}},
+		&Bar{Event: Event{timestamp: day1, symbol: "TEST.B"}},
+		&Bar{Event: Event{timestamp: day2, symbol: "TEST.A"}},
+	})
+	test := &Backtest{data: data}
+
+	// expected sequence of events, a data slice is released after each timestamp
+	var exp = []struct {
+		slice   bool
+		time    time.Time
+		symbols int
+	}{
+		{false, day1, 0},
+		{false, day1, 0},
+		{true, day1, 2},
+		{false, day2, 0},
+		{true, day2, 1},
+	}
+
+	for i, e := range exp {
+		event, ok := test.nextData()
+		if !ok {
+			t.Fatalf("nextData() %d: expected event, got none", i)
+		}
+		slice, isSlice := event.(*DataSlice)
+		if (isSlice != e.slice) || !event.Time().Equal(e.time) || (isSlice && slice.Len() != e.symbols) {
+			t.Errorf("nextData() %d: \nexpected slice %v %v, \nactual   slice %v %v", i, e.slice, e.time, isSlice, event.Time())
+		}
+		// the data slice is released before the data handler moves on to the next timestamp
+		if isSlice && !data.History()[len(data.History())-1].Time().Equal(slice.Time()) {
+			t.Errorf("nextData() %d: \nexpected latest data %v, \nactual   %v", i, slice.Time(), data.History()[len(data.History())-1].Time())
+		}
+	}
+
+	if event, ok := test.nextData(); ok {
+		t.Errorf("nextData() on empty stream: expected none, actual %#v", event)
+	}
+}
+
+// testSliceLatestAlgo records the latest data event of each symbol of the data slice.
+type testSliceLatestAlgo struct {
+	Algo
+	latest *[]time.Time
+}
+
+func (a testSliceLatestAlgo) Run(s StrategyHandler) (bool, error) {
+	slice, _ := s.(DataSlicer).DataSlice()
+	data, _ := s.Data()
+	for _, symbol := range slice.Symbols() {
+		*a.latest = append(*a.latest, data.Latest(symbol).Time())
+	}
+	return true, nil
+}
+
+func TestBacktestRunDataSliceLatest(t *testing.T) {
+	day1 := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	data := &Data{}
+	data.SetStream([]DataEvent{
+		&Bar{Event: Event{timestamp: day1, symbol: "TEST.A"}, Close: 10, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day1, symbol: "TEST.B"}, Close: 10, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day2, symbol: "TEST.A"}, Close: 99, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day2, symbol: "TEST.B"}, Close: 11, Metric: Metric{}},
+	})
+
+	var latest []time.Time
+	strategy := NewStrategy("slice")
+	strategy.SetSliceAlgo(&testSliceLatestAlgo{latest: &latest})
+
+	test := New()
+	test.SetSymbols([]string{"TEST.A", "TEST.B"})
+	test.SetData(data)
+	test.SetStrategy(strategy)
+	if err := test.Run(); err != nil {
+		t.Fatalf("testing run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	// the data handler does not move on to the next timestamp before the data slice is processed
+	expLatest := []time.Time{day1, day1, day2, day2}
+	if !reflect.DeepEqual(latest, expLatest) {
+		t.Errorf("testing latest data event in OnDataSlice: \nexpected %v, \nactual   %v", expLatest, latest)
+	}
+}
+
+// testEventStrategy hides all methods of the strategy except the StrategyHandler, e.g. OnDataSlice.
+type testEventStrategy struct {
+	StrategyHandler
+}
+
+func TestBacktestRunEventStrategy(t *testing.T) {
+	day1 := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	data := &Data{}
+	data.SetStream([]DataEvent{
+		&Bar{Event: Event{timestamp: day1, symbol: "TEST.A"}, Close: 10, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day2, symbol: "TEST.A"}, Close: 10, Metric: Metric{}},
+	})
+
+	// a strategy without data slices still runs on every data event
+	test := New()
+	test.SetSymbols([]string{"TEST.A"})
+	test.SetData(data)
+	test.SetStrategy(&testEventStrategy{NewStrategy("buy").SetAlgo(&testBuyAlgo{})})
+	if err := test.Run(); err != nil {
+		t.Fatalf("testing run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	if fills := len(test.Stats().(*Statistic).Transactions()); fills != 2 {
+		t.Errorf("testing strategy without OnDataSlice: \nexpected %v, \nactual   %v", 2, fills)
+	}
+}
+
+// testBuyAlgo creates a buy signal for every data event.
+type testBuyAlgo struct {
+	Algo
+}
+
+func (a testBuyAlgo) Run(s StrategyHandler) (bool, error) {
+	event, _ := s.Event()
+	return true, s.AddSignal(&Signal{Event: Event{timestamp: event.Time(), symbol: event.Symbol()}, direction: BOT})
+}
+
+// testCountStatistic counts the updates of the statistic.
+type testCountStatistic struct {
+	*Statistic
+	updates int
+}
+
+func (s *testCountStatistic) Update(d DataEvent, p PortfolioHandler) {
+	s.updates++
+	s.Statistic.Update(d, p)
+}
+
+func TestBacktestRunEquityPoints(t *testing.T) {
+	day := func(i int) time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+
+	var stream []DataEvent
+	for i := 0; i < 3; i++ {
+		stream = append(stream,
+			&Bar{Event: Event{timestamp: day(i), symbol: "A"}, Close: 10, Metric: Metric{}},
+			&Bar{Event: Event{timestamp: day(i), symbol: "B"}, Close: 20, Metric: Metric{}},
+		)
+	}
+	data := &Data{}
+	data.SetStream(stream)
+
+	statistic := &testCountStatistic{Statistic: &Statistic{}}
+	test := New()
+	test.SetSymbols([]string{"A", "B"})
+	test.SetData(data)
+	test.SetStrategy(NewStrategy("buy").SetAlgo(&testBuyAlgo{}))
+	test.SetStatistic(statistic)
+	if err := test.Run(); err != nil {
+		t.Fatalf("testing run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	// each equity point is taken after the fills of its timestamp
+	var points []interface{}
+	var expPoints []interface{}
+	cash := test.portfolio.InitialCash()
+	for i, e := range statistic.EquityCurve() {
+		for _, f := range statistic.Transactions() {
+			if f.Time().Equal(day(i)) && (f.Direction() == BOT) {
+				cash -= f.NetValue()
+			}
+		}
+		points = append(points, []interface{}{e.Time(), e.Cash(), e.LongPositions()})
+		expPoints = append(expPoints, []interface{}{day(i), math.Round(cash*math.Pow10(DP)) / math.Pow10(DP), 2})
+	}
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing equity points", points, expPoints},
+		{"testing transactions", len(statistic.Transactions()), 6},
+		{"testing one update per data event", statistic.updates, 6},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
+
+func TestBacktestRunBreaker(t *testing.T) {
+	day := func(i int) time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+
+	var stream []DataEvent
+	for i, price := range []float64{10, 10, 5} {
+		stream = append(stream, &Bar{Event: Event{timestamp: day(i), symbol: "A"}, Close: price, Metric: Metric{}})
+	}
+	data := &Data{}
+	data.SetStream(stream)
+
+	portfolio := NewPortfolio()
+	portfolio.SetInitialCash(3000)
+	test := New()
+	test.SetSymbols([]string{"A"})
+	test.SetData(data)
+	test.SetPortfolio(portfolio)
+	test.SetStrategy(NewStrategy("buy").SetAlgo(&testBuyAlgo{}))
+	test.SetBreaker(&CircuitBreaker{MaxDrawdown: 0.1})
+	if err := test.Run(); err != nil {
+		t.Fatalf("testing run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	// the drawdown of the last day blocks the buy order of the same day
+	stats := test.Stats().(*Statistic)
+	if (len(stats.Transactions()) != 2) || (len(stats.BreakerEvents()) != 1) || !stats.BreakerEvents()[0].Time.Equal(day(2)) {
+		t.Errorf("testing breaker at the timestamp of the drawdown: \nexpected %v %v, \nactual   %v %v",
+			2, day(2), len(stats.Transactions()), stats.BreakerEvents())
+	}
+}
+
+// testTargetAlgo creates a target signal for each symbol with the weight of the portfolio value.
+type testTargetAlgo struct {
+	Algo
+	symbols []string
+}
+
+func (a testTargetAlgo) Run(s StrategyHandler) (bool, error) {
+	event, _ := s.Event()
+	for _, symbol := range a.symbols {
+		target := NewTargetWeight(event, 0.5)
+		target.SetSymbol(symbol)
+		if err := s.AddSignal(target); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// testSignalPortfolio hides all methods of the portfolio except the PortfolioHandler, e.g. OnTargets.
+type testSignalPortfolio struct {
+	PortfolioHandler
+}
+
+func TestBacktestRunRejectedTargets(t *testing.T) {
+	var testCases = []struct {
+		msg          string
+		portfolio    PortfolioHandler
+		symbols      []string
+		expFills     int
+		expDecisions []RiskDecision
+	}{
+		{"testing portfolio with targets",
+			NewPortfolio(), []string{"A"},
+			1, nil,
+		},
+		{"testing portfolio without targets",
+			&testSignalPortfolio{NewPortfolio()}, []string{"A"},
+			0, []RiskDecision{
+				{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Symbol: "A", Direction: HLD, Reason: "portfolio does not handle target signals"},
+			},
+		},
+		{"testing target without data",
+			NewPortfolio(), []string{"A", "C"},
+			0, []RiskDecision{
+				{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Symbol: "A", Direction: HLD, Reason: "targets: cannot rebalance C: no price data"},
+				{Time: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Symbol: "C", Direction: HLD, Reason: "targets: cannot rebalance C: no price data"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		data := &Data{}
+		data.SetStream([]DataEvent{
+			&Bar{Event: Event{timestamp: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), symbol: "A"}, Close: 10, Metric: Metric{}},
+		})
+
+		test := New()
+		test.SetSymbols([]string{"A"})
+		test.SetData(data)
+		test.SetPortfolio(tc.portfolio)
+		test.SetStrategy(NewStrategy("targets").SetAlgo(&testTargetAlgo{symbols: tc.symbols}))
+		if err := test.Run(); err != nil {
+			t.Fatalf("%v: \nexpected %v, \nactual   %v", tc.msg, nil, err)
+		}
+
+		stats := test.Stats().(*Statistic)
+		if len(stats.Transactions()) != tc.expFills {
+			t.Errorf("%v fills: \nexpected %v, \nactual   %v", tc.msg, tc.expFills, len(stats.Transactions()))
+		}
+		if !reflect.DeepEqual(stats.RiskDecisions(), tc.expDecisions) {
+			t.Errorf("%v: \nexpected %+v, \nactual   %+v", tc.msg, tc.expDecisions, stats.RiskDecisions())
+		}
+	}
+}
+
+// testCountAlgo counts the data events seen by the strategy.
+type testCountAlgo struct {
+	Algo
+	events *int
+}
+
+func (a testCountAlgo) Run(s StrategyHandler) (bool, error) {
+	*a.events++
+	return true, nil
+}
+
+func TestBacktestRunStart(t *testing.T) {
+	day := func(i int) time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+
+	var stream []DataEvent
+	for i := 0; i < 4; i++ {
+		stream = append(stream, &Bar{Event: Event{timestamp: day(i), symbol: "A"}, Close: 10, Metric: Metric{}})
+	}
+	data := &Data{}
+	data.SetStream(stream)
+
+	var events int
+	test := New()
+	test.SetSymbols([]string{"A"})
+	test.SetData(data)
+	test.SetStrategy(NewStrategy("buy").SetAlgo(&testCountAlgo{events: &events}, &testBuyAlgo{}))
+	test.SetStart(day(2))
+	if err := test.Run(); err != nil {
+		t.Fatalf("testing run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	stats := test.Stats().(*Statistic)
+	var times []time.Time
+	for _, e := range stats.EquityCurve() {
+		times = append(times, e.Time())
+	}
+	var fills []time.Time
+	for _, f := range stats.Transactions() {
+		fills = append(fills, f.Time())
+	}
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing warm up of the strategy", events, 4},
+		{"testing equity from the start", times, []time.Time{day(2), day(3)}},
+		{"testing fills from the start", fills, []time.Time{day(2), day(3)}},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
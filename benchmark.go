@@ -0,0 +1,205 @@
+package gobacktest
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// Benchmarker compares the equity curve of a backtest with a benchmark from the data stream.
+type Benchmarker interface {
+	SetBenchmark(symbols ...string)
+	BenchmarkCurve() []EquityPoint
+	RelativeCurve() []EquityPoint
+	CompareBenchmark(PerformanceOptions) (BenchmarkComparison, error)
+}
+
+// BenchmarkComparison is the summary of the metrics relative to the benchmark.
+// Alpha, tracking error and information ratio are annualised with the number of periods per year.
+type BenchmarkComparison struct {
+	Symbols          []string
+	Periods          int     // number of returns compared with the benchmark
+	PeriodsPerYear   float64 // annualisation factor of the bar frequency
+	Return           float64 // total return of the equity curve over the compared periods
+	BenchmarkReturn  float64 // total return of the benchmark over the compared periods
+	Alpha            float64 // annualised Jensen's alpha against the risk free rate
+	Beta             float64
+	Correlation      float64
+	TrackingError    float64 // annualised standard deviation of the active returns
+	InformationRatio float64 // annualised mean active return divided by the tracking error
+	UpCapture        float64 // mean return divided by the mean benchmark return of the periods the benchmark rises
+	DownCapture      float64 // mean return divided by the mean benchmark return of the periods the benchmark falls
+}
+
+// SetBenchmark sets the symbols of the benchmark. The benchmark is an equal weighted buy and hold
+// of the symbols, which starts as soon as all symbols have a price in the data stream.
+// Any symbol, which is part of the data stream but not traded by the strategy, e.g. an index, can be used.
+func (s *Statistic) SetBenchmark(symbols ...string) {
+	s.benchmarkSymbols = symbols
+}
+
+// BenchmarkCurve returns the value of the benchmark, which starts at 1.
+func (s Statistic) BenchmarkCurve() []EquityPoint {
+	return s.benchmark
+}
+
+// RelativeCurve returns the equity curve relative to the benchmark, both starting at 1
+// at the first timestamp with a benchmark value. A value above 1 outperforms the benchmark.
+func (s Statistic) RelativeCurve() []EquityPoint {
+	points, bench := s.alignBenchmark()
+	if len(points) == 0 {
+		return nil
+	}
+
+	relative := make([]EquityPoint, len(points))
+	for i := range points {
+		value := (points[i].equity / points[0].equity) / (bench[i] / bench[0])
+		relative[i] = EquityPoint{timestamp: points[i].timestamp, equity: math.Round(value*math.Pow10(DP)) / math.Pow10(DP)}
+	}
+	return relative
+}
+
+// CompareBenchmark calculates the metrics of the equity curve relative to the benchmark.
+func (s Statistic) CompareBenchmark(opts PerformanceOptions) (BenchmarkComparison, error) {
+	points, bench := s.alignBenchmark()
+	if len(points) < 3 {
+		return BenchmarkComparison{}, errors.New("could not compare benchmark, not enough equity points with a benchmark")
+	}
+	if points[0].equity <= 0 {
+		return BenchmarkComparison{}, errors.New("could not compare benchmark, no initial equity")
+	}
+
+	periodsPerYear, err := periodsPerYear(opts, points)
+	if err != nil {
+		return BenchmarkComparison{}, err
+	}
+
+	c := BenchmarkComparison{
+		Symbols:         s.benchmarkSymbols,
+		Periods:         len(points) - 1,
+		PeriodsPerYear:  periodsPerYear,
+		Return:          points[len(points)-1].equity/points[0].equity - 1,
+		BenchmarkReturn: bench[len(bench)-1]/bench[0] - 1,
+	}
+
+	returns := make([]float64, c.Periods)
+	benchReturns := make([]float64, c.Periods)
+	excess := make([]float64, c.Periods)
+	benchExcess := make([]float64, c.Periods)
+	active := make([]float64, c.Periods)
+	var up, upBench, down, downBench []float64
+	rates := periodRates(opts, periodsPerYear)
+	for i := 1; i < len(points); i++ {
+		if points[i-1].equity != 0 {
+			returns[i-1] = (points[i].equity - points[i-1].equity) / points[i-1].equity
+		}
+		benchReturns[i-1] = (bench[i] - bench[i-1]) / bench[i-1]
+
+		rate := rates(points[i].timestamp)
+		excess[i-1] = returns[i-1] - rate
+		benchExcess[i-1] = benchReturns[i-1] - rate
+		active[i-1] = returns[i-1] - benchReturns[i-1]
+
+		switch {
+		case benchReturns[i-1] > 0:
+			up = append(up, returns[i-1])
+			upBench = append(upBench, benchReturns[i-1])
+		case benchReturns[i-1] < 0:
+			down = append(down, returns[i-1])
+			downBench = append(downBench, benchReturns[i-1])
+		}
+	}
+
+	if variance := stat.Variance(benchReturns, nil); variance > 0 {
+		c.Beta = stat.Covariance(returns, benchReturns, nil) / variance
+		if stat.StdDev(returns, nil) > 0 {
+			c.Correlation = stat.Correlation(returns, benchReturns, nil)
+		}
+	}
+	c.Alpha = (stat.Mean(excess, nil) - c.Beta*stat.Mean(benchExcess, nil)) * periodsPerYear
+
+	mean, stdDev := stat.MeanStdDev(active, nil)
+	if stdDev > 0 {
+		c.TrackingError = stdDev * math.Sqrt(periodsPerYear)
+		c.InformationRatio = mean / stdDev * math.Sqrt(periodsPerYear)
+	}
+
+	if len(up) > 0 {
+		c.UpCapture = stat.Mean(up, nil) / stat.Mean(upBench, nil)
+	}
+	if len(down) > 0 {
+		c.DownCapture = stat.Mean(down, nil) / stat.Mean(downBench, nil)
+	}
+
+	for _, f := range []*float64{&c.PeriodsPerYear, &c.Return, &c.BenchmarkReturn, &c.Alpha, &c.Beta, &c.Correlation,
+		&c.TrackingError, &c.InformationRatio, &c.UpCapture, &c.DownCapture} {
+		*f = math.Round(*f*math.Pow10(DP)) / math.Pow10(DP)
+	}
+	return c, nil
+}
+
+// updateBenchmark tracks the price of a benchmark symbol and updates the value of the benchmark.
+func (s *Statistic) updateBenchmark(d DataEvent) {
+	var found bool
+	for _, symbol := range s.benchmarkSymbols {
+		if symbol == d.Symbol() {
+			found = true
+			break
+		}
+	}
+	if !found || (d.Price() <= 0) {
+		return
+	}
+
+	if s.benchmarkLast == nil {
+		s.benchmarkLast = make(map[string]float64)
+	}
+	s.benchmarkLast[d.Symbol()] = d.Price()
+
+	// the buy and hold starts with a price of all symbols
+	if len(s.benchmarkLast) < len(s.benchmarkSymbols) {
+		return
+	}
+	if s.benchmarkFirst == nil {
+		s.benchmarkFirst = make(map[string]float64)
+		for symbol, price := range s.benchmarkLast {
+			s.benchmarkFirst[symbol] = price
+		}
+	}
+
+	var value float64
+	for symbol, first := range s.benchmarkFirst {
+		value += s.benchmarkLast[symbol] / first
+	}
+	value = value / float64(len(s.benchmarkFirst))
+
+	if (len(s.benchmark) > 0) && s.benchmark[len(s.benchmark)-1].timestamp.Equal(d.Time()) {
+		s.benchmark[len(s.benchmark)-1].equity = value
+		return
+	}
+	s.benchmark = append(s.benchmark, EquityPoint{timestamp: d.Time(), equity: value})
+}
+
+// alignBenchmark returns the equity points of each timestamp with the latest benchmark value at that time,
+// starting with the first timestamp with a benchmark value.
+func (s Statistic) alignBenchmark() ([]EquityPoint, []float64) {
+	var points []EquityPoint
+	var bench []float64
+
+	var i int
+	var value float64
+	for _, e := range s.equity {
+		for (i < len(s.benchmark)) && !s.benchmark[i].timestamp.After(e.timestamp) {
+			value = s.benchmark[i].equity
+			i++
+		}
+		if value == 0 {
+			continue
+		}
+		points = append(points, e)
+		bench = append(bench, value)
+	}
+
+	return points, bench
+}
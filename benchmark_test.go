@@ -0,0 +1,130 @@
+package gobacktest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBenchmarkCurve(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		msg      string
+		symbols  []string
+		bars     []*Bar
+		expCurve []EquityPoint
+	}{
+		{"testing single benchmark symbol",
+			[]string{"^GDAXI"},
+			[]*Bar{
+				{Event: Event{timestamp: start, symbol: "TEST.DE"}, Close: 10},
+				{Event: Event{timestamp: start, symbol: "^GDAXI"}, Close: 100},
+				{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "^GDAXI"}, Close: 110},
+			},
+			[]EquityPoint{
+				{timestamp: start, equity: 1},
+				{timestamp: start.AddDate(0, 0, 1), equity: 1.1},
+			},
+		},
+		{"testing equal weighted buy and hold",
+			[]string{"A", "B"},
+			[]*Bar{
+				{Event: Event{timestamp: start, symbol: "A"}, Close: 10},
+				{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "A"}, Close: 20},
+				{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "B"}, Close: 100},
+				{Event: Event{timestamp: start.AddDate(0, 0, 2), symbol: "A"}, Close: 30},
+				{Event: Event{timestamp: start.AddDate(0, 0, 2), symbol: "B"}, Close: 50},
+			},
+			[]EquityPoint{
+				{timestamp: start.AddDate(0, 0, 1), equity: 1},
+				{timestamp: start.AddDate(0, 0, 2), equity: 1},
+			},
+		},
+		{"testing without benchmark",
+			nil,
+			[]*Bar{
+				{Event: Event{timestamp: start, symbol: "A"}, Close: 10},
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		stat := &Statistic{}
+		stat.SetBenchmark(tc.symbols...)
+		for _, bar := range tc.bars {
+			stat.Update(bar, &Portfolio{cash: 100})
+		}
+
+		if !reflect.DeepEqual(stat.BenchmarkCurve(), tc.expCurve) {
+			t.Errorf("%v: \nexpected %+v, \nactual   %+v", tc.msg, tc.expCurve, stat.BenchmarkCurve())
+		}
+
+		stat.Reset()
+		if (len(stat.BenchmarkCurve()) != 0) || !reflect.DeepEqual(stat.benchmarkSymbols, tc.symbols) {
+			t.Errorf("%v Reset(): \nexpected %v %v, \nactual   %v %v", tc.msg, 0, tc.symbols, len(stat.BenchmarkCurve()), stat.benchmarkSymbols)
+		}
+	}
+}
+
+func TestCompareBenchmark(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// equity and benchmark curve with one point per day
+	curves := func(equity []float64, bench []float64) Statistic {
+		s := Statistic{benchmarkSymbols: []string{"^GDAXI"}}
+		for i := range equity {
+			s.equity = append(s.equity, EquityPoint{timestamp: start.AddDate(0, 0, i), equity: equity[i]})
+			if bench[i] > 0 {
+				s.benchmark = append(s.benchmark, EquityPoint{timestamp: start.AddDate(0, 0, i), equity: bench[i]})
+			}
+		}
+		return s
+	}
+
+	var testCases = []struct {
+		msg         string
+		stat        Statistic
+		opts        PerformanceOptions
+		expCompare  BenchmarkComparison
+		expRelative []float64
+		expErr      bool
+	}{
+		{"testing leveraged benchmark",
+			curves([]float64{100, 120, 96, 115.2}, []float64{1, 1.1, 0.99, 1.089}),
+			PerformanceOptions{PeriodsPerYear: 4},
+			BenchmarkComparison{Symbols: []string{"^GDAXI"}, Periods: 3, PeriodsPerYear: 4, Return: 0.152, BenchmarkReturn: 0.089,
+				Alpha: 0, Beta: 2, Correlation: 1, TrackingError: 0.2309, InformationRatio: 0.5774, UpCapture: 2, DownCapture: 2},
+			[]float64{1, 1.0909, 0.9697, 1.0579},
+			false,
+		},
+		{"testing benchmark starting later",
+			curves([]float64{90, 100, 110, 121}, []float64{0, 1, 1, 1.1}),
+			PerformanceOptions{PeriodsPerYear: 4},
+			BenchmarkComparison{Symbols: []string{"^GDAXI"}, Periods: 2, PeriodsPerYear: 4, Return: 0.21, BenchmarkReturn: 0.1,
+				Alpha: 0.4, Beta: 0, Correlation: 0, TrackingError: 0.1414, InformationRatio: 1.4142, UpCapture: 1},
+			[]float64{1, 1.1, 1.1},
+			false,
+		},
+		{"testing without benchmark",
+			curves([]float64{100, 110, 120}, []float64{0, 0, 0}),
+			PerformanceOptions{PeriodsPerYear: 4},
+			BenchmarkComparison{},
+			[]float64{},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		compare, err := tc.stat.CompareBenchmark(tc.opts)
+		relative := []float64{}
+		for _, e := range tc.stat.RelativeCurve() {
+			relative = append(relative, e.Equity())
+		}
+
+		if ((err != nil) != tc.expErr) || !reflect.DeepEqual(compare, tc.expCompare) || !reflect.DeepEqual(relative, tc.expRelative) {
+			t.Errorf("%v: \nexpected %+v %v %v, \nactual   %+v %v %v", tc.msg, tc.expCompare, tc.expRelative, tc.expErr, compare, relative, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package gobacktest
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dirkolbrich/gobacktest/ta"
+)
+
+// BreakerHandler is the basic interface of a portfolio wide circuit breaker, which is fed by the equity curve.
+type BreakerHandler interface {
+	Update(EquityPoint) (BreakerEvent, bool)
+	Blocked() (string, bool)
+	Reseter
+}
+
+// BreakerEvent records a state change of a circuit breaker.
+type BreakerEvent struct {
+	Time    time.Time
+	Tripped bool // true if the breaker tripped, false if it was re-enabled
+	Flatten bool // all positions are closed
+	Equity  float64
+	Reason  string
+}
+
+// CircuitBreaker trips if the drawdown, the daily loss or the volatility of the equity curve breaches its threshold.
+// A tripped breaker blocks all orders which open or increase a position and optionally flattens all positions.
+// After the cooldown the breaker is re-enabled, and the drawdown and volatility are measured from that point on.
+// A threshold of 0 is not checked.
+type CircuitBreaker struct {
+	MaxDrawdown   float64       // drawdown from the equity high, e.g. 0.2 for 20%
+	MaxDailyLoss  float64       // loss since the end of the previous day, e.g. 0.05 for 5%
+	MaxVolatility float64       // standard deviation of the equity returns over the period, e.g. 0.03 for 3%
+	Period        int           // number of equity returns for the volatility
+	Flatten       bool          // close all positions when tripped, otherwise only block new entries
+	Cooldown      time.Duration // time until the breaker is re-enabled, 0 keeps it tripped until reset
+	tripped       bool
+	trippedAt     time.Time
+	reason        string
+	high          float64
+	day           time.Time
+	dayStart      float64
+	last          float64
+	returns       []float64
+}
+
+// Update feeds the breaker with the latest equity point and returns the event of a state change.
+func (b *CircuitBreaker) Update(point EquityPoint) (BreakerEvent, bool) {
+	equity := point.Equity()
+	defer func() { b.last = equity }()
+
+	// keep the equity at the end of the previous day
+	y, m, d := point.Time().Date()
+	if day := time.Date(y, m, d, 0, 0, 0, 0, point.Time().Location()); !day.Equal(b.day) {
+		b.day = day
+		b.dayStart = b.last
+		if b.dayStart == 0 {
+			b.dayStart = equity
+		}
+	}
+
+	if b.tripped {
+		if (b.Cooldown == 0) || point.Time().Before(b.trippedAt.Add(b.Cooldown)) {
+			return BreakerEvent{}, false
+		}
+		b.tripped = false
+		b.reason = ""
+		b.high = equity
+		b.returns = nil
+		return BreakerEvent{Time: point.Time(), Equity: equity, Reason: "cooldown passed"}, true
+	}
+
+	b.track(equity)
+
+	reason, ok := b.breached(equity)
+	if !ok {
+		return BreakerEvent{}, false
+	}
+	b.tripped = true
+	b.trippedAt = point.Time()
+	b.reason = reason
+	return BreakerEvent{Time: point.Time(), Tripped: true, Flatten: b.Flatten, Equity: equity, Reason: reason}, true
+}
+
+// Blocked returns the reason, if the breaker is tripped and blocks new entries.
+func (b *CircuitBreaker) Blocked() (string, bool) {
+	return b.reason, b.tripped
+}
+
+// Reset implements Reseter to re-enable the breaker into a clean state.
+func (b *CircuitBreaker) Reset() error {
+	b.tripped = false
+	b.trippedAt = time.Time{}
+	b.reason = ""
+	b.high = 0
+	b.day = time.Time{}
+	b.dayStart = 0
+	b.last = 0
+	b.returns = nil
+	return nil
+}
+
+// track updates the equity high and the window of the equity returns.
+func (b *CircuitBreaker) track(equity float64) {
+	if equity > b.high {
+		b.high = equity
+	}
+
+	if (b.Period <= 0) || (b.last == 0) {
+		return
+	}
+	b.returns = append(b.returns, (equity-b.last)/b.last)
+	if len(b.returns) > b.Period {
+		b.returns = b.returns[len(b.returns)-b.Period:]
+	}
+}
+
+// breached checks the thresholds of the breaker and returns the reason of the first breach.
+func (b *CircuitBreaker) breached(equity float64) (string, bool) {
+	if (b.MaxDrawdown > 0) && (b.high > 0) {
+		if drawdown := (b.high - equity) / b.high; drawdown >= b.MaxDrawdown {
+			return fmt.Sprintf("drawdown %.2f%% breached max drawdown %.2f%%", drawdown*100, b.MaxDrawdown*100), true
+		}
+	}
+
+	if (b.MaxDailyLoss > 0) && (b.dayStart > 0) {
+		if loss := (b.dayStart - equity) / b.dayStart; loss >= b.MaxDailyLoss {
+			return fmt.Sprintf("daily loss %.2f%% breached max daily loss %.2f%%", loss*100, b.MaxDailyLoss*100), true
+		}
+	}
+
+	if (b.MaxVolatility > 0) && (b.Period > 1) && (len(b.returns) == b.Period) {
+		if vol, _ := ta.StdDev(b.returns, b.Period); vol[0] >= b.MaxVolatility {
+			return fmt.Sprintf("volatility %.2f%% breached max volatility %.2f%%", vol[0]*100, b.MaxVolatility*100), true
+		}
+	}
+
+	return "", false
+}
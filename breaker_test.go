@@ -0,0 +1,137 @@
+package gobacktest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	type point struct {
+		hours  int // hours since start
+		equity float64
+	}
+
+	var testCases = []struct {
+		msg       string
+		breaker   *CircuitBreaker
+		points    []point
+		expEvents map[int]bool // index of the points with an event, true if tripped
+	}{
+		{"testing max drawdown",
+			&CircuitBreaker{MaxDrawdown: 0.1},
+			[]point{{0, 100}, {24, 110}, {48, 100}, {72, 98}, {96, 90}},
+			map[int]bool{3: true},
+		},
+		{"testing max daily loss",
+			&CircuitBreaker{MaxDailyLoss: 0.05},
+			[]point{{0, 100}, {24, 100}, {30, 96}, {31, 94}},
+			map[int]bool{3: true},
+		},
+		{"testing max daily loss against the previous day",
+			&CircuitBreaker{MaxDailyLoss: 0.05},
+			[]point{{0, 100}, {6, 96}, {24, 94}, {30, 92}},
+			map[int]bool{},
+		},
+		{"testing max volatility",
+			&CircuitBreaker{MaxVolatility: 0.05, Period: 2},
+			[]point{{0, 100}, {24, 110}, {48, 99}},
+			map[int]bool{2: true},
+		},
+		{"testing max volatility below threshold",
+			&CircuitBreaker{MaxVolatility: 0.05, Period: 2},
+			[]point{{0, 100}, {24, 101}, {48, 100}, {72, 102}},
+			map[int]bool{},
+		},
+		{"testing cooldown",
+			&CircuitBreaker{MaxDrawdown: 0.1, Cooldown: 48 * time.Hour},
+			[]point{{0, 100}, {24, 89}, {48, 85}, {72, 86}, {96, 80}, {120, 77}},
+			map[int]bool{1: true, 3: false, 5: true},
+		},
+		{"testing without cooldown",
+			&CircuitBreaker{MaxDrawdown: 0.1},
+			[]point{{0, 100}, {24, 89}, {48, 85}, {72, 86}, {96, 80}, {120, 77}},
+			map[int]bool{1: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		events := map[int]bool{}
+		for i, p := range tc.points {
+			e := EquityPoint{timestamp: start.Add(time.Duration(p.hours) * time.Hour), equity: p.equity}
+			if event, ok := tc.breaker.Update(e); ok {
+				events[i] = event.Tripped
+			}
+		}
+
+		if !reflect.DeepEqual(events, tc.expEvents) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expEvents, events)
+		}
+
+		tc.breaker.Reset()
+		if _, ok := tc.breaker.Blocked(); ok {
+			t.Errorf("%v Reset(): \nexpected %v, \nactual   %v", tc.msg, false, ok)
+		}
+	}
+}
+
+func TestBacktestBreaker(t *testing.T) {
+	start := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	statistic := &Statistic{equity: []EquityPoint{{timestamp: start, equity: 100}}}
+	test := &Backtest{
+		portfolio: &Portfolio{holdings: map[string]Position{
+			"A": {qty: 100},
+			"B": {qty: -50},
+			"C": {qty: 0},
+		}},
+		statistic: statistic,
+		breaker:   &CircuitBreaker{MaxDrawdown: 0.1, Flatten: true},
+	}
+
+	test.updateBreaker()
+	statistic.equity = append(statistic.equity, EquityPoint{timestamp: start.AddDate(0, 0, 1), equity: 80})
+	test.updateBreaker()
+
+	expOrders := []EventHandler{
+		&Order{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "A"}, direction: SLD, qty: 100},
+		&Order{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "B"}, direction: BOT, qty: 50},
+	}
+	if !reflect.DeepEqual(test.eventQueue, expOrders) || (len(statistic.BreakerEvents()) != 1) {
+		t.Errorf("testing flatten: \nexpected %v %v, \nactual   %v %v",
+			expOrders, 1, test.eventQueue, len(statistic.BreakerEvents()))
+	}
+
+	var testCases = []struct {
+		msg        string
+		order      *Order
+		expBlocked bool
+		expQty     int64
+	}{
+		{"testing new entry is blocked",
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 10},
+			true, 10,
+		},
+		{"testing exit is not blocked",
+			&Order{Event: Event{symbol: "A"}, direction: SLD, qty: 100},
+			false, 100,
+		},
+		{"testing flip is scaled down",
+			&Order{Event: Event{symbol: "B"}, direction: BOT, qty: 80},
+			false, 50,
+		},
+	}
+
+	for _, tc := range testCases {
+		blocked := test.blocked(tc.order)
+		if (blocked != tc.expBlocked) || (tc.order.Qty() != tc.expQty) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v", tc.msg, tc.expBlocked, tc.expQty, blocked, tc.order.Qty())
+		}
+	}
+
+	if len(statistic.RiskDecisions()) != 2 {
+		t.Errorf("testing tracked decisions: \nexpected %v, \nactual   %v", 2, len(statistic.RiskDecisions()))
+	}
+}
@@ -0,0 +1,164 @@
+package gobacktest
+
+import (
+	"sort"
+	"time"
+)
+
+// Cloner creates a copy of a data event with its own metrics.
+type Cloner interface {
+	Clone() DataEvent
+}
+
+// Clone returns a copy of the bar with its own metrics.
+func (b Bar) Clone() DataEvent {
+	b.Metric = b.Metric.copy()
+	return &b
+}
+
+// Clone returns a copy of the tick with its own metrics.
+func (t Tick) Clone() DataEvent {
+	t.Metric = t.Metric.copy()
+	return &t
+}
+
+// Dataset is a loaded and read-only set of data events, which can be shared by concurrent backtests.
+// Each backtest iterates the dataset with its own DataView.
+type Dataset struct {
+	events []DataEvent // sorted by time and symbol
+}
+
+// NewDataset creates a dataset from the data events, e.g. the stream of a loaded data handler.
+// The events must not be changed after the dataset is created.
+func NewDataset(events []DataEvent) *Dataset {
+	sorted := make([]DataEvent, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		b1 := sorted[i]
+		b2 := sorted[j]
+
+		// if date is equal sort by symbol
+		if b1.Time().Equal(b2.Time()) {
+			return b1.Symbol() < b2.Symbol()
+		}
+		// else sort by date
+		return b1.Time().Before(b2.Time())
+	})
+
+	return &Dataset{events: sorted}
+}
+
+// Events returns the sorted data events of the dataset, which must not be changed.
+func (d *Dataset) Events() []DataEvent {
+	return d.events
+}
+
+// Len returns the number of data events of the dataset.
+func (d *Dataset) Len() int {
+	return len(d.events)
+}
+
+// Timestamps returns the distinct timestamps of the data events in ascending order.
+func (d *Dataset) Timestamps() []time.Time {
+	var timestamps []time.Time
+	for i, e := range d.events {
+		if (i == 0) || !e.Time().Equal(d.events[i-1].Time()) {
+			timestamps = append(timestamps, e.Time())
+		}
+	}
+	return timestamps
+}
+
+// Window returns a dataset with the data events from start to end, both included.
+// The window shares the data events with the dataset.
+func (d *Dataset) Window(start, end time.Time) *Dataset {
+	from := sort.Search(len(d.events), func(i int) bool {
+		return !d.events[i].Time().Before(start)
+	})
+	to := sort.Search(len(d.events), func(i int) bool {
+		return d.events[i].Time().After(end)
+	})
+	if to < from {
+		to = from
+	}
+
+	return &Dataset{events: d.events[from:to:to]}
+}
+
+// View returns a new cursor on the dataset, which satisfies the DataHandler interface.
+func (d *Dataset) View() *DataView {
+	return &DataView{dataset: d}
+}
+
+// DataView is the cursor of a single backtest on a shared dataset. The view hands out a copy of each
+// data event, which implements Cloner, so the metrics of a backtest stay apart from other backtests.
+// Data events without Cloner are shared and must not be changed.
+type DataView struct {
+	dataset *Dataset
+	next    int // index of the next data event of the dataset
+	latest  map[string]DataEvent
+	list    map[string][]DataEvent
+	history []DataEvent
+}
+
+// Load satisfies the DataLoader interface, the data events are loaded with the dataset.
+func (v *DataView) Load(s []string) error {
+	return nil
+}
+
+// Reset implements Reseter to move the cursor back to the first data event of the dataset.
+func (v *DataView) Reset() error {
+	v.next = 0
+	v.latest = nil
+	v.list = nil
+	v.history = nil
+	return nil
+}
+
+// Next returns the next data event of the dataset and appends it to the historic data stream.
+func (v *DataView) Next() (dh DataEvent, ok bool) {
+	if v.next >= len(v.dataset.events) {
+		return dh, false
+	}
+
+	dh = v.dataset.events[v.next]
+	v.next++
+	if c, ok := dh.(Cloner); ok {
+		dh = c.Clone()
+	}
+	v.history = append(v.history, dh)
+
+	// check for nil map, else initialise the map
+	if v.latest == nil {
+		v.latest = make(map[string]DataEvent)
+	}
+	v.latest[dh.Symbol()] = dh
+
+	if v.list == nil {
+		v.list = make(map[string][]DataEvent)
+	}
+	v.list[dh.Symbol()] = append(v.list[dh.Symbol()], dh)
+
+	return dh, true
+}
+
+// Stream returns the data events of the dataset, which are not yet returned by Next.
+// The data events are shared with other views and must not be changed.
+func (v *DataView) Stream() []DataEvent {
+	return v.dataset.events[v.next:]
+}
+
+// History returns the historic data stream of the view.
+func (v *DataView) History() []DataEvent {
+	return v.history
+}
+
+// Latest returns the last known data event for a symbol.
+func (v *DataView) Latest(symbol string) DataEvent {
+	return v.latest[symbol]
+}
+
+// List returns the data event list for a symbol.
+func (v *DataView) List(symbol string) []DataEvent {
+	return v.list[symbol]
+}
@@ -0,0 +1,151 @@
+package gobacktest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testHelperDataset returns a dataset with two symbols over three days, loaded out of order.
+func testHelperDataset() *Dataset {
+	day := func(i int) time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+
+	return NewDataset([]DataEvent{
+		&Bar{Event: Event{timestamp: day(1), symbol: "B"}, Close: 21, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day(0), symbol: "B"}, Close: 20, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day(0), symbol: "A"}, Close: 10, Metric: Metric{"base": 1}},
+		&Bar{Event: Event{timestamp: day(2), symbol: "A"}, Close: 12, Metric: Metric{}},
+		&Bar{Event: Event{timestamp: day(1), symbol: "A"}, Close: 11, Metric: Metric{}},
+	})
+}
+
+func TestDataset(t *testing.T) {
+	day := func(i int) time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+	dataset := testHelperDataset()
+
+	prices := func(events []DataEvent) []float64 {
+		var prices []float64
+		for _, e := range events {
+			prices = append(prices, e.Price())
+		}
+		return prices
+	}
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing sorted events", prices(dataset.Events()), []float64{10, 20, 11, 21, 12}},
+		{"testing length", dataset.Len(), 5},
+		{"testing timestamps", dataset.Timestamps(), []time.Time{day(0), day(1), day(2)}},
+		{"testing window", prices(dataset.Window(day(1), day(2)).Events()), []float64{11, 21, 12}},
+		{"testing window of a single day", prices(dataset.Window(day(0), day(0)).Events()), []float64{10, 20}},
+		{"testing window without events", dataset.Window(day(5), day(6)).Len(), 0},
+		{"testing reversed window", dataset.Window(day(2), day(0)).Len(), 0},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
+
+func TestDataView(t *testing.T) {
+	dataset := testHelperDataset()
+	view := dataset.View()
+	other := dataset.View()
+
+	first, _ := view.Next()
+	first.Add("sma", 5)
+	view.Next()
+	view.Next()
+
+	otherFirst, _ := other.Next()
+	base, _ := otherFirst.Get("base")
+	_, leaked := otherFirst.Get("sma")
+	_, shared := dataset.Events()[0].Get("sma")
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing history", len(view.History()), 3},
+		{"testing remaining stream", len(view.Stream()), 2},
+		{"testing latest", view.Latest("A").Price(), 11.0},
+		{"testing list", len(view.List("A")), 2},
+		{"testing copied event", first != dataset.Events()[0], true},
+		{"testing metrics of the base event", base, 1.0},
+		{"testing metrics of other view", leaked, false},
+		{"testing metrics of the dataset", shared, false},
+		{"testing independent cursor", len(other.History()), 1},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+
+	view.Reset()
+	if (len(view.History()) != 0) || (len(view.Stream()) != 5) || (view.Latest("A") != nil) {
+		t.Errorf("testing Reset(): \nexpected %v %v %v, \nactual   %v %v %v", 0, 5, nil, len(view.History()), len(view.Stream()), view.Latest("A"))
+	}
+}
+
+// testMetricAlgo writes the number of data events of the symbol into the metrics of each data event.
+type testMetricAlgo struct {
+	Algo
+}
+
+func (a testMetricAlgo) Run(s StrategyHandler) (bool, error) {
+	event, _ := s.Event()
+	data, _ := s.Data()
+	event.Add("count", float64(len(data.List(event.Symbol()))))
+	return true, nil
+}
+
+func TestDatasetConcurrentBacktests(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	var events []DataEvent
+	for i := 0; i < 200; i++ {
+		for _, symbol := range []string{"A", "B"} {
+			events = append(events, &Bar{Event: Event{timestamp: start.AddDate(0, 0, i), symbol: symbol}, Close: float64(100 + i%7), Metric: Metric{}})
+		}
+	}
+	dataset := NewDataset(events)
+
+	var wg sync.WaitGroup
+	results := make([]int, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			test := New()
+			test.SetSymbols([]string{"A", "B"})
+			test.SetData(dataset.View())
+			test.SetStrategy(NewStrategy("metric").SetAlgo(&testMetricAlgo{}))
+			if err := test.Run(); err != nil {
+				t.Errorf("testing concurrent backtest %v: \nexpected %v, \nactual   %v", i, nil, err)
+			}
+			results[i] = len(test.Stats().(EquityCurver).EquityCurve())
+		}(i)
+	}
+	wg.Wait()
+
+	for i, r := range results {
+		if r != 200 {
+			t.Errorf("testing concurrent backtest %v: \nexpected %v, \nactual   %v", i, 200, r)
+		}
+	}
+	if _, ok := dataset.Events()[0].Get("count"); ok {
+		t.Errorf("testing metrics of the dataset: \nexpected %v, \nactual   %v", false, ok)
+	}
+}
@@ -0,0 +1,81 @@
+package gobacktest
+
+import (
+	"sort"
+)
+
+// DataSlice bundles the data events of all symbols for a single timestamp.
+// It allows a strategy to look at the complete cross section of the market at once.
+type DataSlice struct {
+	Event
+	events map[string]DataEvent
+}
+
+// NewDataSlice creates a data slice from the given data events.
+// The timestamp of the slice is taken from the first data event.
+func NewDataSlice(events ...DataEvent) *DataSlice {
+	ds := &DataSlice{}
+	for _, event := range events {
+		ds.Add(event)
+	}
+	return ds
+}
+
+// Add a data event to the data slice. An existing data event of the same symbol is replaced.
+func (ds *DataSlice) Add(event DataEvent) {
+	// check for nil map, else initialise the map
+	if ds.events == nil {
+		ds.events = make(map[string]DataEvent)
+		ds.SetTime(event.Time())
+	}
+
+	ds.events[event.Symbol()] = event
+}
+
+// Get returns the data event of a symbol, if not found it returns false.
+func (ds DataSlice) Get(symbol string) (DataEvent, bool) {
+	event, ok := ds.events[symbol]
+	return event, ok
+}
+
+// Len returns the number of data events in the data slice.
+func (ds DataSlice) Len() int {
+	return len(ds.events)
+}
+
+// Symbols returns the symbols of the data slice in ascending order.
+func (ds DataSlice) Symbols() []string {
+	var symbols = []string{}
+	for symbol := range ds.events {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	return symbols
+}
+
+// Events returns the data events of the data slice, sorted by symbol.
+func (ds DataSlice) Events() []DataEvent {
+	var events = []DataEvent{}
+	for _, symbol := range ds.Symbols() {
+		events = append(events, ds.events[symbol])
+	}
+
+	return events
+}
+
+// Filter returns a new data slice which only contains the data events of the given symbols.
+func (ds DataSlice) Filter(symbols ...string) *DataSlice {
+	filtered := &DataSlice{
+		Event:  Event{timestamp: ds.timestamp},
+		events: make(map[string]DataEvent),
+	}
+
+	for _, symbol := range symbols {
+		if event, ok := ds.events[symbol]; ok {
+			filtered.events[symbol] = event
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,101 @@
+package gobacktest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewDataSlice(t *testing.T) {
+	timestamp := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		msg        string
+		events     []DataEvent
+		expTime    time.Time
+		expSymbols []string
+		expLen     int
+	}{
+		{"test empty data slice:",
+			[]DataEvent{},
+			time.Time{},
+			[]string{},
+			0,
+		},
+		{"test multiple symbols sorted:",
+			[]DataEvent{
+				&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.B"}, Close: 20},
+				&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.A"}, Close: 10},
+			},
+			timestamp,
+			[]string{"TEST.A", "TEST.B"},
+			2,
+		},
+		{"test same symbol replaces event:",
+			[]DataEvent{
+				&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.A"}, Close: 10},
+				&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.A"}, Close: 11},
+			},
+			timestamp,
+			[]string{"TEST.A"},
+			1,
+		},
+	}
+
+	for _, tc := range testCases {
+		ds := NewDataSlice(tc.events...)
+		if !ds.Time().Equal(tc.expTime) || !reflect.DeepEqual(ds.Symbols(), tc.expSymbols) || (ds.Len() != tc.expLen) {
+			t.Errorf("%v NewDataSlice(): \nexpected %v %v %v, \nactual   %v %v %v",
+				tc.msg, tc.expTime, tc.expSymbols, tc.expLen, ds.Time(), ds.Symbols(), ds.Len())
+		}
+	}
+}
+
+func TestDataSliceEvents(t *testing.T) {
+	barA := &Bar{Event: Event{symbol: "TEST.A"}, Close: 10}
+	barB := &Bar{Event: Event{symbol: "TEST.B"}, Close: 20}
+	ds := NewDataSlice(barB, barA)
+
+	events := ds.Events()
+	exp := []DataEvent{barA, barB}
+	if !reflect.DeepEqual(events, exp) {
+		t.Errorf("Events(): \nexpected %#v, \nactual   %#v", exp, events)
+	}
+
+	event, ok := ds.Get("TEST.B")
+	if !ok || (event != barB) {
+		t.Errorf("Get(): \nexpected %#v %v, \nactual   %#v %v", barB, true, event, ok)
+	}
+
+	_, ok = ds.Get("TEST.C")
+	if ok {
+		t.Errorf("Get() unknown symbol: expected %v, actual %v", false, ok)
+	}
+}
+
+func TestDataSliceFilter(t *testing.T) {
+	timestamp := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+	ds := NewDataSlice(
+		&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.A"}},
+		&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.B"}},
+		&Bar{Event: Event{timestamp: timestamp, symbol: "TEST.C"}},
+	)
+
+	var testCases = []struct {
+		msg        string
+		symbols    []string
+		expSymbols []string
+	}{
+		{"test no symbols:", []string{}, []string{}},
+		{"test subset:", []string{"TEST.C", "TEST.A"}, []string{"TEST.A", "TEST.C"}},
+		{"test unknown symbol:", []string{"TEST.D"}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		filtered := ds.Filter(tc.symbols...)
+		if !reflect.DeepEqual(filtered.Symbols(), tc.expSymbols) || !filtered.Time().Equal(timestamp) {
+			t.Errorf("%v Filter(%v): \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.symbols, tc.expSymbols, timestamp, filtered.Symbols(), filtered.Time())
+		}
+	}
+}
@@ -2,115 +2,60 @@ package main
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/dirkolbrich/gobacktest"
 	"github.com/dirkolbrich/gobacktest/data"
+	"github.com/dirkolbrich/gobacktest/optimiser"
 	"github.com/dirkolbrich/gobacktest/strategy"
 )
 
-// Result bundles the result of a single backtest
-type Result struct {
-	smaShort int
-	smaLong  int
-	result   float64
-}
-
 func main() {
-	// create intervals for the short and long range
-	shortRange := linspace(5, 50, 10)
-	longRange := linspace(150, 250, 10)
-	// create a slice for different test results
-	results := []Result{}
-	for _, short := range shortRange {
-		for _, long := range longRange {
-			results = append(results, Result{smaShort: short, smaLong: long})
-		}
-	}
-
-	// initiate new backtester
-	test := gobacktest.New()
-
 	// define and load symbols
 	symbols := []string{"SZG.DE"}
-	test.SetSymbols(symbols)
 
-	// create data provider and load data into the backtest
+	// create data provider and load the data, which is shared by all backtests
 	data := &data.BarEventFromCSVFile{FileDir: "../testdata/bar/"}
 	data.Load(symbols)
-	test.SetData(data)
 
-	startTest := time.Now()
-	// iterate over every field in the matrix
-	for i := range results {
-		// create strategy provider and load into the backtest
-		strategy := strategy.MovingAverageCross(results[i].smaShort, results[i].smaLong)
-		test.SetStrategy(strategy)
-
-		// run the backtest
-		test.Run()
-
-		// get the result and save to slice
-		result, _ := test.Stats().TotalEquityReturn()
-		fmt.Printf("backtest sma%d / sma%d with result %f%%\n", results[i].smaShort, results[i].smaLong, result*100)
-		results[i].result = result
-
-		test.Reset()
+	// create the optimiser with intervals for the short and long range
+	opt := &optimiser.Optimiser{
+		Space: optimiser.Grid(
+			optimiser.Parameter{Name: "short", Min: 5, Max: 50, Step: 5, Integer: true},
+			optimiser.Parameter{Name: "long", Min: 150, Max: 250, Step: 10, Integer: true},
+		),
+		// build a fresh backtest for every combination of the parameters
+		Build: func(p optimiser.Params) (*gobacktest.Backtest, error) {
+			test := gobacktest.New()
+			test.SetSymbols(symbols)
+			test.SetStrategy(strategy.MovingAverageCross(p.Int("short"), p.Int("long")))
+			return test, nil
+		},
+		Data: data,
 	}
 
+	startTest := time.Now()
+	results, err := opt.Run()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	stopTest := time.Now()
-	fmt.Printf("Complete backtest took %v sec\n", stopTest.Sub(startTest).Seconds())
+	fmt.Printf("Complete backtest of %d combinations took %v sec\n", len(results), stopTest.Sub(startTest).Seconds())
+
+	// rank the results by the total return, with a max drawdown below 20%
+	ranked := results.Rank(optimiser.TotalReturn(), optimiser.DrawdownLimit(0.2))
 
-	sortedResults := sortResults(results)
 	// print best results
 	fmt.Println("Best results:")
-	for k := 0; k < 3; k++ {
-		result := sortedResults[k]
-		fmt.Printf("%v. SMA %v / SMA %v: %2f%%\n", k+1, result.smaShort, result.smaLong, result.result*100)
+	for k := 0; (k < 3) && (k < len(ranked)); k++ {
+		result := ranked[k]
+		fmt.Printf("%v. SMA %v / SMA %v: %2f%%\n", k+1, result.Params.Int("short"), result.Params.Int("long"), result.Value(optimiser.TotalReturn())*100)
 	}
 	// print worst results
 	fmt.Println("Worst results:")
-	for k := 0; k < 3; k++ {
-		result := sortedResults[len(sortedResults)-1-k]
-		fmt.Printf("%v. SMA %v / SMA %v: %2f%%\n", k+1, result.smaShort, result.smaLong, result.result*100)
+	for k := 0; (k < 3) && (k < len(ranked)); k++ {
+		result := ranked[len(ranked)-1-k]
+		fmt.Printf("%v. SMA %v / SMA %v: %2f%%\n", k+1, result.Params.Int("short"), result.Params.Int("long"), result.Value(optimiser.TotalReturn())*100)
 	}
-
-}
-
-// linspace returns a slice of n evenly spaced integers within a given range
-// this function is similar to linspace() in the Python package Numpy
-func linspace(start, stop, n int) []int {
-	delta := stop - start
-	step := delta / (n - 1)
-	slice := make([]int, n, n)
-	i := 0
-	for x := start; x < stop; x += step {
-		slice[i] = x
-		i++
-	}
-	slice[n-1] = stop
-	return slice
-}
-
-// sortResults sorts the result slice descending - highest result first
-func sortResults(r []Result) []Result {
-	sort.Slice(r, func(i, j int) bool {
-		r1 := r[i]
-		r2 := r[j]
-
-		// if result is equal sort by smaShort
-		if r1.result == r2.result {
-			// if smaShort is equal sort by smaLong
-			if r1.smaShort == r2.smaShort {
-				// sort by smaLong
-				return r1.smaLong < r2.smaLong
-			}
-			// sort by smaShort
-			return r1.smaShort < r2.smaShort
-		}
-		// else sort by result
-		return r1.result > r2.result
-	})
-	return r
 }
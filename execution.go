@@ -1,20 +1,38 @@
 package gobacktest
 
 import (
-// "fmt"
+	"math"
+	"sort"
+	"time"
 )
 
 // ExecutionHandler is the basic interface for executing orders
 type ExecutionHandler interface {
-	OnData(DataEvent) (*Fill, error)
+	OnData(DataEvent, DataHandler) ([]*Fill, error)
 	OnOrder(OrderEvent, DataHandler) (*Fill, error)
 }
 
-// Exchange is a basic execution handler implementation
+// Exchange is a basic execution handler implementation.
+// Market orders are filled directly, stop and limit orders rest in the order book of the exchange,
+// until a data event of their symbol reaches their price.
 type Exchange struct {
 	Symbol      string
 	Commission  CommissionHandler
 	ExchangeFee ExchangeFeeHandler
+	Exits       []ExitRule // protective exits attached to every new position
+	orderBook   OrderBook
+	positions   map[string]*protection
+}
+
+// protection tracks the position of a symbol from the fills of the exchange
+// together with the protective exits and their orders.
+type protection struct {
+	qty   int64
+	entry time.Time
+	exits []Exiter
+	group *orderGroup
+	stop  *Order
+	limit *Order
 }
 
 // NewExchange creates a default exchange with sensible defaults ready for use.
@@ -26,23 +44,119 @@ func NewExchange() *Exchange {
 	}
 }
 
-// OnData executes any open order on new data
-func (e *Exchange) OnData(data DataEvent) (*Fill, error) {
-	return nil, nil
+// Reset implements Reseter to remove all resting orders and tracked positions.
+func (e *Exchange) Reset() error {
+	e.orderBook = OrderBook{}
+	e.positions = nil
+	return nil
+}
+
+// Orders returns the resting orders of the exchange.
+func (e *Exchange) Orders() ([]OrderEvent, bool) {
+	return e.orderBook.Orders()
+}
+
+// OnData executes any open order on new data.
+// Resting orders are checked against the high and low of a bar, before the protective exits
+// of the position are updated with the data event. If a stop and a limit order are both reached
+// within the same bar, the stop order is filled first.
+func (e *Exchange) OnData(event DataEvent, data DataHandler) ([]*Fill, error) {
+	var fills []*Fill
+
+	orders, _ := e.orderBook.OrdersBySymbol(event.Symbol())
+	sort.SliceStable(orders, func(i, j int) bool {
+		return (orders[i].(*Order).orderType == StopMarketOrder) && (orders[j].(*Order).orderType != StopMarketOrder)
+	})
+
+	for _, o := range orders {
+		order := o.(*Order)
+		// canceled by an order of the same group, which was filled before
+		if order.status == OrderCanceled {
+			continue
+		}
+
+		price, ok := triggered(order, event)
+		if !ok {
+			continue
+		}
+
+		fill, err := e.execute(order, event.Time(), price)
+		if err != nil {
+			return fills, err
+		}
+		fills = append(fills, fill)
+		e.fillOrder(order, fill)
+		e.track(fill, data)
+	}
+
+	// update the protective exits with the data event
+	pos, ok := e.positions[event.Symbol()]
+	if !ok || (pos.qty == 0) || (len(pos.exits) == 0) || !event.Time().After(pos.entry) {
+		return fills, nil
+	}
+
+	var expired bool
+	for _, exit := range pos.exits {
+		exit.Update(event)
+		if exit.Expired() {
+			expired = true
+		}
+	}
+
+	if !expired {
+		e.protect(event.Symbol(), pos)
+		return fills, nil
+	}
+
+	// close the position at market
+	order := &Order{
+		Event:     Event{timestamp: event.Time(), symbol: event.Symbol()},
+		direction: exitDirection(pos.qty),
+		qty:       abs(pos.qty),
+	}
+	fill, err := e.execute(order, event.Time(), event.Price())
+	if err != nil {
+		return fills, err
+	}
+	fills = append(fills, fill)
+	e.track(fill, data)
+
+	return fills, nil
 }
 
-// OnOrder executes an order event
+// OnOrder executes an order event.
+// Stop and limit orders are placed into the order book and return no fill.
 func (e *Exchange) OnOrder(order OrderEvent, data DataHandler) (*Fill, error) {
+	if o, ok := order.(*Order); ok && ((o.orderType == StopMarketOrder) || (o.orderType == LimitOrder)) {
+		e.place(o)
+		return nil, nil
+	}
+
 	// fetch latest known data event for the symbol
 	latest := data.Latest(order.Symbol())
 
 	// simple implementation, creates a direct fill from the order
 	// based on the last known data price
+	f, err := e.execute(order, order.Time(), latest.Price())
+	if err != nil {
+		return f, err
+	}
+
+	if o, ok := order.(*Order); ok {
+		e.fillOrder(o, f)
+	}
+	e.track(f, data)
+
+	return f, nil
+}
+
+// execute creates the fill of an order at the given price.
+func (e *Exchange) execute(order OrderEvent, t time.Time, price float64) (*Fill, error) {
 	f := &Fill{
-		Event:    Event{timestamp: order.Time(), symbol: order.Symbol()},
+		Event:    Event{timestamp: t, symbol: order.Symbol()},
 		Exchange: e.Symbol,
 		qty:      order.Qty(),
-		price:    latest.Price(), // last price from data event
+		price:    price,
 	}
 
 	f.direction = order.Direction()
@@ -64,7 +178,193 @@ func (e *Exchange) OnOrder(order OrderEvent, data DataHandler) (*Fill, error) {
 	return f, nil
 }
 
+// place adds an order to the order book of the exchange.
+func (e *Exchange) place(order *Order) {
+	order.status = OrderSubmitted
+	if (order.group != nil) && (order.group.id == 0) {
+		order.group.id = e.orderBook.nextGroup()
+	}
+	e.orderBook.Add(order)
+}
+
+// fillOrder marks an order as filled, cancels the other orders of its group
+// and places its legs with the filled qty.
+func (e *Exchange) fillOrder(order *Order, fill *Fill) {
+	order.status = OrderFilled
+	order.qtyFilled = fill.qty
+	order.avgFillPrice = fill.price
+	if order.id != 0 {
+		e.orderBook.Remove(order.id)
+	}
+
+	if order.group != nil {
+		e.cancelGroup(order.group)
+	}
+
+	for _, leg := range order.legs {
+		leg.SetSymbol(fill.Symbol())
+		leg.SetTime(fill.Time())
+		leg.SetQty(fill.qty)
+		e.place(leg)
+	}
+}
+
+// cancelGroup removes all orders of a group from the order book.
+func (e *Exchange) cancelGroup(group *orderGroup) {
+	orders, _ := e.orderBook.OrderBy(func(o OrderEvent) bool {
+		return o.(*Order).group == group
+	})
+
+	for _, o := range orders {
+		order := o.(*Order)
+		order.status = OrderCanceled
+		e.orderBook.Remove(order.id)
+	}
+}
+
+// track updates the position of a symbol with a fill and attaches, adjusts or removes its protective exits.
+func (e *Exchange) track(fill *Fill, data DataHandler) {
+	if e.positions == nil {
+		e.positions = make(map[string]*protection)
+	}
+	pos, ok := e.positions[fill.Symbol()]
+	if !ok {
+		pos = &protection{}
+		e.positions[fill.Symbol()] = pos
+	}
+
+	before := pos.qty
+	switch fill.Direction() {
+	case BOT:
+		pos.qty += fill.Qty()
+	case SLD:
+		pos.qty -= fill.Qty()
+	default:
+		return
+	}
+
+	switch {
+	// position closed
+	case pos.qty == 0:
+		e.unprotect(pos)
+	// new position or flipped from long to short and vice versa
+	case (before == 0) || ((before > 0) != (pos.qty > 0)):
+		e.unprotect(pos)
+		if len(e.Exits) == 0 {
+			return
+		}
+		pos.entry = fill.Time()
+		pos.group = &orderGroup{}
+		for _, rule := range e.Exits {
+			pos.exits = append(pos.exits, rule.Exit(fill, data))
+		}
+		e.protect(fill.Symbol(), pos)
+	// position size changed
+	default:
+		e.protect(fill.Symbol(), pos)
+	}
+}
+
+// protect places or adjusts the protective stop and limit order of a position.
+// With several exits the tightest stop and the nearest limit are used.
+func (e *Exchange) protect(symbol string, pos *protection) {
+	if len(pos.exits) == 0 {
+		return
+	}
+
+	long := pos.qty > 0
+	var stop, limit float64
+	var hasStop, hasLimit bool
+	for _, exit := range pos.exits {
+		if s, ok := exit.Stop(); ok {
+			if !hasStop || (long && (s > stop)) || (!long && (s < stop)) {
+				stop = s
+			}
+			hasStop = true
+		}
+		if l, ok := exit.Limit(); ok {
+			if !hasLimit || (long && (l < limit)) || (!long && (l > limit)) {
+				limit = l
+			}
+			hasLimit = true
+		}
+	}
+
+	if hasStop {
+		if pos.stop == nil {
+			pos.stop = &Order{Event: Event{symbol: symbol}, orderType: StopMarketOrder, group: pos.group}
+			e.place(pos.stop)
+		}
+		pos.stop.direction = exitDirection(pos.qty)
+		pos.stop.qty = abs(pos.qty)
+		pos.stop.stopPrice = stop
+	}
+
+	if hasLimit {
+		if pos.limit == nil {
+			pos.limit = &Order{Event: Event{symbol: symbol}, orderType: LimitOrder, group: pos.group}
+			e.place(pos.limit)
+		}
+		pos.limit.direction = exitDirection(pos.qty)
+		pos.limit.qty = abs(pos.qty)
+		pos.limit.limitPrice = limit
+	}
+}
+
+// unprotect removes the protective exits and their orders of a position.
+func (e *Exchange) unprotect(pos *protection) {
+	if pos.group != nil {
+		e.cancelGroup(pos.group)
+	}
+
+	pos.exits = nil
+	pos.group = nil
+	pos.stop = nil
+	pos.limit = nil
+}
+
 // calculateCost() calculates the total cost for a stock trade
 func (e *Exchange) calculateCost(commission, fee float64) float64 {
 	return commission + fee
 }
+
+// triggered checks if a data event reaches the price of a stop or limit order and returns the fill price.
+// An order reached by a gap at the open is filled at the open price.
+func triggered(order *Order, event DataEvent) (float64, bool) {
+	open, high, low := priceRange(event)
+
+	switch order.orderType {
+	case StopMarketOrder:
+		if (order.direction == SLD) && (low <= order.stopPrice) {
+			return math.Min(open, order.stopPrice), true
+		}
+		if (order.direction == BOT) && (high >= order.stopPrice) {
+			return math.Max(open, order.stopPrice), true
+		}
+	case LimitOrder:
+		if (order.direction == SLD) && (high >= order.limitPrice) {
+			return math.Max(open, order.limitPrice), true
+		}
+		if (order.direction == BOT) && (low <= order.limitPrice) {
+			return math.Min(open, order.limitPrice), true
+		}
+	}
+
+	return 0, false
+}
+
+// exitDirection returns the direction of an order, which closes a position of the given qty.
+func exitDirection(qty int64) Direction {
+	if qty < 0 {
+		return BOT
+	}
+	return SLD
+}
+
+// abs returns the absolute value of a qty.
+func abs(qty int64) int64 {
+	if qty < 0 {
+		return -qty
+	}
+	return qty
+}
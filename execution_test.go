@@ -83,3 +83,218 @@ func TestOnOrder(t *testing.T) {
 		}
 	}
 }
+
+// testHelperExchangeBars creates a data handler with the bars of a symbol, each bar a day apart.
+func testHelperExchangeBars(bars ...*Bar) *Data {
+	start, _ := time.Parse("2006-01-02", "2017-06-01")
+
+	var stream []DataEvent
+	for i, bar := range bars {
+		bar.symbol = "TEST.DE"
+		bar.timestamp = start.AddDate(0, 0, i)
+		stream = append(stream, bar)
+	}
+
+	data := &Data{}
+	data.SetStream(stream)
+	return data
+}
+
+// testHelperExchangeRun enters a position on the first bar with a market order and passes all following bars
+// to the exchange. It returns the fills of the exchange, the entry fill first.
+func testHelperExchangeRun(e *Exchange, entry *Order, bars ...*Bar) []*Fill {
+	data := testHelperExchangeBars(bars...)
+
+	first, _ := data.Next()
+	e.OnData(first, data)
+	entry.Event = Event{timestamp: first.Time(), symbol: first.Symbol()}
+	fill, _ := e.OnOrder(entry, data)
+	fills := []*Fill{fill}
+
+	for event, ok := data.Next(); ok; event, ok = data.Next() {
+		f, _ := e.OnData(event, data)
+		fills = append(fills, f...)
+	}
+
+	return fills
+}
+
+func TestExchangeRestingOrders(t *testing.T) {
+	var testCases = []struct {
+		msg      string
+		order    *Order
+		bar      *Bar
+		expFill  bool
+		expPrice float64
+	}{
+		{"sell stop not reached",
+			&Order{orderType: StopMarketOrder, direction: SLD, qty: 10, stopPrice: 9},
+			&Bar{Open: 10, High: 11, Low: 9.5, Close: 10},
+			false, 0,
+		},
+		{"sell stop reached by low",
+			&Order{orderType: StopMarketOrder, direction: SLD, qty: 10, stopPrice: 9},
+			&Bar{Open: 10, High: 11, Low: 8, Close: 10},
+			true, 9,
+		},
+		{"sell stop gapped at open",
+			&Order{orderType: StopMarketOrder, direction: SLD, qty: 10, stopPrice: 9},
+			&Bar{Open: 8.5, High: 9, Low: 8, Close: 8.5},
+			true, 8.5,
+		},
+		{"buy stop reached by high",
+			&Order{orderType: StopMarketOrder, direction: BOT, qty: 10, stopPrice: 11},
+			&Bar{Open: 10, High: 12, Low: 9, Close: 10},
+			true, 11,
+		},
+		{"sell limit reached by high",
+			&Order{orderType: LimitOrder, direction: SLD, qty: 10, limitPrice: 11},
+			&Bar{Open: 10, High: 12, Low: 9, Close: 10},
+			true, 11,
+		},
+		{"buy limit gapped at open",
+			&Order{orderType: LimitOrder, direction: BOT, qty: 10, limitPrice: 9},
+			&Bar{Open: 8, High: 8.5, Low: 7, Close: 8},
+			true, 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		e := NewExchange()
+		data := testHelperExchangeBars(tc.bar)
+		event, _ := data.Next()
+
+		tc.order.Event = Event{symbol: "TEST.DE"}
+		fill, err := e.OnOrder(tc.order, data)
+		if (fill != nil) || (err != nil) {
+			t.Errorf("%v: resting order: \nexpected %v %v, \nactual   %v %v", tc.msg, nil, nil, fill, err)
+		}
+
+		fills, err := e.OnData(event, data)
+		if (len(fills) == 1) != tc.expFill || (err != nil) {
+			t.Errorf("%v: \nexpected fill %v, \nactual   %v %v", tc.msg, tc.expFill, fills, err)
+			continue
+		}
+		if tc.expFill && (fills[0].Price() != tc.expPrice) {
+			t.Errorf("%v: \nexpected price %v, \nactual   %v", tc.msg, tc.expPrice, fills[0].Price())
+		}
+
+		_, open := e.Orders()
+		if open == tc.expFill {
+			t.Errorf("%v: \nexpected order in book %v, \nactual   %v", tc.msg, !tc.expFill, open)
+		}
+	}
+}
+
+func TestExchangeBracket(t *testing.T) {
+	e := NewExchange()
+	entry := NewBracket(&Order{direction: BOT, qty: 10}, 9, 12)
+
+	fills := testHelperExchangeRun(e, entry,
+		&Bar{Open: 10, High: 10, Low: 10, Close: 10},
+		&Bar{Open: 10, High: 11, Low: 9.5, Close: 11},
+		&Bar{Open: 11, High: 12.5, Low: 10.5, Close: 12},
+		&Bar{Open: 12, High: 12, Low: 8, Close: 8},
+	)
+
+	if len(fills) != 2 {
+		t.Fatalf("bracket order: \nexpected %v fills, \nactual   %v %+v", 2, len(fills), fills)
+	}
+	if (fills[1].Direction() != SLD) || (fills[1].Qty() != 10) || (fills[1].Price() != 12) {
+		t.Errorf("bracket take profit: \nexpected %v %v %v, \nactual   %v %v %v",
+			SLD, 10, 12, fills[1].Direction(), fills[1].Qty(), fills[1].Price())
+	}
+
+	// the stop loss is canceled with the filled take profit
+	if orders, ok := e.Orders(); ok {
+		t.Errorf("bracket stop loss: \nexpected no orders, \nactual   %+v", orders)
+	}
+}
+
+func TestExchangeExits(t *testing.T) {
+	bars := []*Bar{
+		{Open: 100, High: 102, Low: 98, Close: 100},
+		{Open: 100, High: 104, Low: 99, Close: 103},
+		{Open: 103, High: 110, Low: 102, Close: 108},
+		{Open: 108, High: 109, Low: 101, Close: 102},
+		{Open: 102, High: 103, Low: 90, Close: 91},
+	}
+
+	var testCases = []struct {
+		msg      string
+		exits    []ExitRule
+		entry    Direction
+		expPrice float64
+		expDate  string
+	}{
+		{"stop loss",
+			[]ExitRule{&StopLoss{Distance: 5}},
+			BOT, 95, "2017-06-05",
+		},
+		{"percent stop loss",
+			[]ExitRule{&PercentStopLoss{Percent: 0.02}},
+			BOT, 98, "2017-06-05",
+		},
+		{"take profit",
+			[]ExitRule{&TakeProfit{Distance: 5}},
+			BOT, 105, "2017-06-03",
+		},
+		{"percent take profit with stop loss",
+			[]ExitRule{&StopLoss{Distance: 5}, &PercentTakeProfit{Percent: 0.2}},
+			BOT, 95, "2017-06-05",
+		},
+		{"atr stop",
+			[]ExitRule{&ATRStop{Period: 1, Multiple: 2}},
+			BOT, 92, "2017-06-05",
+		},
+		{"trailing stop",
+			[]ExitRule{&TrailingStop{Distance: 5}},
+			BOT, 105, "2017-06-04",
+		},
+		{"percent trailing stop",
+			[]ExitRule{&PercentTrailingStop{Percent: 0.1}},
+			BOT, 99, "2017-06-05",
+		},
+		{"time exit",
+			[]ExitRule{&TimeExit{Bars: 2}},
+			BOT, 108, "2017-06-03",
+		},
+		{"short stop loss",
+			[]ExitRule{&StopLoss{Distance: 5}},
+			SLD, 105, "2017-06-03",
+		},
+		{"short trailing stop",
+			[]ExitRule{&TrailingStop{Distance: 3}},
+			SLD, 103, "2017-06-02",
+		},
+	}
+
+	for _, tc := range testCases {
+		e := NewExchange()
+		e.Exits = tc.exits
+
+		// copy the bars, the data handler sets their time and symbol
+		var series []*Bar
+		for _, bar := range bars {
+			b := *bar
+			series = append(series, &b)
+		}
+
+		fills := testHelperExchangeRun(e, &Order{direction: tc.entry, qty: 10}, series...)
+		if len(fills) != 2 {
+			t.Errorf("%v: \nexpected %v fills, \nactual   %v %+v", tc.msg, 2, len(fills), fills)
+			continue
+		}
+
+		exit := fills[1]
+		date := exit.Time().Format("2006-01-02")
+		if (exit.Direction() == tc.entry) || (exit.Qty() != 10) || (exit.Price() != tc.expPrice) || (date != tc.expDate) {
+			t.Errorf("%v: \nexpected %v %v at %v, \nactual   %v %v at %v",
+				tc.msg, 10, tc.expPrice, tc.expDate, exit.Qty(), exit.Price(), date)
+		}
+
+		if orders, ok := e.Orders(); ok {
+			t.Errorf("%v: \nexpected no orders after exit, \nactual   %+v", tc.msg, orders)
+		}
+	}
+}
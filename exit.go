@@ -0,0 +1,236 @@
+package gobacktest
+
+import (
+	"math"
+
+	"github.com/dirkolbrich/gobacktest/ta"
+)
+
+// ExitRule defines a protective exit, which the exchange attaches to every new position.
+type ExitRule interface {
+	Exit(entry FillEvent, data DataHandler) Exiter
+}
+
+// Exiter is the protective exit of a single open position.
+type Exiter interface {
+	// Update the exit with a new data event of the symbol.
+	Update(DataEvent)
+	// Stop returns the stop price, false if the exit sets no stop.
+	Stop() (float64, bool)
+	// Limit returns the limit price to take the profit, false if the exit sets no limit.
+	Limit() (float64, bool)
+	// Expired checks if the position has to be closed at market.
+	Expired() bool
+}
+
+// StopLoss is an exit rule with a stop at a fixed price distance from the entry price.
+type StopLoss struct {
+	Distance float64
+}
+
+// Exit creates the stop loss exit of a new position.
+func (r *StopLoss) Exit(entry FillEvent, _ DataHandler) Exiter {
+	return &priceExit{stop: offset(entry, -r.Distance)}
+}
+
+// PercentStopLoss is an exit rule with a stop at a percentage distance from the entry price, e.g. 0.05 for 5%.
+type PercentStopLoss struct {
+	Percent float64
+}
+
+// Exit creates the stop loss exit of a new position.
+func (r *PercentStopLoss) Exit(entry FillEvent, _ DataHandler) Exiter {
+	return &priceExit{stop: offset(entry, -r.Percent*entry.Price())}
+}
+
+// TakeProfit is an exit rule with a limit at a fixed price distance from the entry price.
+type TakeProfit struct {
+	Distance float64
+}
+
+// Exit creates the take profit exit of a new position.
+func (r *TakeProfit) Exit(entry FillEvent, _ DataHandler) Exiter {
+	return &priceExit{limit: offset(entry, r.Distance)}
+}
+
+// PercentTakeProfit is an exit rule with a limit at a percentage distance from the entry price, e.g. 0.1 for 10%.
+type PercentTakeProfit struct {
+	Percent float64
+}
+
+// Exit creates the take profit exit of a new position.
+func (r *PercentTakeProfit) Exit(entry FillEvent, _ DataHandler) Exiter {
+	return &priceExit{limit: offset(entry, r.Percent*entry.Price())}
+}
+
+// ATRStop is an exit rule with a stop at a multiple of the average true range from the entry price.
+// The average true range is calculated over the period at the time of the entry.
+type ATRStop struct {
+	Period   int
+	Multiple float64
+}
+
+// Exit creates the stop loss exit of a new position, without enough data it sets no stop.
+func (r *ATRStop) Exit(entry FillEvent, data DataHandler) Exiter {
+	atr, err := ta.ATR(taBars(data.List(entry.Symbol())), r.Period)
+	if err != nil {
+		return &priceExit{}
+	}
+
+	return &priceExit{stop: offset(entry, -r.Multiple*atr[len(atr)-1])}
+}
+
+// TrailingStop is an exit rule with a stop, which trails the best price since the entry at a fixed distance.
+type TrailingStop struct {
+	Distance float64
+}
+
+// Exit creates the trailing stop exit of a new position.
+func (r *TrailingStop) Exit(entry FillEvent, _ DataHandler) Exiter {
+	return &trailingExit{long: entry.Direction() == BOT, best: entry.Price(), distance: r.Distance}
+}
+
+// PercentTrailingStop is an exit rule with a stop, which trails the best price since the entry at a percentage distance.
+type PercentTrailingStop struct {
+	Percent float64
+}
+
+// Exit creates the trailing stop exit of a new position.
+func (r *PercentTrailingStop) Exit(entry FillEvent, _ DataHandler) Exiter {
+	return &trailingExit{long: entry.Direction() == BOT, best: entry.Price(), percent: r.Percent}
+}
+
+// TimeExit is an exit rule, which closes a position at market after a number of bars.
+type TimeExit struct {
+	Bars int
+}
+
+// Exit creates the time exit of a new position.
+func (r *TimeExit) Exit(_ FillEvent, _ DataHandler) Exiter {
+	return &timeExit{max: r.Bars}
+}
+
+// priceExit is an exit with a fixed stop and limit price, a price of 0 is not set.
+type priceExit struct {
+	stop, limit float64
+}
+
+// Update implements Exiter, a fixed exit does not change.
+func (e *priceExit) Update(DataEvent) {}
+
+// Stop returns the stop price.
+func (e *priceExit) Stop() (float64, bool) {
+	return e.stop, e.stop > 0
+}
+
+// Limit returns the limit price.
+func (e *priceExit) Limit() (float64, bool) {
+	return e.limit, e.limit > 0
+}
+
+// Expired implements Exiter, a fixed exit never expires.
+func (e *priceExit) Expired() bool {
+	return false
+}
+
+// trailingExit is a stop, which follows the highest high of a long or the lowest low of a short position.
+type trailingExit struct {
+	long     bool
+	best     float64
+	distance float64
+	percent  float64
+}
+
+// Update moves the best price with the high or low of the data event.
+func (e *trailingExit) Update(event DataEvent) {
+	_, high, low := priceRange(event)
+	if e.long {
+		e.best = math.Max(e.best, high)
+		return
+	}
+	e.best = math.Min(e.best, low)
+}
+
+// Stop returns the stop price.
+func (e *trailingExit) Stop() (float64, bool) {
+	distance := e.distance + e.percent*e.best
+	if e.long {
+		return e.best - distance, e.best-distance > 0
+	}
+	return e.best + distance, true
+}
+
+// Limit implements Exiter, a trailing stop sets no limit.
+func (e *trailingExit) Limit() (float64, bool) {
+	return 0, false
+}
+
+// Expired implements Exiter, a trailing stop never expires.
+func (e *trailingExit) Expired() bool {
+	return false
+}
+
+// timeExit expires after a number of bars.
+type timeExit struct {
+	bars, max int
+}
+
+// Update counts the bars.
+func (e *timeExit) Update(DataEvent) {
+	e.bars++
+}
+
+// Stop implements Exiter, a time exit sets no stop.
+func (e *timeExit) Stop() (float64, bool) {
+	return 0, false
+}
+
+// Limit implements Exiter, a time exit sets no limit.
+func (e *timeExit) Limit() (float64, bool) {
+	return 0, false
+}
+
+// Expired checks if the position is held for the number of bars.
+func (e *timeExit) Expired() bool {
+	return e.bars >= e.max
+}
+
+// offset returns the entry price moved by the distance in favour of the position,
+// a negative distance moves the price against the position.
+func offset(entry FillEvent, distance float64) float64 {
+	if entry.Direction() == SLD {
+		return entry.Price() - distance
+	}
+	return entry.Price() + distance
+}
+
+// priceRange returns the open, high and low price of a data event.
+// Data events which are not a bar, or a bar without these prices, use the price of the event.
+func priceRange(event DataEvent) (open, high, low float64) {
+	price := event.Price()
+	open, high, low = price, price, price
+
+	if bar, ok := event.(*Bar); ok {
+		if bar.Open > 0 {
+			open = bar.Open
+		}
+		if bar.High > 0 {
+			high = bar.High
+		}
+		if bar.Low > 0 {
+			low = bar.Low
+		}
+	}
+
+	return open, high, low
+}
+
+// taBars converts data events into bars for the ta package.
+func taBars(events []DataEvent) []ta.Bar {
+	bars := make([]ta.Bar, len(events))
+	for i, event := range events {
+		open, high, low := priceRange(event)
+		bars[i] = ta.Bar{Open: open, High: high, Low: low, Close: event.Price()}
+	}
+	return bars
+}
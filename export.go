@@ -0,0 +1,567 @@
+package gobacktest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ExportVersion is the version of the schema of the exported results.
+// It changes with every change of the fields or columns, which breaks existing readers.
+const ExportVersion = 1
+
+// Exporter writes the results of a backtest in a machine readable format.
+type Exporter interface {
+	WriteEquityCSV(io.Writer) error
+	WriteTransactionsCSV(io.Writer) error
+	WriteOrdersCSV(io.Writer) error
+	WriteTradesCSV(io.Writer) error
+	WritePositionsCSV(io.Writer) error
+	WriteMetricsCSV(io.Writer, PerformanceOptions) error
+	WriteJSON(io.Writer, PerformanceOptions) error
+	ExportCSV(dir string, opts PerformanceOptions) error
+	ExportJSON(path string, opts PerformanceOptions) error
+	WriteTearsheet(io.Writer, TearsheetOptions) error
+	ExportTearsheet(path string, opts TearsheetOptions) error
+}
+
+// export files written by ExportCSV
+const (
+	equityFile       = "equity.csv"
+	transactionsFile = "transactions.csv"
+	ordersFile       = "orders.csv"
+	tradesFile       = "trades.csv"
+	positionsFile    = "positions.csv"
+	metricsFile      = "metrics.csv"
+)
+
+// exportDocument is the JSON document of the results, the json tags are the schema of the export.
+type exportDocument struct {
+	Version      int                 `json:"version"`
+	Metrics      map[string]*float64 `json:"metrics"` // null if the metric is not available
+	Equity       []exportEquity      `json:"equity"`
+	Transactions []exportTransaction `json:"transactions"`
+	Orders       []exportOrder       `json:"orders"`
+	Trades       []exportTrade       `json:"trades"`
+	Positions    []exportPosition    `json:"positions"`
+}
+
+type exportEquity struct {
+	Time           time.Time `json:"time"`
+	Equity         float64   `json:"equity"`
+	Return         float64   `json:"return"`
+	Drawdown       float64   `json:"drawdown"`
+	Cash           float64   `json:"cash"`
+	LongExposure   float64   `json:"long_exposure"`
+	ShortExposure  float64   `json:"short_exposure"`
+	GrossLeverage  float64   `json:"gross_leverage"`
+	NetLeverage    float64   `json:"net_leverage"`
+	LongPositions  int       `json:"long_positions"`
+	ShortPositions int       `json:"short_positions"`
+}
+
+var exportEquityHeader = []string{"Time", "Equity", "Return", "Drawdown", "Cash", "LongExposure", "ShortExposure",
+	"GrossLeverage", "NetLeverage", "LongPositions", "ShortPositions"}
+
+func (e exportEquity) record() []string {
+	return []string{
+		formatTime(e.Time),
+		formatFloat(e.Equity),
+		formatFloat(e.Return),
+		formatFloat(e.Drawdown),
+		formatFloat(e.Cash),
+		formatFloat(e.LongExposure),
+		formatFloat(e.ShortExposure),
+		formatFloat(e.GrossLeverage),
+		formatFloat(e.NetLeverage),
+		strconv.Itoa(e.LongPositions),
+		strconv.Itoa(e.ShortPositions),
+	}
+}
+
+type exportTransaction struct {
+	Time        time.Time `json:"time"`
+	Symbol      string    `json:"symbol"`
+	Direction   string    `json:"direction"`
+	Qty         int64     `json:"qty"`
+	Price       float64   `json:"price"`
+	Commission  float64   `json:"commission"`
+	ExchangeFee float64   `json:"exchange_fee"`
+	Cost        float64   `json:"cost"`
+	Value       float64   `json:"value"`
+	NetValue    float64   `json:"net_value"`
+}
+
+var exportTransactionHeader = []string{"Time", "Symbol", "Direction", "Qty", "Price", "Commission", "ExchangeFee",
+	"Cost", "Value", "NetValue"}
+
+func (t exportTransaction) record() []string {
+	return []string{
+		formatTime(t.Time),
+		t.Symbol,
+		t.Direction,
+		strconv.FormatInt(t.Qty, 10),
+		formatFloat(t.Price),
+		formatFloat(t.Commission),
+		formatFloat(t.ExchangeFee),
+		formatFloat(t.Cost),
+		formatFloat(t.Value),
+		formatFloat(t.NetValue),
+	}
+}
+
+type exportOrder struct {
+	Time      time.Time `json:"time"`
+	ID        int       `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Direction string    `json:"direction"`
+	Qty       int64     `json:"qty"`
+	Limit     float64   `json:"limit"`
+	Stop      float64   `json:"stop"`
+	Status    string    `json:"status"`
+}
+
+var exportOrderHeader = []string{"Time", "ID", "Symbol", "Direction", "Qty", "Limit", "Stop", "Status"}
+
+func (o exportOrder) record() []string {
+	return []string{
+		formatTime(o.Time),
+		strconv.Itoa(o.ID),
+		o.Symbol,
+		o.Direction,
+		strconv.FormatInt(o.Qty, 10),
+		formatFloat(o.Limit),
+		formatFloat(o.Stop),
+		o.Status,
+	}
+}
+
+type exportTrade struct {
+	Symbol     string     `json:"symbol"`
+	Direction  string     `json:"direction"`
+	Entry      time.Time  `json:"entry"`
+	Exit       *time.Time `json:"exit"` // null for an open trade
+	Qty        int64      `json:"qty"`
+	EntryPrice float64    `json:"entry_price"`
+	ExitPrice  float64    `json:"exit_price"`
+	Cost       float64    `json:"cost"`
+	ProfitLoss float64    `json:"profit_loss"`
+	MAE        float64    `json:"mae"`
+	MFE        float64    `json:"mfe"`
+	Fills      int        `json:"fills"`
+}
+
+var exportTradeHeader = []string{"Symbol", "Direction", "Entry", "Exit", "Qty", "EntryPrice", "ExitPrice",
+	"Cost", "ProfitLoss", "MAE", "MFE", "Fills"}
+
+func (t exportTrade) record() []string {
+	var exit string
+	if t.Exit != nil {
+		exit = formatTime(*t.Exit)
+	}
+
+	return []string{
+		t.Symbol,
+		t.Direction,
+		formatTime(t.Entry),
+		exit,
+		strconv.FormatInt(t.Qty, 10),
+		formatFloat(t.EntryPrice),
+		formatFloat(t.ExitPrice),
+		formatFloat(t.Cost),
+		formatFloat(t.ProfitLoss),
+		formatFloat(t.MAE),
+		formatFloat(t.MFE),
+		strconv.Itoa(t.Fills),
+	}
+}
+
+type exportPosition struct {
+	Time             time.Time `json:"time"`
+	Symbol           string    `json:"symbol"`
+	Qty              int64     `json:"qty"`
+	AvgPrice         float64   `json:"avg_price"`
+	MarketPrice      float64   `json:"market_price"`
+	MarketValue      float64   `json:"market_value"`
+	UnrealProfitLoss float64   `json:"unreal_profit_loss"`
+}
+
+var exportPositionHeader = []string{"Time", "Symbol", "Qty", "AvgPrice", "MarketPrice", "MarketValue", "UnrealProfitLoss"}
+
+func (p exportPosition) record() []string {
+	return []string{
+		formatTime(p.Time),
+		p.Symbol,
+		strconv.FormatInt(p.Qty, 10),
+		formatFloat(p.AvgPrice),
+		formatFloat(p.MarketPrice),
+		formatFloat(p.MarketValue),
+		formatFloat(p.UnrealProfitLoss),
+	}
+}
+
+// exportMetric is a single metric of the summary, NaN if the metric is not available.
+type exportMetric struct {
+	name  string
+	value float64
+}
+
+// WriteEquityCSV writes the equity curve as csv with a header line.
+func (s Statistic) WriteEquityCSV(w io.Writer) error {
+	equity := s.exportEquity()
+	records := make([][]string, len(equity))
+	for i, e := range equity {
+		records[i] = e.record()
+	}
+	return writeCSV(w, exportEquityHeader, records)
+}
+
+// WriteTransactionsCSV writes the transactions as csv with a header line.
+func (s Statistic) WriteTransactionsCSV(w io.Writer) error {
+	transactions := s.exportTransactions()
+	records := make([][]string, len(transactions))
+	for i, t := range transactions {
+		records[i] = t.record()
+	}
+	return writeCSV(w, exportTransactionHeader, records)
+}
+
+// WriteOrdersCSV writes the orders of the event history as csv with a header line.
+func (s Statistic) WriteOrdersCSV(w io.Writer) error {
+	orders := s.exportOrders()
+	records := make([][]string, len(orders))
+	for i, o := range orders {
+		records[i] = o.record()
+	}
+	return writeCSV(w, exportOrderHeader, records)
+}
+
+// WriteTradesCSV writes the closed and open round trip trades as csv with a header line.
+// An open trade has an empty exit.
+func (s Statistic) WriteTradesCSV(w io.Writer) error {
+	trades := s.exportTrades()
+	records := make([][]string, len(trades))
+	for i, t := range trades {
+		records[i] = t.record()
+	}
+	return writeCSV(w, exportTradeHeader, records)
+}
+
+// WritePositionsCSV writes the open positions of each equity point as csv with a header line.
+func (s Statistic) WritePositionsCSV(w io.Writer) error {
+	positions := s.exportPositions()
+	records := make([][]string, len(positions))
+	for i, p := range positions {
+		records[i] = p.record()
+	}
+	return writeCSV(w, exportPositionHeader, records)
+}
+
+// WriteMetricsCSV writes the summary of the metrics as csv with a Metric and a Value column.
+// A metric, which is not available, has an empty value.
+func (s Statistic) WriteMetricsCSV(w io.Writer, opts PerformanceOptions) error {
+	metrics := s.exportMetrics(opts)
+	records := make([][]string, len(metrics))
+	for i, m := range metrics {
+		value := strconv.FormatFloat(m.value, 'f', -1, 64)
+		if math.IsNaN(m.value) {
+			value = ""
+		}
+		records[i] = []string{m.name, value}
+	}
+	return writeCSV(w, []string{"Metric", "Value"}, records)
+}
+
+// WriteJSON writes all results as a single JSON document with the version of the schema.
+// A metric, which is not available or infinite, is null.
+func (s Statistic) WriteJSON(w io.Writer, opts PerformanceOptions) error {
+	doc := exportDocument{
+		Version:      ExportVersion,
+		Metrics:      make(map[string]*float64),
+		Equity:       s.exportEquity(),
+		Transactions: s.exportTransactions(),
+		Orders:       s.exportOrders(),
+		Trades:       s.exportTrades(),
+		Positions:    s.exportPositions(),
+	}
+	for _, m := range s.exportMetrics(opts) {
+		if math.IsNaN(m.value) || math.IsInf(m.value, 0) {
+			doc.Metrics[m.name] = nil
+			continue
+		}
+		value := m.value
+		doc.Metrics[m.name] = &value
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ExportCSV writes the equity curve, transactions, orders, trades, positions and metrics
+// as separate csv files into the directory, which is created if it does not exist.
+func (s Statistic) ExportCSV(dir string, opts PerformanceOptions) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name  string
+		write func(io.Writer) error
+	}{
+		{equityFile, s.WriteEquityCSV},
+		{transactionsFile, s.WriteTransactionsCSV},
+		{ordersFile, s.WriteOrdersCSV},
+		{tradesFile, s.WriteTradesCSV},
+		{positionsFile, s.WritePositionsCSV},
+		{metricsFile, func(w io.Writer) error { return s.WriteMetricsCSV(w, opts) }},
+	}
+
+	for _, f := range files {
+		if err := writeFile(filepath.Join(dir, f.name), f.write); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportJSON writes all results as a single JSON document into the file.
+func (s Statistic) ExportJSON(path string, opts PerformanceOptions) error {
+	return writeFile(path, func(w io.Writer) error { return s.WriteJSON(w, opts) })
+}
+
+// exportEquity returns the rows of the equity curve.
+func (s Statistic) exportEquity() []exportEquity {
+	rows := make([]exportEquity, len(s.equity))
+	for i, e := range s.equity {
+		rows[i] = exportEquity{
+			Time:           e.timestamp,
+			Equity:         e.equity,
+			Return:         e.equityReturn,
+			Drawdown:       e.drawdown,
+			Cash:           e.cash,
+			LongExposure:   e.long,
+			ShortExposure:  e.short,
+			GrossLeverage:  e.GrossLeverage(),
+			NetLeverage:    e.NetLeverage(),
+			LongPositions:  e.longPositions,
+			ShortPositions: e.shortPositions,
+		}
+	}
+	return rows
+}
+
+// exportTransactions returns the rows of the transactions.
+func (s Statistic) exportTransactions() []exportTransaction {
+	rows := make([]exportTransaction, len(s.transactionHistory))
+	for i, f := range s.transactionHistory {
+		rows[i] = exportTransaction{
+			Time:        f.Time(),
+			Symbol:      f.Symbol(),
+			Direction:   directionName(f.Direction()),
+			Qty:         f.Qty(),
+			Price:       f.Price(),
+			Commission:  f.Commission(),
+			ExchangeFee: f.ExchangeFee(),
+			Cost:        f.Cost(),
+			Value:       f.Value(),
+			NetValue:    f.NetValue(),
+		}
+	}
+	return rows
+}
+
+// exportOrders returns the rows of the orders of the event history.
+func (s Statistic) exportOrders() []exportOrder {
+	rows := []exportOrder{}
+	for _, e := range s.eventHistory {
+		o, ok := e.(OrderEvent)
+		if !ok {
+			continue
+		}
+		rows = append(rows, exportOrder{
+			Time:      o.Time(),
+			ID:        o.ID(),
+			Symbol:    o.Symbol(),
+			Direction: directionName(o.Direction()),
+			Qty:       o.Qty(),
+			Limit:     o.Limit(),
+			Stop:      o.Stop(),
+			Status:    orderStatusName(o.Status()),
+		})
+	}
+	return rows
+}
+
+// exportTrades returns the rows of the closed trades followed by the open trades.
+func (s Statistic) exportTrades() []exportTrade {
+	trades := append([]Trade{}, s.Trades()...)
+	trades = append(trades, s.OpenTrades()...)
+
+	rows := []exportTrade{}
+	for _, t := range trades {
+		row := exportTrade{
+			Symbol:     t.Symbol,
+			Direction:  directionName(t.Direction),
+			Entry:      t.Entry,
+			Qty:        t.Qty,
+			EntryPrice: t.EntryPrice,
+			ExitPrice:  t.ExitPrice,
+			Cost:       t.Cost,
+			ProfitLoss: t.ProfitLoss,
+			MAE:        t.MAE,
+			MFE:        t.MFE,
+			Fills:      t.Fills,
+		}
+		if !t.IsOpen() {
+			exit := t.Exit
+			row.Exit = &exit
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// exportPositions returns the rows of the open positions of each equity point.
+func (s Statistic) exportPositions() []exportPosition {
+	rows := []exportPosition{}
+	for _, e := range s.equity {
+		for _, p := range e.holdings {
+			rows = append(rows, exportPosition{
+				Time:             e.timestamp,
+				Symbol:           p.Symbol,
+				Qty:              p.Qty,
+				AvgPrice:         p.AvgPrice,
+				MarketPrice:      p.MarketPrice,
+				MarketValue:      p.MarketValue,
+				UnrealProfitLoss: p.UnrealProfitLoss,
+			})
+		}
+	}
+	return rows
+}
+
+// exportMetrics returns the summary of the metrics in a fixed order.
+// The performance metrics are not available, if the equity curve is too short.
+func (s Statistic) exportMetrics(opts PerformanceOptions) []exportMetric {
+	perf, err := s.Performance(opts)
+	available := func(v float64) float64 {
+		if err != nil {
+			return math.NaN()
+		}
+		return v
+	}
+
+	totalReturn, err := s.TotalEquityReturn()
+	if err != nil {
+		totalReturn = math.NaN()
+	}
+
+	return []exportMetric{
+		{"total_return", totalReturn},
+		{"periods", available(float64(perf.Periods))},
+		{"periods_per_year", available(perf.PeriodsPerYear)},
+		{"cagr", available(perf.CAGR)},
+		{"volatility", available(perf.Volatility)},
+		{"downside_deviation", available(perf.DownsideDeviation)},
+		{"sharpe", available(perf.Sharpe)},
+		{"sortino", available(perf.Sortino)},
+		{"calmar", available(perf.Calmar)},
+		{"omega", available(perf.Omega)},
+		{"tail_ratio", available(perf.TailRatio)},
+		{"skew", available(perf.Skew)},
+		{"kurtosis", available(perf.Kurtosis)},
+		{"max_drawdown", s.MaxDrawdown()},
+		{"max_drawdown_days", math.Round(s.MaxDrawdownDuration().Hours() / 24)},
+		{"best_period", available(perf.BestPeriod)},
+		{"worst_period", available(perf.WorstPeriod)},
+		{"transactions", float64(len(s.transactionHistory))},
+		{"trades", float64(len(s.Trades()))},
+		{"win_rate", s.WinRate()},
+		{"average_win", s.AverageWin()},
+		{"average_loss", s.AverageLoss()},
+		{"profit_factor", s.ProfitFactor()},
+		{"expectancy", s.Expectancy()},
+		{"average_holding_days", math.Round(s.AverageHoldingTime().Hours()/24*math.Pow10(DP)) / math.Pow10(DP)},
+		{"max_consecutive_losses", float64(s.MaxConsecutiveLosses())},
+	}
+}
+
+// writeCSV writes the header and the records as csv.
+func writeCSV(w io.Writer, header []string, records [][]string) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	if err := out.WriteAll(records); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+// writeFile creates the file and passes it to the write function.
+func writeFile(path string, write func(io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// formatTime formats a time of the export.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// formatFloat formats a float of the export with the precision DP.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', DP, 64)
+}
+
+// directionName returns the name of the direction in the export.
+func directionName(d Direction) string {
+	switch d {
+	case BOT:
+		return "BOT"
+	case SLD:
+		return "SLD"
+	case HLD:
+		return "HLD"
+	case EXT:
+		return "EXT"
+	}
+	return strconv.Itoa(int(d))
+}
+
+// orderStatusName returns the name of the order status in the export.
+func orderStatusName(s OrderStatus) string {
+	switch s {
+	case OrderNone:
+		return "none"
+	case OrderNew:
+		return "new"
+	case OrderSubmitted:
+		return "submitted"
+	case OrderPartiallyFilled:
+		return "partially_filled"
+	case OrderFilled:
+		return "filled"
+	case OrderCanceled:
+		return "canceled"
+	case OrderCancelPending:
+		return "cancel_pending"
+	case OrderInvalid:
+		return "invalid"
+	}
+	return strconv.Itoa(int(s))
+}
@@ -0,0 +1,184 @@
+package gobacktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testHelperExportStatistic returns a statistic with a closed and an open trade.
+func testHelperExportStatistic() Statistic {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	stat := Statistic{}
+	stat.TrackEvent(&Order{Event: Event{timestamp: start, symbol: "A"}, id: 1, direction: BOT, qty: 10, status: OrderFilled})
+	stat.TrackTransaction(&Fill{Event: Event{timestamp: start, symbol: "A"}, direction: BOT, qty: 10, price: 10, cost: 1})
+	stat.TrackTransaction(&Fill{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "A"}, direction: SLD, qty: 10, price: 12, cost: 1})
+	stat.TrackTransaction(&Fill{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "B"}, direction: SLD, qty: 5, price: 20})
+
+	stat.equity = []EquityPoint{
+		{timestamp: start, equity: 1000, cash: 899, long: 100, longPositions: 1,
+			holdings: []PositionSnapshot{{Symbol: "A", Qty: 10, AvgPrice: 10, MarketPrice: 10, MarketValue: 100}}},
+		{timestamp: start.AddDate(0, 0, 1), equity: 1018, equityReturn: 0.018, cash: 1118, short: 100, shortPositions: 1,
+			holdings: []PositionSnapshot{{Symbol: "B", Qty: -5, AvgPrice: 20, MarketPrice: 20, MarketValue: 100}}},
+	}
+	return stat
+}
+
+func TestExportCSV(t *testing.T) {
+	stat := testHelperExportStatistic()
+
+	var testCases = []struct {
+		msg   string
+		write func(*bytes.Buffer) error
+		exp   string
+	}{
+		{"testing equity csv",
+			func(b *bytes.Buffer) error { return stat.WriteEquityCSV(b) },
+			"Time,Equity,Return,Drawdown,Cash,LongExposure,ShortExposure,GrossLeverage,NetLeverage,LongPositions,ShortPositions\n" +
+				"2018-01-01T00:00:00Z,1000.0000,0.0000,0.0000,899.0000,100.0000,0.0000,0.1000,0.1000,1,0\n" +
+				"2018-01-02T00:00:00Z,1018.0000,0.0180,0.0000,1118.0000,0.0000,100.0000,0.0982,-0.0982,0,1\n",
+		},
+		{"testing transactions csv",
+			func(b *bytes.Buffer) error { return stat.WriteTransactionsCSV(b) },
+			"Time,Symbol,Direction,Qty,Price,Commission,ExchangeFee,Cost,Value,NetValue\n" +
+				"2018-01-01T00:00:00Z,A,BOT,10,10.0000,0.0000,0.0000,1.0000,100.0000,101.0000\n" +
+				"2018-01-02T00:00:00Z,A,SLD,10,12.0000,0.0000,0.0000,1.0000,120.0000,119.0000\n" +
+				"2018-01-02T00:00:00Z,B,SLD,5,20.0000,0.0000,0.0000,0.0000,100.0000,100.0000\n",
+		},
+		{"testing orders csv",
+			func(b *bytes.Buffer) error { return stat.WriteOrdersCSV(b) },
+			"Time,ID,Symbol,Direction,Qty,Limit,Stop,Status\n" +
+				"2018-01-01T00:00:00Z,1,A,BOT,10,0.0000,0.0000,filled\n",
+		},
+		{"testing trades csv",
+			func(b *bytes.Buffer) error { return stat.WriteTradesCSV(b) },
+			"Symbol,Direction,Entry,Exit,Qty,EntryPrice,ExitPrice,Cost,ProfitLoss,MAE,MFE,Fills\n" +
+				"A,BOT,2018-01-01T00:00:00Z,2018-01-02T00:00:00Z,10,10.0000,12.0000,2.0000,18.0000,-1.0000,18.0000,2\n" +
+				"B,SLD,2018-01-02T00:00:00Z,,5,20.0000,0.0000,0.0000,0.0000,0.0000,0.0000,1\n",
+		},
+		{"testing positions csv",
+			func(b *bytes.Buffer) error { return stat.WritePositionsCSV(b) },
+			"Time,Symbol,Qty,AvgPrice,MarketPrice,MarketValue,UnrealProfitLoss\n" +
+				"2018-01-01T00:00:00Z,A,10,10.0000,10.0000,100.0000,0.0000\n" +
+				"2018-01-02T00:00:00Z,B,-5,20.0000,20.0000,100.0000,0.0000\n",
+		},
+		{"testing empty statistic",
+			func(b *bytes.Buffer) error { return Statistic{}.WriteEquityCSV(b) },
+			"Time,Equity,Return,Drawdown,Cash,LongExposure,ShortExposure,GrossLeverage,NetLeverage,LongPositions,ShortPositions\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		var b bytes.Buffer
+		err := tc.write(&b)
+		if (err != nil) || (b.String() != tc.exp) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v %v", tc.msg, tc.exp, b.String(), err)
+		}
+	}
+}
+
+func TestExportMetrics(t *testing.T) {
+	var b bytes.Buffer
+	if err := testHelperExportStatistic().WriteMetricsCSV(&b, PerformanceOptions{PeriodsPerYear: 252}); err != nil {
+		t.Fatalf("testing metrics csv: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	lines := strings.Split(b.String(), "\n")
+	var testCases = []struct {
+		msg     string
+		line    string
+		expLine string
+	}{
+		{"testing metrics header", lines[0], "Metric,Value"},
+		{"testing total return", lines[1], "total_return,0.018"},
+		{"testing periods", lines[2], "periods,1"},
+		{"testing trades", lines[19], "trades,1"},
+		{"testing infinite profit factor", lines[23], "profit_factor,+Inf"},
+	}
+
+	for _, tc := range testCases {
+		if tc.line != tc.expLine {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expLine, tc.line)
+		}
+	}
+
+	b.Reset()
+	if err := (Statistic{}).WriteMetricsCSV(&b, PerformanceOptions{}); (err != nil) || !strings.Contains(b.String(), "\nsharpe,\n") {
+		t.Errorf("testing metrics without equity: \nexpected %v, \nactual   %v %v", "sharpe,", b.String(), err)
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	stat := testHelperExportStatistic()
+
+	var b bytes.Buffer
+	if err := stat.WriteJSON(&b, PerformanceOptions{PeriodsPerYear: 252}); err != nil {
+		t.Fatalf("testing json: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	var doc exportDocument
+	if err := json.Unmarshal(b.Bytes(), &doc); err != nil {
+		t.Fatalf("testing json decode: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing version", doc.Version, ExportVersion},
+		{"testing equity", doc.Equity, stat.exportEquity()},
+		{"testing transactions", doc.Transactions, stat.exportTransactions()},
+		{"testing orders", doc.Orders, stat.exportOrders()},
+		{"testing trades", doc.Trades, stat.exportTrades()},
+		{"testing positions", doc.Positions, stat.exportPositions()},
+		{"testing metric", *doc.Metrics["total_return"], 0.018},
+		{"testing infinite metric", doc.Metrics["profit_factor"] == nil, true},
+		{"testing open trade exit", doc.Trades[1].Exit == nil, true},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
+
+func TestExportFiles(t *testing.T) {
+	stat := testHelperExportStatistic()
+	dir, err := ioutil.TempDir("", "gobacktest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := stat.ExportCSV(filepath.Join(dir, "csv"), PerformanceOptions{}); err != nil {
+		t.Errorf("testing csv export: \nexpected %v, \nactual   %v", nil, err)
+	}
+	for _, name := range []string{equityFile, transactionsFile, ordersFile, tradesFile, positionsFile, metricsFile} {
+		if _, err := ioutil.ReadFile(filepath.Join(dir, "csv", name)); err != nil {
+			t.Errorf("testing csv export %v: \nexpected %v, \nactual   %v", name, nil, err)
+		}
+	}
+
+	path := filepath.Join(dir, "results.json")
+	if err := stat.ExportJSON(path, PerformanceOptions{}); err != nil {
+		t.Errorf("testing json export: \nexpected %v, \nactual   %v", nil, err)
+	}
+	content, _ := ioutil.ReadFile(path)
+	var doc exportDocument
+	if err := json.Unmarshal(content, &doc); (err != nil) || (len(doc.Equity) != 2) {
+		t.Errorf("testing json export: \nexpected %v %v, \nactual   %v %v", nil, 2, err, len(doc.Equity))
+	}
+
+	if err := stat.ExportJSON(filepath.Join(dir, "missing", "results.json"), PerformanceOptions{}); err == nil {
+		t.Errorf("testing json export into missing directory: \nexpected %v, \nactual   %v", "error", err)
+	}
+}
@@ -32,3 +32,12 @@ func (m Metric) Get(key string) (float64, bool) {
 	value, ok := m[key]
 	return value, ok
 }
+
+// copy returns a copy of the metrics map.
+func (m Metric) copy() Metric {
+	metric := make(Metric, len(m))
+	for key, value := range m {
+		metric[key] = value
+	}
+	return metric
+}
@@ -0,0 +1,325 @@
+// Package montecarlo tests the robustness of a finished backtest. It simulates alternative paths
+// from the trades or the returns of the run and reports the distribution of the final equity,
+// the max drawdown and the Sharpe ratio of the paths.
+package montecarlo
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Resample defines how a simulated path is drawn from the run.
+type Resample int
+
+// Resample methods of the simulation.
+const (
+	Original       Resample = iota // keep the order of the trades, e.g. to only skip trades or jitter prices
+	Shuffle                        // random permutation of the trades
+	Bootstrap                      // draw the trades with replacement
+	BlockBootstrap                 // draw blocks of consecutive returns of the equity curve with replacement
+)
+
+// Simulation runs a Monte Carlo analysis on the closed trades or the equity curve of a finished backtest.
+// The same seed returns the same paths.
+type Simulation struct {
+	Statistic   gbt.StatisticHandler // statistic of the finished run
+	Resample    Resample
+	Runs        int     // number of simulated paths
+	Seed        int64   // seed of the random numbers
+	BlockSize   int     // number of returns of a block, 0 uses the cube root of the number of returns
+	Skip        float64 // share of trades skipped at random in each path, e.g. 0.1 for 10%
+	Jitter      float64 // maximum relative deviation of the entry and exit price of each trade, e.g. 0.001 for 0.1%
+	Confidence  float64 // level of the confidence intervals, 0 uses 0.95
+	Performance gbt.PerformanceOptions
+}
+
+// Path holds the metrics of a single simulated path.
+type Path struct {
+	FinalEquity float64
+	MaxDrawdown float64 // negative value
+	Sharpe      float64 // annualised
+}
+
+// Distribution is the distribution of a metric over all simulated paths.
+type Distribution struct {
+	Original float64 // value of the path of the run itself
+	Mean     float64
+	StdDev   float64
+	Median   float64
+	Lower    float64   // lower bound of the confidence interval
+	Upper    float64   // upper bound of the confidence interval
+	Values   []float64 // values of all paths in ascending order
+}
+
+// Percentile returns the value of the distribution at the percentile p between 0 and 1.
+func (d Distribution) Percentile(p float64) float64 {
+	if len(d.Values) == 0 {
+		return math.NaN()
+	}
+	return stat.Quantile(p, stat.Empirical, d.Values, nil)
+}
+
+// Below returns the share of the paths with a value below v, e.g. the probability of a loss.
+func (d Distribution) Below(v float64) float64 {
+	if len(d.Values) == 0 {
+		return math.NaN()
+	}
+	return float64(sort.SearchFloat64s(d.Values, v)) / float64(len(d.Values))
+}
+
+// Result is the report of the Monte Carlo analysis.
+type Result struct {
+	Paths       []Path // metrics of each path in the order of the simulation
+	FinalEquity Distribution
+	MaxDrawdown Distribution
+	Sharpe      Distribution
+}
+
+// Run simulates all paths and returns the distributions of their metrics.
+//
+// Trade paths start with the initial equity of the run and add the profit and loss of the drawn trades,
+// the Sharpe ratio is calculated from the returns of the trades and annualised with the number of trades per year.
+// Open trades are left out. Block bootstrap paths compound the drawn returns of the equity curve, the blocks wrap
+// around at the end of the curve, the Sharpe ratio is annualised with the periods per year of the performance options.
+// The risk free rate series of the performance options is not used.
+func (s *Simulation) Run() (Result, error) {
+	if s.Statistic == nil {
+		return Result{}, errors.New("could not simulate, no statistic set")
+	}
+	if s.Runs <= 0 {
+		return Result{}, errors.New("could not simulate, number of runs must be positive")
+	}
+	if (s.Skip < 0) || (s.Skip >= 1) {
+		return Result{}, errors.New("could not simulate, skip must be between 0 and 1")
+	}
+	if s.Jitter < 0 {
+		return Result{}, errors.New("could not simulate, jitter must not be negative")
+	}
+	confidence := s.Confidence
+	if confidence == 0 {
+		confidence = 0.95
+	}
+	if (confidence <= 0) || (confidence >= 1) {
+		return Result{}, errors.New("could not simulate, confidence must be between 0 and 1")
+	}
+
+	curver, ok := s.Statistic.(gbt.EquityCurver)
+	if !ok {
+		return Result{}, errors.New("could not simulate, statistic has no equity curve")
+	}
+	equity := curver.EquityCurve()
+	if len(equity) < 2 {
+		return Result{}, errors.New("could not simulate, not enough equity points")
+	}
+	initial := equity[0].Equity()
+	if initial <= 0 {
+		return Result{}, errors.New("could not simulate, no initial equity")
+	}
+	years := equity[len(equity)-1].Time().Sub(equity[0].Time()).Hours() / 24 / 365.25
+	if years <= 0 {
+		return Result{}, errors.New("could not simulate, equity curve has no duration")
+	}
+
+	r := rand.New(rand.NewSource(s.Seed))
+	var original Path
+	paths := make([]Path, s.Runs)
+
+	switch s.Resample {
+	case Original, Shuffle, Bootstrap:
+		resulter, ok := s.Statistic.(gbt.TradeResulter)
+		if !ok {
+			return Result{}, errors.New("could not simulate, statistic has no trades")
+		}
+		trades := resulter.Trades()
+		if len(trades) == 0 {
+			return Result{}, errors.New("could not simulate, no closed trades")
+		}
+		original = tradePath(initial, profits(trades, nil, 0), years, s.Performance.RiskFree)
+		for i := range paths {
+			paths[i] = tradePath(initial, profits(s.drawTrades(r, trades), r, s.Jitter), years, s.Performance.RiskFree)
+		}
+	case BlockBootstrap:
+		if (s.Skip != 0) || (s.Jitter != 0) {
+			return Result{}, errors.New("could not simulate, skip and jitter apply to trades and not to the block bootstrap")
+		}
+		returns := make([]float64, len(equity)-1)
+		for i := range returns {
+			if prev := equity[i].Equity(); prev != 0 {
+				returns[i] = equity[i+1].Equity()/prev - 1
+			}
+		}
+		periodsPerYear := s.Performance.PeriodsPerYear
+		if periodsPerYear <= 0 {
+			periodsPerYear = float64(len(returns)) / years
+		}
+		size := s.BlockSize
+		if size <= 0 {
+			size = int(math.Max(1, math.Round(math.Cbrt(float64(len(returns))))))
+		}
+		original = returnPath(initial, returns, periodsPerYear, s.Performance.RiskFree)
+		for i := range paths {
+			paths[i] = returnPath(initial, drawBlocks(r, returns, size), periodsPerYear, s.Performance.RiskFree)
+		}
+	default:
+		return Result{}, errors.New("could not simulate, unknown resample method")
+	}
+
+	result := Result{Paths: paths}
+	result.FinalEquity = distribution(original.FinalEquity, paths, confidence, func(p Path) float64 { return p.FinalEquity })
+	result.MaxDrawdown = distribution(original.MaxDrawdown, paths, confidence, func(p Path) float64 { return p.MaxDrawdown })
+	result.Sharpe = distribution(original.Sharpe, paths, confidence, func(p Path) float64 { return p.Sharpe })
+	return result, nil
+}
+
+// drawTrades returns the trades of a single path, reordered by the resample method and without the skipped trades.
+func (s *Simulation) drawTrades(r *rand.Rand, trades []gbt.Trade) []gbt.Trade {
+	drawn := make([]gbt.Trade, len(trades))
+	switch s.Resample {
+	case Shuffle:
+		for i, j := range r.Perm(len(trades)) {
+			drawn[i] = trades[j]
+		}
+	case Bootstrap:
+		for i := range drawn {
+			drawn[i] = trades[r.Intn(len(trades))]
+		}
+	default:
+		copy(drawn, trades)
+	}
+
+	if s.Skip == 0 {
+		return drawn
+	}
+	taken := drawn[:0]
+	for _, t := range drawn {
+		if r.Float64() >= s.Skip {
+			taken = append(taken, t)
+		}
+	}
+	return taken
+}
+
+// profits returns the profit and loss of the trades, with the entry and exit price of each trade
+// moved at random by up to the relative jitter.
+func profits(trades []gbt.Trade, r *rand.Rand, jitter float64) []float64 {
+	pl := make([]float64, len(trades))
+	for i, t := range trades {
+		pl[i] = t.ProfitLoss
+		if jitter == 0 {
+			continue
+		}
+		entry := float64(t.EntryQty()) * t.EntryPrice * jitter * (2*r.Float64() - 1)
+		exit := float64(t.ExitQty()) * t.ExitPrice * jitter * (2*r.Float64() - 1)
+		move := exit - entry
+		if t.Direction == gbt.SLD {
+			move = -move
+		}
+		pl[i] += move
+	}
+	return pl
+}
+
+// tradePath adds the profit and loss of the trades to the initial equity.
+// A path is ruined, if its equity drops to zero or below, it stops there with a final equity of 0.
+func tradePath(initial float64, pl []float64, years, riskFree float64) Path {
+	periodsPerYear := float64(len(pl)) / years
+	returns := make([]float64, 0, len(pl))
+	equity := initial
+	for _, v := range pl {
+		if equity+v <= 0 {
+			// the ruin loses the whole equity
+			returns = append(returns, -1)
+			return path(initial, returns, 0, periodsPerYear, riskFree)
+		}
+		returns = append(returns, v/equity)
+		equity += v
+	}
+	return path(initial, returns, equity, periodsPerYear, riskFree)
+}
+
+// returnPath compounds the returns starting with the initial equity.
+func returnPath(initial float64, returns []float64, periodsPerYear, riskFree float64) Path {
+	equity := initial
+	for _, r := range returns {
+		equity *= 1 + r
+	}
+	return path(initial, returns, equity, periodsPerYear, riskFree)
+}
+
+// path calculates the metrics of a path from its returns.
+func path(initial float64, returns []float64, final, periodsPerYear, riskFree float64) Path {
+	p := Path{FinalEquity: round(final)}
+
+	equity, high := initial, initial
+	for _, r := range returns {
+		equity *= 1 + r
+		high = math.Max(high, equity)
+		if drawdown := (equity - high) / high; drawdown < p.MaxDrawdown {
+			p.MaxDrawdown = drawdown
+		}
+	}
+	p.MaxDrawdown = round(p.MaxDrawdown)
+
+	if (len(returns) < 2) || (periodsPerYear <= 0) {
+		return p
+	}
+	rate := 0.0
+	if riskFree != 0 {
+		rate = math.Pow(1+riskFree, 1/periodsPerYear) - 1
+	}
+	excess := make([]float64, len(returns))
+	for i, r := range returns {
+		excess[i] = r - rate
+	}
+	if mean, stdDev := stat.MeanStdDev(excess, nil); stdDev > 0 {
+		p.Sharpe = round(mean / stdDev * math.Sqrt(periodsPerYear))
+	}
+	return p
+}
+
+// drawBlocks draws blocks of consecutive returns with replacement until the path has the length of the returns.
+// A block starting near the end wraps around to the first returns.
+func drawBlocks(r *rand.Rand, returns []float64, size int) []float64 {
+	drawn := make([]float64, 0, len(returns))
+	for len(drawn) < len(returns) {
+		start := r.Intn(len(returns))
+		for i := 0; (i < size) && (len(drawn) < len(returns)); i++ {
+			drawn = append(drawn, returns[(start+i)%len(returns)])
+		}
+	}
+	return drawn
+}
+
+// distribution returns the distribution of a metric of the paths.
+func distribution(original float64, paths []Path, confidence float64, value func(Path) float64) Distribution {
+	values := make([]float64, len(paths))
+	for i, p := range paths {
+		values[i] = value(p)
+	}
+	sort.Float64s(values)
+
+	d := Distribution{Original: original, Values: values}
+	mean, stdDev := stat.MeanStdDev(values, nil)
+	d.Mean = round(mean)
+	if !math.IsNaN(stdDev) {
+		d.StdDev = round(stdDev)
+	}
+	d.Median = round(d.Percentile(0.5))
+	d.Lower = round(d.Percentile((1 - confidence) / 2))
+	d.Upper = round(d.Percentile(1 - (1-confidence)/2))
+	return d
+}
+
+// round rounds a value to the precision of the statistic, without a negative zero.
+func round(v float64) float64 {
+	v = math.Round(v*math.Pow10(gbt.DP)) / math.Pow10(gbt.DP)
+	if v == 0 {
+		return 0
+	}
+	return v
+}
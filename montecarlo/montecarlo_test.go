@@ -0,0 +1,247 @@
+package montecarlo
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+	"github.com/dirkolbrich/gobacktest/strategy"
+)
+
+// testHelperStatistic runs a moving average cross backtest on a sine wave and returns its statistic.
+func testHelperStatistic(t *testing.T) gbt.StatisticHandler {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var stream []gbt.DataEvent
+	for i := 0; i < 300; i++ {
+		price := 100 + 10*math.Sin(float64(i)/15) + float64(i)/10
+		bar := &gbt.Bar{Open: price, High: price, Low: price, Close: price, Metric: gbt.Metric{}}
+		bar.SetSymbol("TEST")
+		bar.SetTime(start.AddDate(0, 0, i))
+		stream = append(stream, bar)
+	}
+	data := &gbt.Data{}
+	data.SetStream(stream)
+
+	test := gbt.New()
+	test.SetSymbols([]string{"TEST"})
+	test.SetData(data)
+	test.SetStrategy(strategy.MovingAverageCross(5, 20))
+	if err := test.Run(); err != nil {
+		t.Fatalf("testing backtest run: \nexpected %v, \nactual   %v", nil, err)
+	}
+	if trades := test.Stats().(gbt.TradeResulter).Trades(); len(trades) < 3 {
+		t.Fatalf("testing backtest trades: \nexpected %v, \nactual   %v", "at least 3 trades", len(trades))
+	}
+	return test.Stats()
+}
+
+func TestSimulationRun(t *testing.T) {
+	stats := testHelperStatistic(t)
+
+	var testCases = []struct {
+		msg         string
+		sim         Simulation
+		sameEquity  bool // every path ends with the final equity of the run
+		sameSharpe  bool // every path has the Sharpe ratio of the run, shuffled trades change the equity before each trade
+		expVariance bool // the final equity differs between the paths
+	}{
+		{"testing original order",
+			Simulation{Resample: Original, Runs: 50},
+			true, true, false,
+		},
+		{"testing shuffled trades",
+			Simulation{Resample: Shuffle, Runs: 50},
+			true, false, false,
+		},
+		{"testing bootstrapped trades",
+			Simulation{Resample: Bootstrap, Runs: 50},
+			false, false, true,
+		},
+		{"testing skipped trades",
+			Simulation{Resample: Original, Runs: 50, Skip: 0.3},
+			false, false, true,
+		},
+		{"testing jittered prices",
+			Simulation{Resample: Shuffle, Runs: 50, Jitter: 0.01},
+			false, false, true,
+		},
+		{"testing block bootstrap",
+			Simulation{Resample: BlockBootstrap, Runs: 50, BlockSize: 10},
+			false, false, true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc.sim.Statistic = stats
+		tc.sim.Seed = 42
+		result, err := tc.sim.Run()
+		if err != nil {
+			t.Fatalf("%v: \nexpected %v, \nactual   %v", tc.msg, nil, err)
+		}
+
+		// the same seed returns the same paths
+		again, _ := tc.sim.Run()
+		if !reflect.DeepEqual(result, again) {
+			t.Errorf("%v reproducible: \nexpected %v, \nactual   %v", tc.msg, result.Paths, again.Paths)
+		}
+
+		if len(result.Paths) != tc.sim.Runs {
+			t.Errorf("%v paths: \nexpected %v, \nactual   %v", tc.msg, tc.sim.Runs, len(result.Paths))
+		}
+		for _, d := range []Distribution{result.FinalEquity, result.MaxDrawdown, result.Sharpe} {
+			if !(d.Lower <= d.Median) || !(d.Median <= d.Upper) || (d.Values[0] > d.Lower) || (d.Values[len(d.Values)-1] < d.Upper) {
+				t.Errorf("%v confidence interval: \nexpected %v, \nactual   %v %v %v", tc.msg, "ordered bounds", d.Lower, d.Median, d.Upper)
+			}
+		}
+
+		var sameEquity, sameSharpe = true, true
+		for _, p := range result.Paths {
+			if math.Abs(p.FinalEquity-result.FinalEquity.Original) > 1e-6 {
+				sameEquity = false
+			}
+			if p.Sharpe != result.Sharpe.Original {
+				sameSharpe = false
+			}
+		}
+		if sameEquity != tc.sameEquity {
+			t.Errorf("%v final equity: \nexpected %v, \nactual   %v", tc.msg, tc.sameEquity, result.FinalEquity.Values)
+		}
+		if sameSharpe != tc.sameSharpe {
+			t.Errorf("%v sharpe: \nexpected %v, \nactual   %v", tc.msg, tc.sameSharpe, result.Sharpe.Values)
+		}
+		if (result.FinalEquity.StdDev > 0) != tc.expVariance {
+			t.Errorf("%v variance: \nexpected %v, \nactual   %v", tc.msg, tc.expVariance, result.FinalEquity.StdDev)
+		}
+	}
+}
+
+func TestSimulationRunOriginal(t *testing.T) {
+	stats := testHelperStatistic(t)
+
+	// the original trade path ends with the initial equity plus the profit and loss of all closed trades
+	result, err := (&Simulation{Statistic: stats, Runs: 1}).Run()
+	if err != nil {
+		t.Fatalf("testing original trade path: \nexpected %v, \nactual   %v", nil, err)
+	}
+	curve := stats.(gbt.EquityCurver).EquityCurve()
+	expEquity := curve[0].Equity()
+	for _, trade := range stats.(gbt.TradeResulter).Trades() {
+		expEquity += trade.ProfitLoss
+	}
+	if math.Abs(result.FinalEquity.Original-expEquity) > 1e-4 {
+		t.Errorf("testing original trade path: \nexpected %v, \nactual   %v", expEquity, result.FinalEquity.Original)
+	}
+
+	// the original return path is the equity curve of the run
+	result, err = (&Simulation{Statistic: stats, Resample: BlockBootstrap, Runs: 1}).Run()
+	if err != nil {
+		t.Fatalf("testing original return path: \nexpected %v, \nactual   %v", nil, err)
+	}
+	if math.Abs(result.FinalEquity.Original-curve[len(curve)-1].Equity()) > 1e-4 {
+		t.Errorf("testing original return path: \nexpected %v, \nactual   %v", curve[len(curve)-1].Equity(), result.FinalEquity.Original)
+	}
+	if result.MaxDrawdown.Original != round(stats.MaxDrawdown()) {
+		t.Errorf("testing original return path drawdown: \nexpected %v, \nactual   %v", round(stats.MaxDrawdown()), result.MaxDrawdown.Original)
+	}
+}
+
+// testCurveStatistic hides all methods of the statistic except the StatisticHandler and the EquityCurver.
+type testCurveStatistic struct {
+	gbt.StatisticHandler
+	gbt.EquityCurver
+}
+
+func TestSimulationRunErrors(t *testing.T) {
+	stats := testHelperStatistic(t)
+
+	var testCases = []struct {
+		msg string
+		sim Simulation
+	}{
+		{"testing without statistic", Simulation{Runs: 10}},
+		{"testing without runs", Simulation{Statistic: stats}},
+		{"testing skip of all trades", Simulation{Statistic: stats, Runs: 10, Skip: 1}},
+		{"testing negative jitter", Simulation{Statistic: stats, Runs: 10, Jitter: -0.1}},
+		{"testing invalid confidence", Simulation{Statistic: stats, Runs: 10, Confidence: 1.5}},
+		{"testing skip with block bootstrap", Simulation{Statistic: stats, Runs: 10, Resample: BlockBootstrap, Skip: 0.1}},
+		{"testing unknown resample", Simulation{Statistic: stats, Runs: 10, Resample: Resample(9)}},
+		{"testing statistic without trades", Simulation{Statistic: &gbt.Statistic{}, Runs: 10}},
+		{"testing statistic without trade results", Simulation{Statistic: testCurveStatistic{stats, stats.(gbt.EquityCurver)}, Runs: 10}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := tc.sim.Run(); err == nil {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, "error", err)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	var testCases = []struct {
+		msg     string
+		pl      []float64
+		expPath Path
+	}{
+		{"testing winning trades",
+			[]float64{100, 100},
+			Path{FinalEquity: 1200, MaxDrawdown: 0, Sharpe: 14.8492},
+		},
+		{"testing drawdown",
+			[]float64{100, -550, 450},
+			Path{FinalEquity: 1000, MaxDrawdown: -0.5, Sharpe: 0.2112},
+		},
+		{"testing ruin stops the path",
+			[]float64{100, -1200, 500},
+			Path{FinalEquity: 0, MaxDrawdown: -1, Sharpe: -0.5785},
+		},
+	}
+
+	for _, tc := range testCases {
+		path := tradePath(1000, tc.pl, float64(len(tc.pl)), 0)
+		if !reflect.DeepEqual(path, tc.expPath) {
+			t.Errorf("%v: \nexpected %+v, \nactual   %+v", tc.msg, tc.expPath, path)
+		}
+	}
+}
+
+func TestDrawBlocks(t *testing.T) {
+	returns := []float64{1, 2, 3, 4, 5, 6, 7}
+	drawn := drawBlocks(rand.New(rand.NewSource(1)), returns, 3)
+
+	if len(drawn) != len(returns) {
+		t.Fatalf("testing length of the blocks: \nexpected %v, \nactual   %v", len(returns), len(drawn))
+	}
+	// each block continues with the next return, wrapping around to the first
+	for i := 0; i < len(drawn); i += 3 {
+		for j := i + 1; (j < i+3) && (j < len(drawn)); j++ {
+			if exp := math.Mod(drawn[j-1], 7) + 1; drawn[j] != exp {
+				t.Errorf("testing block %v: \nexpected %v, \nactual   %v", drawn[i:], exp, drawn[j])
+			}
+		}
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	d := Distribution{Values: []float64{-2, -1, 0, 1, 2}}
+
+	var testCases = []struct {
+		msg      string
+		value    float64
+		expValue float64
+	}{
+		{"testing percentile", d.Percentile(0.5), 0},
+		{"testing lowest percentile", d.Percentile(0), -2},
+		{"testing share below zero", d.Below(0), 0.4},
+		{"testing share below the lowest value", d.Below(-2), 0},
+	}
+
+	for _, tc := range testCases {
+		if tc.value != tc.expValue {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package optimiser
+
+import (
+	"math"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// Metric returns a value of the statistic of a backtest, a higher value is a better result.
+// NaN marks a metric, which could not be calculated.
+type Metric func(gbt.StatisticHandler) float64
+
+// TotalReturn returns the total return of the equity curve.
+func TotalReturn() Metric {
+	return func(s gbt.StatisticHandler) float64 {
+		r, err := s.TotalEquityReturn()
+		if err != nil {
+			return math.NaN()
+		}
+		return r
+	}
+}
+
+// MaxDrawdown returns the maximum drawdown as negative value, the smallest drawdown ranks first.
+func MaxDrawdown() Metric {
+	return func(s gbt.StatisticHandler) float64 {
+		return s.MaxDrawdown()
+	}
+}
+
+// CAGR returns the compound annual growth rate.
+func CAGR(opts gbt.PerformanceOptions) Metric {
+	return performance(opts, func(p gbt.Performance) float64 { return p.CAGR })
+}
+
+// Sharpe returns the annualised Sharpe ratio.
+func Sharpe(opts gbt.PerformanceOptions) Metric {
+	return performance(opts, func(p gbt.Performance) float64 { return p.Sharpe })
+}
+
+// Sortino returns the annualised Sortino ratio.
+func Sortino(opts gbt.PerformanceOptions) Metric {
+	return performance(opts, func(p gbt.Performance) float64 { return p.Sortino })
+}
+
+// Calmar returns the CAGR divided by the max drawdown.
+func Calmar(opts gbt.PerformanceOptions) Metric {
+	return performance(opts, func(p gbt.Performance) float64 { return p.Calmar })
+}
+
+// ProfitFactor returns the gross profit divided by the gross loss of the trades.
+func ProfitFactor() Metric {
+	return trades(func(r gbt.TradeResulter) float64 { return r.ProfitFactor() })
+}
+
+// WinRate returns the share of winning trades.
+func WinRate() Metric {
+	return trades(func(r gbt.TradeResulter) float64 { return r.WinRate() })
+}
+
+// Expectancy returns the average profit and loss per trade.
+func Expectancy() Metric {
+	return trades(func(r gbt.TradeResulter) float64 { return r.Expectancy() })
+}
+
+// Trades returns the number of closed trades.
+func Trades() Metric {
+	return trades(func(r gbt.TradeResulter) float64 { return float64(len(r.Trades())) })
+}
+
+// trades returns a metric of the closed trades, NaN if the statistic does not implement TradeResulter.
+func trades(value func(gbt.TradeResulter) float64) Metric {
+	return func(s gbt.StatisticHandler) float64 {
+		r, ok := s.(gbt.TradeResulter)
+		if !ok {
+			return math.NaN()
+		}
+		return value(r)
+	}
+}
+
+// performance returns a metric of the performance summary, NaN if the statistic does not implement Performer.
+func performance(opts gbt.PerformanceOptions, value func(gbt.Performance) float64) Metric {
+	return func(s gbt.StatisticHandler) float64 {
+		performer, ok := s.(gbt.Performer)
+		if !ok {
+			return math.NaN()
+		}
+		p, err := performer.Performance(opts)
+		if err != nil {
+			return math.NaN()
+		}
+		return value(p)
+	}
+}
+
+// Constraint decides if the result of a backtest is acceptable.
+type Constraint func(gbt.StatisticHandler) bool
+
+// Above accepts a result with a value of the metric above the limit.
+func Above(m Metric, limit float64) Constraint {
+	return func(s gbt.StatisticHandler) bool {
+		return m(s) > limit
+	}
+}
+
+// Below accepts a result with a value of the metric below the limit.
+func Below(m Metric, limit float64) Constraint {
+	return func(s gbt.StatisticHandler) bool {
+		return m(s) < limit
+	}
+}
+
+// DrawdownLimit accepts a result with a max drawdown smaller than the limit, e.g. 0.2 for 20%.
+func DrawdownLimit(limit float64) Constraint {
+	return func(s gbt.StatisticHandler) bool {
+		return -s.MaxDrawdown() < limit
+	}
+}
+
+// MinTrades accepts a result with at least n closed trades.
+func MinTrades(n int) Constraint {
+	return func(s gbt.StatisticHandler) bool {
+		return Trades()(s) >= float64(n)
+	}
+}
@@ -0,0 +1,163 @@
+// Package optimiser runs a backtest for every parameter combination of a parameter space
+// on a pool of workers and ranks the results by a metric.
+package optimiser
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// Builder creates a fresh backtest for a parameter combination.
+// The optimiser sets the data of the backtest, the builder sets the symbols, strategy and other handlers.
+type Builder func(Params) (*gbt.Backtest, error)
+
+// Optimiser runs a backtest for each combination of a parameter space.
+type Optimiser struct {
+	Space   Space
+	Build   Builder
+	Data    gbt.DataHandler // loaded data, the data stream is shared read-only by all runs
+	Workers int             // number of backtests running at the same time, 0 uses the number of CPUs
+}
+
+// Result is the outcome of the backtest of a single parameter combination.
+type Result struct {
+	Index     int // position of the combination in the parameter space
+	Params    Params
+	Statistic gbt.StatisticHandler
+	Err       error
+}
+
+// Value returns the value of a metric of the result.
+func (r Result) Value(m Metric) float64 {
+	if (r.Err != nil) || (r.Statistic == nil) {
+		return math.NaN()
+	}
+	return m(r.Statistic)
+}
+
+// Results are the results of all parameter combinations.
+type Results []Result
+
+// Run runs a backtest for every combination of the space and returns the results
+// in the order of the combinations.
+func (o *Optimiser) Run() (Results, error) {
+	if o.Space == nil {
+		return nil, errors.New("could not optimise, no parameter space set")
+	}
+	if len(o.Space.Combinations()) == 0 {
+		return nil, errors.New("could not optimise, parameter space has no combinations")
+	}
+	if o.Build == nil {
+		return nil, errors.New("could not optimise, no backtest builder set")
+	}
+	if o.Data == nil {
+		return nil, errors.New("could not optimise, no data set")
+	}
+
+	return o.run(gbt.NewDataset(o.Data.Stream())), nil
+}
+
+// run runs a backtest for every combination of the space on a view of the dataset.
+func (o *Optimiser) run(dataset *gbt.Dataset) Results {
+	combinations := o.Space.Combinations()
+
+	workers := o.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	results := make(Results, len(combinations))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runBacktest(o.Build, i, combinations[i], dataset, time.Time{})
+			}
+		}()
+	}
+
+	for i := range combinations {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// runBacktest builds and runs the backtest of a single combination on its own view of the dataset.
+// Data events before the start warm up the strategy, a zero start trades from the first data event.
+func runBacktest(build Builder, i int, params Params, dataset *gbt.Dataset, start time.Time) Result {
+	result := Result{Index: i, Params: params}
+
+	test, err := build(params)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if test == nil {
+		result.Err = errors.New("could not run backtest, builder returned no backtest")
+		return result
+	}
+
+	test.SetData(dataset.View())
+	test.SetStart(start)
+	if err := test.Run(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Statistic = test.Stats()
+	return result
+}
+
+// Rank returns the successful results, which meet all constraints, ordered by the metric with the highest value first.
+// Results with an equal value keep the order of the parameter space, results without a value of the metric are left out.
+func (r Results) Rank(m Metric, constraints ...Constraint) Results {
+	// the value of each result is calculated once
+	type candidate struct {
+		result Result
+		value  float64
+	}
+
+	var candidates []candidate
+	for _, result := range r {
+		if result.Err != nil {
+			continue
+		}
+		value := result.Value(m)
+		if math.IsNaN(value) || !result.meets(constraints) {
+			continue
+		}
+		candidates = append(candidates, candidate{result: result, value: value})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].value > candidates[j].value
+	})
+
+	var ranked Results
+	for _, c := range candidates {
+		ranked = append(ranked, c.result)
+	}
+	return ranked
+}
+
+// meets checks the result against all constraints.
+func (r Result) meets(constraints []Constraint) bool {
+	for _, c := range constraints {
+		if !c(r.Statistic) {
+			return false
+		}
+	}
+	return true
+}
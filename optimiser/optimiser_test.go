@@ -0,0 +1,164 @@
+package optimiser
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+	"github.com/dirkolbrich/gobacktest/strategy"
+)
+
+// testHelperData returns a data handler with a loaded sine wave price series.
+func testHelperData() *gbt.Data {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var stream []gbt.DataEvent
+	for i := 0; i < 300; i++ {
+		price := 100 + 10*math.Sin(float64(i)/15) + float64(i)/10
+		bar := &gbt.Bar{Open: price, High: price, Low: price, Close: price, Metric: gbt.Metric{}}
+		bar.SetSymbol("TEST")
+		bar.SetTime(start.AddDate(0, 0, i))
+		stream = append(stream, bar)
+	}
+
+	data := &gbt.Data{}
+	data.SetStream(stream)
+	return data
+}
+
+// testHelperBuild builds a moving average cross backtest.
+func testHelperBuild(p Params) (*gbt.Backtest, error) {
+	if p.Int("short") >= p.Int("long") {
+		return nil, errors.New("short window must be smaller than the long window")
+	}
+
+	test := gbt.New()
+	test.SetSymbols([]string{"TEST"})
+	test.SetStrategy(strategy.MovingAverageCross(p.Int("short"), p.Int("long")))
+	return test, nil
+}
+
+func TestOptimiserRun(t *testing.T) {
+	space := Grid(Parameter{Name: "short", Min: 5, Max: 20, Step: 5}, Parameter{Name: "long", Min: 10, Max: 30, Step: 10})
+	data := testHelperData()
+
+	serial, err := (&Optimiser{Space: space, Build: testHelperBuild, Data: data, Workers: 1}).Run()
+	if err != nil {
+		t.Fatalf("testing serial run: \nexpected %v, \nactual   %v", nil, err)
+	}
+	parallel, err := (&Optimiser{Space: space, Build: testHelperBuild, Data: data, Workers: 4}).Run()
+	if err != nil {
+		t.Fatalf("testing parallel run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	var failed int
+	for i := range serial {
+		if serial[i].Err != nil {
+			failed++
+		}
+		if (serial[i].Index != i) || !reflect.DeepEqual(serial[i].Params, parallel[i].Params) ||
+			(serial[i].Value(TotalReturn()) != parallel[i].Value(TotalReturn())) && !math.IsNaN(serial[i].Value(TotalReturn())) {
+			t.Errorf("testing parallel result %v: \nexpected %v %v, \nactual   %v %v", i,
+				serial[i].Params, serial[i].Value(TotalReturn()), parallel[i].Params, parallel[i].Value(TotalReturn()))
+		}
+	}
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing number of results", len(serial), 12},
+		{"testing failed builds", failed, 4},
+		{"testing shared data stream is untouched", len(data.Stream()), 300},
+		{"testing metrics of the shared data", len(data.Stream()[299].(*gbt.Bar).Metric), 0},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
+
+func TestResultsRank(t *testing.T) {
+	results, err := (&Optimiser{
+		Space: Grid(Parameter{Name: "short", Min: 5, Max: 20, Step: 5}, Parameter{Name: "long", Min: 10, Max: 30, Step: 10}),
+		Build: testHelperBuild,
+		Data:  testHelperData(),
+	}).Run()
+	if err != nil {
+		t.Fatalf("testing run: \nexpected %v, \nactual   %v", nil, err)
+	}
+
+	ranked := results.Rank(TotalReturn())
+	if len(ranked) != 8 {
+		t.Errorf("testing ranked results: \nexpected %v, \nactual   %v", 8, len(ranked))
+	}
+	for i := 1; i < len(ranked); i++ {
+		if ranked[i-1].Value(TotalReturn()) < ranked[i].Value(TotalReturn()) {
+			t.Errorf("testing rank order: \nexpected %v >= %v", ranked[i-1].Value(TotalReturn()), ranked[i].Value(TotalReturn()))
+		}
+	}
+
+	constrained := results.Rank(TotalReturn(), MinTrades(3), DrawdownLimit(0.2))
+	for _, r := range constrained {
+		if (r.Value(Trades()) < 3) || (-r.Statistic.MaxDrawdown() >= 0.2) {
+			t.Errorf("testing constraints: \nexpected %v, \nactual   %v %v", "at least 3 trades and drawdown below 20%",
+				r.Value(Trades()), r.Statistic.MaxDrawdown())
+		}
+	}
+	if len(results.Rank(TotalReturn(), Above(Trades(), 1000))) != 0 {
+		t.Errorf("testing unmet constraint: \nexpected %v, \nactual   %v", 0, len(results.Rank(TotalReturn(), Above(Trades(), 1000))))
+	}
+}
+
+func TestOptimiserErrors(t *testing.T) {
+	space := Grid(Parameter{Name: "short", Min: 5})
+
+	var testCases = []struct {
+		msg       string
+		optimiser *Optimiser
+	}{
+		{"testing without space", &Optimiser{Build: testHelperBuild, Data: testHelperData()}},
+		{"testing without builder", &Optimiser{Space: space, Data: testHelperData()}},
+		{"testing without data", &Optimiser{Space: space, Build: testHelperBuild}},
+		{"testing negative random space", &Optimiser{Space: Random(-1, 1, Parameter{Name: "short", Min: 5, Max: 10}), Build: testHelperBuild, Data: testHelperData()}},
+		{"testing negative latin hypercube space", &Optimiser{Space: LatinHypercube(-1, 1, Parameter{Name: "short", Min: 5, Max: 10}), Build: testHelperBuild, Data: testHelperData()}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := tc.optimiser.Run(); err == nil {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, "error", err)
+		}
+	}
+}
+
+// testStatistic hides all methods of the statistic except the StatisticHandler.
+type testStatistic struct {
+	gbt.StatisticHandler
+}
+
+func TestMetricsOptional(t *testing.T) {
+	stats := testStatistic{&gbt.Statistic{}}
+
+	var testCases = []struct {
+		msg    string
+		metric Metric
+	}{
+		{"testing trade metric without TradeResulter", Trades()},
+		{"testing performance metric without Performer", Sharpe(gbt.PerformanceOptions{})},
+	}
+
+	for _, tc := range testCases {
+		if v := tc.metric(stats); !math.IsNaN(v) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, math.NaN(), v)
+		}
+	}
+	if MinTrades(0)(stats) {
+		t.Errorf("testing min trades without TradeResulter: \nexpected %v, \nactual   %v", false, true)
+	}
+}
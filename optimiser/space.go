@@ -0,0 +1,187 @@
+package optimiser
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Params is a single combination of parameter values.
+type Params map[string]float64
+
+// Float returns the value of a parameter.
+func (p Params) Float(name string) float64 {
+	return p[name]
+}
+
+// Int returns the value of a parameter rounded to an integer.
+func (p Params) Int(name string) int {
+	return int(math.Round(p[name]))
+}
+
+// String returns the parameters sorted by name, e.g. "long=50 short=10".
+func (p Params) String() string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf("%s=%s", name, strconv.FormatFloat(p[name], 'f', -1, 64))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Parameter defines the range of values of a single parameter.
+type Parameter struct {
+	Name    string
+	Min     float64
+	Max     float64
+	Step    float64 // step between the values of a grid, sampled values snap to the step if set
+	Integer bool    // round the values to integers, e.g. for window lengths
+}
+
+// value snaps a value of the range to the step and rounds it to an integer.
+func (p Parameter) value(v float64) float64 {
+	if p.Step > 0 {
+		v = p.Min + math.Round((v-p.Min)/p.Step)*p.Step
+	}
+	if p.Integer {
+		v = math.Round(v)
+	}
+	// avoid floating point artefacts of the steps, e.g. 0.30000000000000004
+	v = math.Round(v*1e10) / 1e10
+	if p.Max <= p.Min {
+		return v
+	}
+	return math.Min(math.Max(v, p.Min), p.Max)
+}
+
+// values returns the values of the grid from Min to Max with Step.
+func (p Parameter) values() []float64 {
+	if (p.Step <= 0) || (p.Max <= p.Min) {
+		return []float64{p.value(p.Min)}
+	}
+
+	var values []float64
+	for i := 0; ; i++ {
+		v := p.Min + float64(i)*p.Step
+		if v > p.Max+p.Step*1e-9 {
+			break
+		}
+		v = p.value(v)
+		// integer rounding can map two steps onto the same value
+		if (len(values) > 0) && (values[len(values)-1] == v) {
+			continue
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// Space is a parameter space, which creates the combinations to run.
+type Space interface {
+	Combinations() []Params
+}
+
+// grid is the space of all combinations of the parameter values.
+type grid struct {
+	params []Parameter
+}
+
+// Grid returns a space with every combination of the values from Min to Max with Step of each parameter.
+func Grid(params ...Parameter) Space {
+	return &grid{params: params}
+}
+
+// Combinations returns all combinations, the last parameter changes fastest.
+func (g *grid) Combinations() []Params {
+	if len(g.params) == 0 {
+		return nil
+	}
+
+	combinations := []Params{{}}
+	for _, p := range g.params {
+		var next []Params
+		for _, c := range combinations {
+			for _, v := range p.values() {
+				combination := Params{p.Name: v}
+				for name, value := range c {
+					combination[name] = value
+				}
+				next = append(next, combination)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// random is a space of uniformly sampled combinations.
+type random struct {
+	n      int
+	seed   int64
+	params []Parameter
+}
+
+// Random returns a space of n combinations with values drawn uniformly between Min and Max of each parameter.
+// The same seed returns the same combinations, a space with n <= 0 has no combinations.
+func Random(n int, seed int64, params ...Parameter) Space {
+	return &random{n: n, seed: seed, params: params}
+}
+
+// Combinations returns the sampled combinations.
+func (s *random) Combinations() []Params {
+	if s.n <= 0 {
+		return nil
+	}
+	r := rand.New(rand.NewSource(s.seed))
+
+	combinations := make([]Params, s.n)
+	for i := range combinations {
+		combinations[i] = Params{}
+		for _, p := range s.params {
+			combinations[i][p.Name] = p.value(p.Min + r.Float64()*(p.Max-p.Min))
+		}
+	}
+	return combinations
+}
+
+// latinHypercube is a space of stratified sampled combinations.
+type latinHypercube struct {
+	n      int
+	seed   int64
+	params []Parameter
+}
+
+// LatinHypercube returns a space of n combinations. The range of each parameter is divided into n equal
+// intervals and every interval is sampled exactly once, which covers the space more evenly than Random.
+// The same seed returns the same combinations, a space with n <= 0 has no combinations.
+func LatinHypercube(n int, seed int64, params ...Parameter) Space {
+	return &latinHypercube{n: n, seed: seed, params: params}
+}
+
+// Combinations returns the sampled combinations.
+func (s *latinHypercube) Combinations() []Params {
+	if s.n <= 0 {
+		return nil
+	}
+	r := rand.New(rand.NewSource(s.seed))
+
+	combinations := make([]Params, s.n)
+	for i := range combinations {
+		combinations[i] = Params{}
+	}
+	for _, p := range s.params {
+		for i, stratum := range r.Perm(s.n) {
+			v := p.Min + (float64(stratum)+r.Float64())/float64(s.n)*(p.Max-p.Min)
+			combinations[i][p.Name] = p.value(v)
+		}
+	}
+	return combinations
+}
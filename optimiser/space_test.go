@@ -0,0 +1,106 @@
+package optimiser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrid(t *testing.T) {
+	var testCases = []struct {
+		msg             string
+		space           Space
+		expCombinations []Params
+	}{
+		{"testing grid of two parameters",
+			Grid(Parameter{Name: "short", Min: 5, Max: 15, Step: 5}, Parameter{Name: "long", Min: 20, Max: 30, Step: 10}),
+			[]Params{
+				{"short": 5, "long": 20}, {"short": 5, "long": 30},
+				{"short": 10, "long": 20}, {"short": 10, "long": 30},
+				{"short": 15, "long": 20}, {"short": 15, "long": 30},
+			},
+		},
+		{"testing grid with float steps",
+			Grid(Parameter{Name: "stop", Min: 0.1, Max: 0.3, Step: 0.1}),
+			[]Params{{"stop": 0.1}, {"stop": 0.2}, {"stop": 0.3}},
+		},
+		{"testing grid with integer rounding",
+			Grid(Parameter{Name: "window", Min: 1, Max: 2, Step: 0.4, Integer: true}),
+			[]Params{{"window": 1}, {"window": 2}},
+		},
+		{"testing grid with a fixed parameter",
+			Grid(Parameter{Name: "window", Min: 10}),
+			[]Params{{"window": 10}},
+		},
+		{"testing grid without parameters",
+			Grid(),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		combinations := tc.space.Combinations()
+		if !reflect.DeepEqual(combinations, tc.expCombinations) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expCombinations, combinations)
+		}
+	}
+}
+
+func TestSampledSpaces(t *testing.T) {
+	params := []Parameter{
+		{Name: "short", Min: 0, Max: 10, Integer: true},
+		{Name: "stop", Min: 0.1, Max: 0.5, Step: 0.05},
+	}
+
+	var testCases = []struct {
+		msg   string
+		space func(seed int64) Space
+	}{
+		{"testing random space", func(seed int64) Space { return Random(10, seed, params...) }},
+		{"testing latin hypercube space", func(seed int64) Space { return LatinHypercube(10, seed, params...) }},
+	}
+
+	for _, tc := range testCases {
+		combinations := tc.space(1).Combinations()
+		if len(combinations) != 10 {
+			t.Errorf("%v length: \nexpected %v, \nactual   %v", tc.msg, 10, len(combinations))
+		}
+		if !reflect.DeepEqual(combinations, tc.space(1).Combinations()) {
+			t.Errorf("%v with same seed: \nexpected %v, \nactual   %v", tc.msg, combinations, tc.space(1).Combinations())
+		}
+		if reflect.DeepEqual(combinations, tc.space(2).Combinations()) {
+			t.Errorf("%v with other seed: \nexpected different combinations, \nactual   %v", tc.msg, combinations)
+		}
+
+		for _, c := range combinations {
+			short, stop := c.Float("short"), c.Float("stop")
+			steps := (stop - 0.1) / 0.05
+			if (short < 0) || (short > 10) || (short != float64(c.Int("short"))) ||
+				(stop < 0.1) || (stop > 0.5) || (steps-float64(int(steps+0.5)) > 1e-9) {
+				t.Errorf("%v values: \nexpected %v, \nactual   %v", tc.msg, "values within the range and on the step", c)
+			}
+		}
+	}
+
+	// a space with a non-positive number of combinations is empty
+	for _, space := range []Space{Random(-1, 1, params...), LatinHypercube(-1, 1, params...), LatinHypercube(0, 1, params...)} {
+		if combinations := space.Combinations(); len(combinations) != 0 {
+			t.Errorf("testing negative number of combinations: \nexpected %v, \nactual   %v", 0, len(combinations))
+		}
+	}
+
+	// every stratum of a parameter is sampled exactly once
+	strata := make(map[int]int)
+	for _, c := range LatinHypercube(5, 1, Parameter{Name: "x", Min: 0, Max: 1}).Combinations() {
+		strata[int(c.Float("x")*5)]++
+	}
+	if !reflect.DeepEqual(strata, map[int]int{0: 1, 1: 1, 2: 1, 3: 1, 4: 1}) {
+		t.Errorf("testing latin hypercube strata: \nexpected %v, \nactual   %v", "one value per stratum", strata)
+	}
+}
+
+func TestParamsString(t *testing.T) {
+	params := Params{"short": 10, "long": 50, "stop": 0.05}
+	if params.String() != "long=50 short=10 stop=0.05" {
+		t.Errorf("testing params string: \nexpected %v, \nactual   %v", "long=50 short=10 stop=0.05", params.String())
+	}
+}
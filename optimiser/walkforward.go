@@ -0,0 +1,198 @@
+package optimiser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+// WalkForward optimises the parameters on an in-sample window and applies the best parameters
+// to the following out-of-sample window. The windows move forward by the out-of-sample length
+// until the data is used up, the last out-of-sample window can be shorter.
+type WalkForward struct {
+	Space       Space
+	Build       Builder
+	Data        gbt.DataHandler // loaded data with a sorted data stream, shared by all runs
+	Workers     int             // number of backtests running at the same time, 0 uses the number of CPUs
+	Metric      Metric          // metric to rank the in-sample results, the total return if not set
+	Constraints []Constraint    // constraints of the in-sample results
+	Performance gbt.PerformanceOptions
+	InSample    int  // number of timestamps of the in-sample window
+	OutOfSample int  // number of timestamps of the out-of-sample window
+	Anchored    bool // the in-sample window always starts with the first timestamp and grows with each step
+}
+
+// Window is the in-sample optimisation and the out-of-sample run of a single walk-forward step.
+// The out-of-sample backtest warms up the strategy with the data events of the in-sample window,
+// trading and the statistic start with the out-of-sample window.
+type Window struct {
+	InSampleStart    time.Time
+	InSampleEnd      time.Time
+	OutOfSampleStart time.Time
+	OutOfSampleEnd   time.Time
+	Params           Params  // best parameters of the in-sample optimisation
+	InSample         float64 // value of the metric of the best in-sample result
+	OutOfSample      float64 // value of the metric of the out-of-sample run
+	InSampleCAGR     float64 // annualised return of the best in-sample result
+	OutOfSampleCAGR  float64 // annualised return of the out-of-sample run
+	Efficiency       float64 // out-of-sample CAGR divided by the in-sample CAGR
+	Statistic        gbt.StatisticHandler
+	Err              error
+}
+
+// Point is a point of the stitched out-of-sample equity curve.
+type Point struct {
+	Time   time.Time
+	Equity float64
+}
+
+// WalkForwardResult is the report of the walk-forward analysis.
+type WalkForwardResult struct {
+	Windows    []Window
+	Equity     []Point // out-of-sample equity curves, each window continues with the equity of the window before
+	Return     float64 // total return of the stitched equity curve
+	Efficiency float64 // walk-forward efficiency, mean out-of-sample CAGR divided by the mean in-sample CAGR
+}
+
+// Run runs the walk-forward analysis over all windows.
+func (w *WalkForward) Run() (WalkForwardResult, error) {
+	if (w.Space == nil) || (w.Build == nil) || (w.Data == nil) {
+		return WalkForwardResult{}, errors.New("could not walk forward, parameter space, builder and data must be set")
+	}
+	if len(w.Space.Combinations()) == 0 {
+		return WalkForwardResult{}, errors.New("could not walk forward, parameter space has no combinations")
+	}
+	if (w.InSample <= 0) || (w.OutOfSample <= 0) {
+		return WalkForwardResult{}, errors.New("could not walk forward, in-sample and out-of-sample length must be positive")
+	}
+
+	dataset := gbt.NewDataset(w.Data.Stream())
+	timestamps := dataset.Timestamps()
+	if len(timestamps) <= w.InSample {
+		return WalkForwardResult{}, fmt.Errorf("could not walk forward, %d timestamps do not cover the in-sample length of %d", len(timestamps), w.InSample)
+	}
+
+	metric := w.Metric
+	if metric == nil {
+		metric = TotalReturn()
+	}
+
+	var result WalkForwardResult
+	var inSample, outOfSample []float64
+	for start := 0; start+w.InSample < len(timestamps); start += w.OutOfSample {
+		from := start
+		if w.Anchored {
+			from = 0
+		}
+		split := start + w.InSample
+		to := split + w.OutOfSample
+		if to > len(timestamps) {
+			to = len(timestamps)
+		}
+
+		window := w.runWindow(dataset, timestamps, from, split, to, metric)
+		result.Windows = append(result.Windows, window)
+		if window.Err != nil {
+			continue
+		}
+
+		result.Equity = stitch(result.Equity, window.Statistic)
+		inSample = append(inSample, window.InSampleCAGR)
+		outOfSample = append(outOfSample, window.OutOfSampleCAGR)
+	}
+
+	if len(result.Equity) > 0 && result.Equity[0].Equity != 0 {
+		result.Return = round(result.Equity[len(result.Equity)-1].Equity/result.Equity[0].Equity - 1)
+	}
+	result.Efficiency = efficiency(mean(outOfSample), mean(inSample))
+
+	return result, nil
+}
+
+// runWindow optimises the parameters on the in-sample timestamps [from, split) and runs the best parameters
+// on the out-of-sample timestamps [split, to), warmed up with the in-sample timestamps.
+func (w *WalkForward) runWindow(dataset *gbt.Dataset, timestamps []time.Time, from, split, to int, metric Metric) Window {
+	window := Window{
+		InSampleStart:    timestamps[from],
+		InSampleEnd:      timestamps[split-1],
+		OutOfSampleStart: timestamps[split],
+		OutOfSampleEnd:   timestamps[to-1],
+	}
+
+	opt := &Optimiser{Space: w.Space, Build: w.Build, Workers: w.Workers}
+	results := opt.run(dataset.Window(window.InSampleStart, window.InSampleEnd))
+	ranked := results.Rank(metric, w.Constraints...)
+	if len(ranked) == 0 {
+		window.Err = errors.New("could not walk forward, no in-sample result meets the constraints")
+		return window
+	}
+	best := ranked[0]
+	window.Params = best.Params
+	window.InSample = round(best.Value(metric))
+	window.InSampleCAGR = round(CAGR(w.Performance)(best.Statistic))
+
+	oos := runBacktest(w.Build, 0, best.Params, dataset.Window(window.InSampleStart, window.OutOfSampleEnd), window.OutOfSampleStart)
+	if oos.Err != nil {
+		window.Err = oos.Err
+		return window
+	}
+	window.Statistic = oos.Statistic
+	window.OutOfSample = round(oos.Value(metric))
+	window.OutOfSampleCAGR = round(CAGR(w.Performance)(oos.Statistic))
+	window.Efficiency = efficiency(window.OutOfSampleCAGR, window.InSampleCAGR)
+
+	return window
+}
+
+// stitch appends the equity curve of the statistic, scaled to continue with the last equity of the curve.
+func stitch(curve []Point, s gbt.StatisticHandler) []Point {
+	curver, ok := s.(gbt.EquityCurver)
+	if !ok {
+		return curve
+	}
+	equity := curver.EquityCurve()
+	if (len(equity) == 0) || (equity[0].Equity() == 0) {
+		return curve
+	}
+
+	scale := 1.0
+	if len(curve) > 0 {
+		scale = curve[len(curve)-1].Equity / equity[0].Equity()
+	}
+	for _, e := range equity {
+		curve = append(curve, Point{Time: e.Time(), Equity: e.Equity() * scale})
+	}
+	return curve
+}
+
+// efficiency returns the out-of-sample relative to the in-sample value, 0 without in-sample value.
+func efficiency(outOfSample, inSample float64) float64 {
+	if (inSample == 0) || math.IsNaN(inSample) || math.IsNaN(outOfSample) {
+		return 0
+	}
+	return round(outOfSample / inSample)
+}
+
+// mean returns the mean of the values.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// round rounds a value to the precision of the statistic, without a negative zero.
+func round(v float64) float64 {
+	v = math.Round(v*math.Pow10(gbt.DP)) / math.Pow10(gbt.DP)
+	if v == 0 {
+		return 0
+	}
+	return v
+}
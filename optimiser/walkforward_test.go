@@ -0,0 +1,104 @@
+package optimiser
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	gbt "github.com/dirkolbrich/gobacktest"
+)
+
+func TestWalkForward(t *testing.T) {
+	day := func(i int) time.Time {
+		return time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+	}
+	space := Grid(Parameter{Name: "short", Min: 5, Max: 10, Step: 5}, Parameter{Name: "long", Min: 20, Max: 30, Step: 10})
+
+	var testCases = []struct {
+		msg        string
+		anchored   bool
+		expWindows [][4]time.Time
+	}{
+		{"testing rolling windows",
+			false,
+			[][4]time.Time{
+				{day(0), day(99), day(100), day(149)},
+				{day(50), day(149), day(150), day(199)},
+				{day(100), day(199), day(200), day(249)},
+				{day(150), day(249), day(250), day(299)},
+			},
+		},
+		{"testing anchored windows",
+			true,
+			[][4]time.Time{
+				{day(0), day(99), day(100), day(149)},
+				{day(0), day(149), day(150), day(199)},
+				{day(0), day(199), day(200), day(249)},
+				{day(0), day(249), day(250), day(299)},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		wf := &WalkForward{Space: space, Build: testHelperBuild, Data: testHelperData(), InSample: 100, OutOfSample: 50, Anchored: tc.anchored}
+		result, err := wf.Run()
+		if err != nil {
+			t.Fatalf("%v: \nexpected %v, \nactual   %v", tc.msg, nil, err)
+		}
+
+		var windows [][4]time.Time
+		growth := 1.0
+		for _, w := range result.Windows {
+			windows = append(windows, [4]time.Time{w.InSampleStart, w.InSampleEnd, w.OutOfSampleStart, w.OutOfSampleEnd})
+			if (w.Err != nil) || (len(w.Params) != 2) || (w.Statistic == nil) {
+				t.Errorf("%v window %v: \nexpected %v, \nactual   %v %v", tc.msg, w.OutOfSampleStart, "params and statistic", w.Params, w.Err)
+				continue
+			}
+			// the in-sample data events only warm up the out-of-sample run
+			if curve := w.Statistic.(gbt.EquityCurver).EquityCurve(); !curve[0].Time().Equal(w.OutOfSampleStart) {
+				t.Errorf("%v window %v: \nexpected equity from %v, \nactual   %v", tc.msg, w.OutOfSampleStart, w.OutOfSampleStart, curve[0].Time())
+			}
+			r, _ := w.Statistic.TotalEquityReturn()
+			growth *= 1 + r
+		}
+		if !reflect.DeepEqual(windows, tc.expWindows) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expWindows, windows)
+		}
+
+		// the stitched curve covers all out-of-sample timestamps and compounds the window returns
+		if (len(result.Equity) != 200) || !result.Equity[0].Time.Equal(day(100)) || !result.Equity[199].Time.Equal(day(299)) {
+			t.Errorf("%v stitched equity: \nexpected %v points from %v, \nactual   %v", tc.msg, 200, day(100), len(result.Equity))
+		}
+		if math.Abs(result.Return-(growth-1)) > 0.001 {
+			t.Errorf("%v stitched return: \nexpected %v, \nactual   %v", tc.msg, growth-1, result.Return)
+		}
+	}
+}
+
+func TestWalkForwardErrors(t *testing.T) {
+	space := Grid(Parameter{Name: "short", Min: 5}, Parameter{Name: "long", Min: 20})
+
+	var testCases = []struct {
+		msg          string
+		walkForward  *WalkForward
+		expErr       bool
+		expWindowErr bool
+	}{
+		{"testing without builder", &WalkForward{Space: space, Data: testHelperData(), InSample: 100, OutOfSample: 50}, true, false},
+		{"testing space without combinations", &WalkForward{Space: Random(0, 1, Parameter{Name: "short", Min: 5}), Build: testHelperBuild, Data: testHelperData(), InSample: 100, OutOfSample: 50}, true, false},
+		{"testing without out-of-sample length", &WalkForward{Space: space, Build: testHelperBuild, Data: testHelperData(), InSample: 100}, true, false},
+		{"testing in-sample longer than the data", &WalkForward{Space: space, Build: testHelperBuild, Data: testHelperData(), InSample: 300, OutOfSample: 50}, true, false},
+		{"testing unmet constraints",
+			&WalkForward{Space: space, Build: testHelperBuild, Data: testHelperData(), InSample: 200, OutOfSample: 100, Constraints: []Constraint{MinTrades(1000)}},
+			false, true},
+	}
+
+	for _, tc := range testCases {
+		result, err := tc.walkForward.Run()
+		windowErr := (len(result.Windows) > 0) && (result.Windows[0].Err != nil)
+		if ((err != nil) != tc.expErr) || (windowErr != tc.expWindowErr) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v", tc.msg, tc.expErr, tc.expWindowErr, err, windowErr)
+		}
+	}
+}
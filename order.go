@@ -41,6 +41,53 @@ type Order struct {
 	avgFillPrice float64
 	limitPrice   float64 // limit for the order
 	stopPrice    float64
+	group        *orderGroup // one-cancels-other group, nil if the order is not part of a group
+	legs         []*Order    // orders placed when this order is filled, e.g. the exits of a bracket
+}
+
+// orderGroup links the orders of a one-cancels-other group.
+// The order book assigns the id, when the first order of the group is placed.
+type orderGroup struct {
+	id int
+}
+
+// NewOCO combines the orders into a one-cancels-other group.
+// When one of the orders is filled, the exchange cancels all other orders of the group.
+func NewOCO(orders ...*Order) []*Order {
+	group := &orderGroup{}
+	for _, order := range orders {
+		order.group = group
+	}
+	return orders
+}
+
+// NewBracket attaches a stop loss and a take profit exit to an entry order. Both exits are placed
+// as a one-cancels-other group with the filled qty of the entry, once the entry order is filled.
+// A price of 0 omits the stop loss or the take profit.
+func NewBracket(entry *Order, stop, limit float64) *Order {
+	exit := SLD
+	if entry.direction == SLD {
+		exit = BOT
+	}
+
+	var legs []*Order
+	if stop > 0 {
+		legs = append(legs, &Order{
+			orderType: StopMarketOrder,
+			direction: exit,
+			stopPrice: stop,
+		})
+	}
+	if limit > 0 {
+		legs = append(legs, &Order{
+			orderType:  LimitOrder,
+			direction:  exit,
+			limitPrice: limit,
+		})
+	}
+
+	entry.legs = NewOCO(legs...)
+	return entry
 }
 
 // ID returns the id of the Order.
@@ -73,6 +120,16 @@ func (o *Order) SetQty(i int64) {
 	o.qty = i
 }
 
+// OrderType returns the type of an Order
+func (o Order) OrderType() OrderType {
+	return o.orderType
+}
+
+// SetOrderType sets the type of an Order
+func (o *Order) SetOrderType(t OrderType) {
+	o.orderType = t
+}
+
 // Status returns the status of an Order
 func (o Order) Status() OrderStatus {
 	return o.status
@@ -83,11 +140,35 @@ func (o Order) Limit() float64 {
 	return o.limitPrice
 }
 
+// SetLimit sets the limit price of an Order
+func (o *Order) SetLimit(price float64) {
+	o.limitPrice = price
+}
+
 // Stop returns the stop price of an Order
 func (o Order) Stop() float64 {
 	return o.stopPrice
 }
 
+// SetStop sets the stop price of an Order
+func (o *Order) SetStop(price float64) {
+	o.stopPrice = price
+}
+
+// Group returns the one-cancels-other group of an Order, 0 if the order is not part of a group
+// or the group is not placed in an order book yet
+func (o Order) Group() int {
+	if o.group == nil {
+		return 0
+	}
+	return o.group.id
+}
+
+// Legs returns the orders, which are placed when this order is filled
+func (o Order) Legs() []*Order {
+	return o.legs
+}
+
 // Cancel cancels an order
 func (o *Order) Cancel() {
 	o.status = OrderCancelPending
@@ -8,6 +8,7 @@ import (
 // OrderBook represents an order book.
 type OrderBook struct {
 	counter int
+	groups  int // counter of the one-cancels-other groups
 	orders  []OrderEvent
 	history []OrderEvent
 }
@@ -23,6 +24,12 @@ func (ob *OrderBook) Add(order OrderEvent) error {
 	return nil
 }
 
+// nextGroup returns a new order group id of the order book.
+func (ob *OrderBook) nextGroup() int {
+	ob.groups++
+	return ob.groups
+}
+
 // Remove an order from the order book, append it to history.
 func (ob *OrderBook) Remove(id int) error {
 	for i, order := range ob.orders {
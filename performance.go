@@ -0,0 +1,213 @@
+package gobacktest
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// PerformanceOptions configures the calculation of the performance metrics.
+type PerformanceOptions struct {
+	PeriodsPerYear float64               // number of bars per year, 0 derives it from the timestamps of the equity curve
+	RiskFree       float64               // annual risk free rate, e.g. 0.02 for 2%
+	RiskFreeSeries map[time.Time]float64 // annual risk free rate from a timestamp on, overrides RiskFree until the next rate of the series
+}
+
+// Performance is the summary of the performance metrics of the equity curve.
+// Ratios and the volatility are annualised with the number of periods per year,
+// the returns of the periods are measured against the risk free rate.
+type Performance struct {
+	Start             time.Time
+	End               time.Time
+	Periods           int     // number of returns
+	PeriodsPerYear    float64 // annualisation factor of the bar frequency
+	TotalReturn       float64
+	CAGR              float64 // compound annual growth rate
+	Volatility        float64 // annualised standard deviation of the returns
+	DownsideDeviation float64 // annualised root mean square of the returns below the risk free rate
+	Sharpe            float64
+	Sortino           float64
+	Calmar            float64 // CAGR divided by the max drawdown
+	Omega             float64 // sum of the returns above divided by the sum of the returns below the risk free rate
+	TailRatio         float64 // 95th percentile divided by the absolute 5th percentile of the returns
+	Skew              float64
+	Kurtosis          float64 // excess kurtosis
+	MaxDrawdown       float64 // negative value
+	BestPeriod        float64
+	BestPeriodTime    time.Time
+	WorstPeriod       float64
+	WorstPeriodTime   time.Time
+}
+
+// Performance calculates the performance metrics of the equity curve.
+// Several equity points of the same timestamp count as one period with the last equity point.
+func (s Statistic) Performance(opts PerformanceOptions) (Performance, error) {
+	points := s.equity
+	if len(points) < 2 {
+		return Performance{}, errors.New("could not calculate performance, not enough equity points")
+	}
+	first, last := points[0], points[len(points)-1]
+	if first.equity <= 0 {
+		return Performance{}, errors.New("could not calculate performance, no initial equity")
+	}
+
+	periodsPerYear, err := periodsPerYear(opts, points)
+	if err != nil {
+		return Performance{}, err
+	}
+
+	p := Performance{
+		Start:          first.timestamp,
+		End:            last.timestamp,
+		Periods:        len(points) - 1,
+		PeriodsPerYear: periodsPerYear,
+	}
+
+	returns := make([]float64, p.Periods)
+	excess := make([]float64, p.Periods)
+	rate := periodRates(opts, p.PeriodsPerYear)
+	high := first.equity
+	p.BestPeriod, p.WorstPeriod = math.Inf(-1), math.Inf(1)
+	for i := 1; i < len(points); i++ {
+		prev, e := points[i-1], points[i]
+		r := 0.0
+		if prev.equity != 0 {
+			r = (e.equity - prev.equity) / prev.equity
+		}
+		returns[i-1] = r
+		excess[i-1] = r - rate(e.timestamp)
+
+		// the first of equal rounded returns is the best or worst period
+		rounded := math.Round(r*math.Pow10(DP)) / math.Pow10(DP)
+		if rounded > p.BestPeriod {
+			p.BestPeriod, p.BestPeriodTime = rounded, e.timestamp
+		}
+		if rounded < p.WorstPeriod {
+			p.WorstPeriod, p.WorstPeriodTime = rounded, e.timestamp
+		}
+
+		high = math.Max(high, e.equity)
+		if drawdown := (e.equity - high) / high; drawdown < p.MaxDrawdown {
+			p.MaxDrawdown = drawdown
+		}
+	}
+
+	growth := last.equity / first.equity
+	p.TotalReturn = growth - 1
+	if growth > 0 {
+		p.CAGR = math.Pow(growth, p.PeriodsPerYear/float64(p.Periods)) - 1
+	} else {
+		p.CAGR = -1
+	}
+
+	annual := math.Sqrt(p.PeriodsPerYear)
+	mean, stdDev := stat.MeanStdDev(excess, nil)
+	if volatility := stat.StdDev(returns, nil); volatility > 0 {
+		p.Volatility = volatility * annual
+		p.Skew = stat.Skew(returns, nil)
+		p.Kurtosis = stat.ExKurtosis(returns, nil)
+	}
+	if stdDev > 0 {
+		p.Sharpe = mean / stdDev * annual
+	}
+
+	downside := downsideDeviation(excess)
+	p.DownsideDeviation = downside * annual
+	if downside > 0 {
+		p.Sortino = mean / downside * annual
+	}
+
+	if p.MaxDrawdown < 0 {
+		p.Calmar = p.CAGR / -p.MaxDrawdown
+	}
+
+	var gains, losses float64
+	for _, e := range excess {
+		if e > 0 {
+			gains += e
+			continue
+		}
+		losses -= e
+	}
+	switch {
+	case losses > 0:
+		p.Omega = gains / losses
+	case gains > 0:
+		p.Omega = math.Inf(1)
+	}
+
+	sorted := make([]float64, len(returns))
+	copy(sorted, returns)
+	sort.Float64s(sorted)
+	if lower := math.Abs(stat.Quantile(0.05, stat.Empirical, sorted, nil)); lower > 0 {
+		p.TailRatio = math.Abs(stat.Quantile(0.95, stat.Empirical, sorted, nil)) / lower
+	}
+
+	return p.round(), nil
+}
+
+// round rounds all metrics of the performance to the precision DP.
+func (p Performance) round() Performance {
+	for _, f := range []*float64{&p.PeriodsPerYear, &p.TotalReturn, &p.CAGR, &p.Volatility, &p.DownsideDeviation,
+		&p.Sharpe, &p.Sortino, &p.Calmar, &p.Omega, &p.TailRatio, &p.Skew, &p.Kurtosis,
+		&p.MaxDrawdown, &p.BestPeriod, &p.WorstPeriod} {
+		*f = math.Round(*f*math.Pow10(DP)) / math.Pow10(DP)
+	}
+	return p
+}
+
+// periodsPerYear returns the number of periods per year of the options,
+// or derives it from the timestamps of the equity points.
+func periodsPerYear(opts PerformanceOptions, points []EquityPoint) (float64, error) {
+	if opts.PeriodsPerYear > 0 {
+		return opts.PeriodsPerYear, nil
+	}
+
+	years := points[len(points)-1].timestamp.Sub(points[0].timestamp).Hours() / 24 / 365.25
+	if years <= 0 {
+		return 0, errors.New("could not calculate performance, equity curve has no duration")
+	}
+	return float64(len(points)-1) / years, nil
+}
+
+// periodRates returns the risk free rate of a single period at a timestamp. The annual rate is the latest rate
+// of the risk free rate series at or before the timestamp, RiskFree before the first rate of the series.
+func periodRates(opts PerformanceOptions, periodsPerYear float64) func(time.Time) float64 {
+	times := make([]time.Time, 0, len(opts.RiskFreeSeries))
+	for t := range opts.RiskFreeSeries {
+		times = append(times, t)
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].Before(times[j])
+	})
+
+	return func(t time.Time) float64 {
+		rate := opts.RiskFree
+		// the first rate of the series after the timestamp
+		if i := sort.Search(len(times), func(i int) bool { return times[i].After(t) }); i > 0 {
+			rate = opts.RiskFreeSeries[times[i-1]]
+		}
+		if rate == 0 {
+			return 0
+		}
+		return math.Pow(1+rate, 1/periodsPerYear) - 1
+	}
+}
+
+// downsideDeviation returns the root mean square of the negative values.
+func downsideDeviation(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range values {
+		if v < 0 {
+			sum += v * v
+		}
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
@@ -0,0 +1,123 @@
+package gobacktest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPerformance(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// equity curve with one point per day
+	curve := func(equity ...float64) Statistic {
+		s := Statistic{}
+		for i, e := range equity {
+			s.equity = append(s.equity, EquityPoint{timestamp: start.AddDate(0, 0, i), equity: e})
+		}
+		return s
+	}
+
+	var testCases = []struct {
+		msg     string
+		stat    Statistic
+		opts    PerformanceOptions
+		expPerf Performance
+		expErr  bool
+	}{
+		{"testing full metrics",
+			curve(100, 110, 99, 108.9, 119.79),
+			PerformanceOptions{PeriodsPerYear: 4},
+			Performance{
+				Start: start, End: start.AddDate(0, 0, 4), Periods: 4, PeriodsPerYear: 4,
+				TotalReturn: 0.1979, CAGR: 0.1979, Volatility: 0.2, DownsideDeviation: 0.1,
+				Sharpe: 1, Sortino: 2, Calmar: 1.979, Omega: 3, TailRatio: 1, Skew: -2, Kurtosis: 4,
+				MaxDrawdown: -0.1, BestPeriod: 0.1, BestPeriodTime: start.AddDate(0, 0, 1),
+				WorstPeriod: -0.1, WorstPeriodTime: start.AddDate(0, 0, 2),
+			},
+			false,
+		},
+		{"testing annual risk free rate",
+			curve(100, 110, 99, 108.9, 119.79),
+			PerformanceOptions{PeriodsPerYear: 4, RiskFree: 0.10381289},
+			Performance{
+				Start: start, End: start.AddDate(0, 0, 4), Periods: 4, PeriodsPerYear: 4,
+				TotalReturn: 0.1979, CAGR: 0.1979, Volatility: 0.2, DownsideDeviation: 0.125,
+				Sharpe: 0.5, Sortino: 0.8, Calmar: 1.979, Omega: 1.8, TailRatio: 1, Skew: -2, Kurtosis: 4,
+				MaxDrawdown: -0.1, BestPeriod: 0.1, BestPeriodTime: start.AddDate(0, 0, 1),
+				WorstPeriod: -0.1, WorstPeriodTime: start.AddDate(0, 0, 2),
+			},
+			false,
+		},
+		{"testing risk free rate series",
+			curve(100, 110, 99, 108.9, 119.79),
+			PerformanceOptions{PeriodsPerYear: 4, RiskFree: 0.10381289, RiskFreeSeries: map[time.Time]float64{
+				start.AddDate(0, 0, 1): 0, start.AddDate(0, 0, 2): 0, start.AddDate(0, 0, 3): 0, start.AddDate(0, 0, 4): 0,
+			}},
+			Performance{
+				Start: start, End: start.AddDate(0, 0, 4), Periods: 4, PeriodsPerYear: 4,
+				TotalReturn: 0.1979, CAGR: 0.1979, Volatility: 0.2, DownsideDeviation: 0.1,
+				Sharpe: 1, Sortino: 2, Calmar: 1.979, Omega: 3, TailRatio: 1, Skew: -2, Kurtosis: 4,
+				MaxDrawdown: -0.1, BestPeriod: 0.1, BestPeriodTime: start.AddDate(0, 0, 1),
+				WorstPeriod: -0.1, WorstPeriodTime: start.AddDate(0, 0, 2),
+			},
+			false,
+		},
+		{"testing latest rate of the risk free rate series",
+			curve(100, 110, 99, 108.9, 119.79),
+			PerformanceOptions{PeriodsPerYear: 4, RiskFree: 0.10381289, RiskFreeSeries: map[time.Time]float64{
+				start: 0,
+			}},
+			Performance{
+				Start: start, End: start.AddDate(0, 0, 4), Periods: 4, PeriodsPerYear: 4,
+				TotalReturn: 0.1979, CAGR: 0.1979, Volatility: 0.2, DownsideDeviation: 0.1,
+				Sharpe: 1, Sortino: 2, Calmar: 1.979, Omega: 3, TailRatio: 1, Skew: -2, Kurtosis: 4,
+				MaxDrawdown: -0.1, BestPeriod: 0.1, BestPeriodTime: start.AddDate(0, 0, 1),
+				WorstPeriod: -0.1, WorstPeriodTime: start.AddDate(0, 0, 2),
+			},
+			false,
+		},
+		{"testing risk free rate before the series",
+			curve(100, 110, 99, 108.9, 119.79),
+			PerformanceOptions{PeriodsPerYear: 4, RiskFree: 0.10381289, RiskFreeSeries: map[time.Time]float64{
+				start.AddDate(0, 0, 5): 0,
+			}},
+			Performance{
+				Start: start, End: start.AddDate(0, 0, 4), Periods: 4, PeriodsPerYear: 4,
+				TotalReturn: 0.1979, CAGR: 0.1979, Volatility: 0.2, DownsideDeviation: 0.125,
+				Sharpe: 0.5, Sortino: 0.8, Calmar: 1.979, Omega: 1.8, TailRatio: 1, Skew: -2, Kurtosis: 4,
+				MaxDrawdown: -0.1, BestPeriod: 0.1, BestPeriodTime: start.AddDate(0, 0, 1),
+				WorstPeriod: -0.1, WorstPeriodTime: start.AddDate(0, 0, 2),
+			},
+			false,
+		},
+		{"testing periods per year from timestamps",
+			Statistic{equity: []EquityPoint{
+				{timestamp: start, equity: 100},
+				{timestamp: start.Add(365*24*time.Hour + 6*time.Hour), equity: 121},
+			}},
+			PerformanceOptions{},
+			Performance{
+				Start: start, End: start.Add(365*24*time.Hour + 6*time.Hour), Periods: 1, PeriodsPerYear: 1,
+				TotalReturn: 0.21, CAGR: 0.21, BestPeriod: 0.21, BestPeriodTime: start.Add(365*24*time.Hour + 6*time.Hour),
+				WorstPeriod: 0.21, WorstPeriodTime: start.Add(365*24*time.Hour + 6*time.Hour),
+				Omega: math.Inf(1), TailRatio: 1,
+			},
+			false,
+		},
+		{"testing not enough equity points",
+			curve(100),
+			PerformanceOptions{},
+			Performance{},
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		perf, err := tc.stat.Performance(tc.opts)
+		if ((err != nil) != tc.expErr) || !reflect.DeepEqual(perf, tc.expPerf) {
+			t.Errorf("%v: \nexpected %+v %v, \nactual   %+v %v", tc.msg, tc.expPerf, tc.expErr, perf, err)
+		}
+	}
+}
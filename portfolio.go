@@ -16,6 +16,11 @@ type OnSignaler interface {
 	OnSignal(SignalEvent, DataHandler) (*Order, error)
 }
 
+// OnTargeter is an interface for the OnTargets method
+type OnTargeter interface {
+	OnTargets([]TargetEvent, DataHandler) ([]*Order, error)
+}
+
 // OnFiller is an interface for the OnFill method
 type OnFiller interface {
 	OnFill(FillEvent, DataHandler) (*Fill, error)
@@ -46,6 +51,16 @@ type Valuer interface {
 	Value() float64
 }
 
+// Holder returns the holdings of the portfolio
+type Holder interface {
+	Holdings() map[string]Position
+}
+
+// Risker returns the risk manager of the portfolio
+type Risker interface {
+	RiskManager() RiskHandler
+}
+
 // Booker defines methods for handling the order book of the portfolio
 type Booker interface {
 	OrderBook() ([]OrderEvent, bool)
@@ -61,6 +76,8 @@ type Portfolio struct {
 	transactions []FillEvent
 	sizeManager  SizeHandler
 	riskManager  RiskHandler
+	rebalancer   RebalanceHandler
+	taxLots      *TaxLots
 }
 
 // NewPortfolio creates a default portfolio with sensible defaults ready for use.
@@ -69,6 +86,7 @@ func NewPortfolio() *Portfolio {
 		initialCash: 100000,
 		sizeManager: &Size{DefaultSize: 100, DefaultValue: 1000},
 		riskManager: &Risk{},
+		rebalancer:  &Rebalancer{},
 	}
 }
 
@@ -88,8 +106,32 @@ func (p Portfolio) RiskManager() RiskHandler {
 }
 
 // SetRiskManager sets the risk manager to be used with the portfolio.
+// A risk manager implementing PortfolioSetter is made aware of the portfolio.
 func (p *Portfolio) SetRiskManager(risk RiskHandler) {
 	p.riskManager = risk
+	if s, ok := risk.(PortfolioSetter); ok {
+		s.SetPortfolio(p)
+	}
+}
+
+// Rebalancer returns the rebalance handler of the portfolio.
+func (p Portfolio) Rebalancer() RebalanceHandler {
+	return p.rebalancer
+}
+
+// SetRebalancer sets the rebalance handler to be used with the portfolio.
+func (p *Portfolio) SetRebalancer(rebalancer RebalanceHandler) {
+	p.rebalancer = rebalancer
+}
+
+// TaxLots returns the lot tracker of the portfolio, nil if no lots are tracked.
+func (p Portfolio) TaxLots() *TaxLots {
+	return p.taxLots
+}
+
+// SetTaxLots sets the lot tracker, which keeps every fill of the portfolio as tax lot.
+func (p *Portfolio) SetTaxLots(lots *TaxLots) {
+	p.taxLots = lots
 }
 
 // Reset the portfolio into a clean state with set initial cash.
@@ -97,6 +139,15 @@ func (p *Portfolio) Reset() error {
 	p.cash = 0
 	p.holdings = nil
 	p.transactions = nil
+	if r, ok := p.sizeManager.(Reseter); ok {
+		r.Reset()
+	}
+	if r, ok := p.riskManager.(Reseter); ok {
+		r.Reset()
+	}
+	if p.taxLots != nil {
+		p.taxLots.Reset()
+	}
 	return nil
 }
 
@@ -119,20 +170,64 @@ func (p *Portfolio) OnSignal(signal SignalEvent, data DataHandler) (*Order, erro
 		limitPrice: limit,
 	}
 
+	// signal already knows the qty, e.g. from a rebalancing of the strategy tree
+	if q, ok := signal.(Quantifier); ok {
+		initialOrder.SetQty(q.Qty())
+	}
+
 	// fetch latest known price for the symbol
 	latest := data.Latest(signal.Symbol())
 
+	// make the data history known to the size manager
+	if s, ok := p.sizeManager.(DataSetter); ok {
+		s.SetData(data)
+	}
+
+	// order could not be sized
 	sizedOrder, err := p.sizeManager.SizeOrder(initialOrder, latest, p)
 	if err != nil {
+		return nil, err
 	}
 
+	// order rejected by the risk manager
 	order, err := p.riskManager.EvaluateOrder(sizedOrder, latest, p.holdings)
 	if err != nil {
+		return nil, err
+	}
+
+	// attach the requested exits as bracket to the order
+	if b, ok := signal.(Bracketer); ok && ((b.StopLoss() > 0) || (b.TakeProfit() > 0)) {
+		order = NewBracket(order, b.StopLoss(), b.TakeProfit())
 	}
 
 	return order, nil
 }
 
+// OnTargets handles a set of incomming target signals.
+// The rebalancer creates the orders to reach the targets, each order is evaluated by the risk manager.
+func (p *Portfolio) OnTargets(targets []TargetEvent, data DataHandler) ([]*Order, error) {
+	rebalancer := p.rebalancer
+	if rebalancer == nil {
+		rebalancer = &Rebalancer{}
+	}
+
+	orders, err := rebalancer.Rebalance(targets, data, p)
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluated []*Order
+	for _, o := range orders {
+		order, err := p.riskManager.EvaluateOrder(o, data.Latest(o.Symbol()), p.holdings)
+		if err != nil {
+			continue
+		}
+		evaluated = append(evaluated, order)
+	}
+
+	return evaluated, nil
+}
+
 // OnFill handles an incomming fill event
 func (p *Portfolio) OnFill(fill FillEvent, data DataHandler) (*Fill, error) {
 	// Check for nil map, else initialise the map
@@ -163,6 +258,16 @@ func (p *Portfolio) OnFill(fill FillEvent, data DataHandler) (*Fill, error) {
 	// add fill to transactions
 	p.transactions = append(p.transactions, fill)
 
+	// match fill against the tax lots
+	if p.taxLots != nil {
+		p.taxLots.OnFill(fill)
+	}
+
+	// pass fill to a size manager, which tracks the trades
+	if t, ok := p.sizeManager.(FillTracker); ok {
+		t.TrackFill(fill)
+	}
+
 	f := fill.(*Fill)
 	return f, nil
 }
@@ -194,8 +299,13 @@ func (p Portfolio) IsShort(symbol string) (pos Position, ok bool) {
 	return pos, false
 }
 
-// Update updates the holding on a data event
+// Update updates the holding on a data event.
+// A risk manager implementing Updater is updated before the holdings.
 func (p *Portfolio) Update(d DataEvent) {
+	if u, ok := p.riskManager.(Updater); ok {
+		u.Update(d)
+	}
+
 	if pos, ok := p.IsInvested(d.Symbol()); ok {
 		pos.UpdateValue(d)
 		p.holdings[d.Symbol()] = pos
@@ -222,11 +332,15 @@ func (p Portfolio) Cash() float64 {
 	return p.cash
 }
 
-// Value return the current total value of the portfolio
+// Value return the current total value of the portfolio.
+// The market value of a short position is owed and reduces the value.
 func (p Portfolio) Value() float64 {
 	var holdingValue float64
 	for _, pos := range p.holdings {
-
+		if pos.qty < 0 {
+			holdingValue -= pos.marketValue
+			continue
+		}
 		holdingValue += pos.marketValue
 	}
 
@@ -696,6 +696,16 @@ func TestPortfolioValue(t *testing.T) {
 			},
 			10400,
 		},
+		{"testing value of short holdings",
+			&Portfolio{
+				cash: 10000,
+				holdings: map[string]Position{
+					"TEST.DE": {qty: -100, marketValue: 200},
+					"APPL":    {qty: 100, marketValue: 500},
+				},
+			},
+			10300,
+		},
 		{"testing value of empty holdings",
 			&Portfolio{},
 			0,
@@ -710,3 +720,45 @@ func TestPortfolioValue(t *testing.T) {
 		}
 	}
 }
+
+func TestPortfolioShortValue(t *testing.T) {
+	var exampleTime, _ = time.Parse("2006-01-02", "2017-06-01")
+	p := &Portfolio{cash: 1000}
+
+	var testCases = []struct {
+		msg      string
+		event    EventHandler // fill or data event
+		expCash  float64
+		expValue float64
+	}{
+		{"testing short entry",
+			&Fill{Event: Event{timestamp: exampleTime, symbol: "TEST.DE"}, direction: SLD, qty: 10, price: 10},
+			1100, 1000,
+		},
+		{"testing short on falling price",
+			&Bar{Event: Event{timestamp: exampleTime, symbol: "TEST.DE"}, Close: 8},
+			1100, 1020,
+		},
+		{"testing flip to long",
+			&Fill{Event: Event{timestamp: exampleTime, symbol: "TEST.DE"}, direction: BOT, qty: 15, price: 8},
+			980, 1020,
+		},
+		{"testing long on rising price",
+			&Bar{Event: Event{timestamp: exampleTime, symbol: "TEST.DE"}, Close: 9},
+			980, 1025,
+		},
+	}
+
+	for _, tc := range testCases {
+		switch e := tc.event.(type) {
+		case *Fill:
+			p.OnFill(e, &Data{})
+		case *Bar:
+			p.Update(e)
+		}
+
+		if (p.Cash() != tc.expCash) || (p.Value() != tc.expValue) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v", tc.msg, tc.expCash, tc.expValue, p.Cash(), p.Value())
+		}
+	}
+}
@@ -60,6 +60,15 @@ func (p *Position) UpdateValue(data DataEvent) {
 
 // internal function to update a position on a new fill event
 func (p *Position) update(fill FillEvent) {
+	// a fill crossing zero closes the position and opens a new position in the opposite direction
+	if ((p.qty > 0) && (fill.Direction() == SLD) && (fill.Qty() > p.qty)) ||
+		((p.qty < 0) && (fill.Direction() == BOT) && (fill.Qty() > -p.qty)) {
+		closing, opening := splitFill(fill, abs(p.qty))
+		p.update(closing)
+		p.update(opening)
+		return
+	}
+
 	// convert fill to internally used decimal numbers
 	fillQty := float64(fill.Qty())
 	fillPrice := fill.Price()
@@ -192,3 +201,30 @@ func (p *Position) updateValue(l float64) {
 	totalProfitLoss := realProfitLoss + unrealProfitLoss
 	p.totalProfitLoss = math.Round(totalProfitLoss*math.Pow10(DP)) / math.Pow10(DP)
 }
+
+// splitFill splits a fill into a fill of the given qty and a fill of the remaining qty,
+// the cost is split proportional to the qty.
+func splitFill(fill FillEvent, qty int64) (*Fill, *Fill) {
+	ratio := float64(qty) / float64(fill.Qty())
+
+	first := &Fill{
+		Event:       Event{timestamp: fill.Time(), symbol: fill.Symbol()},
+		direction:   fill.Direction(),
+		qty:         qty,
+		price:       fill.Price(),
+		commission:  fill.Commission() * ratio,
+		exchangeFee: fill.ExchangeFee() * ratio,
+		cost:        fill.Cost() * ratio,
+	}
+	second := &Fill{
+		Event:       Event{timestamp: fill.Time(), symbol: fill.Symbol()},
+		direction:   fill.Direction(),
+		qty:         fill.Qty() - qty,
+		price:       fill.Price(),
+		commission:  fill.Commission() - first.commission,
+		exchangeFee: fill.ExchangeFee() - first.exchangeFee,
+		cost:        fill.Cost() - first.cost,
+	}
+
+	return first, second
+}
@@ -1,6 +1,7 @@
 package gobacktest
 
 import (
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -136,6 +137,15 @@ func TestUpdatePosition(t *testing.T) {
 				realProfitLoss: 4, unrealProfitLoss: 6, totalProfitLoss: 10,
 			},
 		},
+		// a fill crossing zero is split into a closing and an opening fill with proportional cost,
+		// docs/positions-variations.xls instead values the new position at the net price of the old one.
+		// SLD 15@5 cost 5 against BOT 10@10 cost 5 is split into SLD 10@5 cost 3.3333 and SLD 5@5 cost 1.6667:
+		//   avgPrice 7 -> 5: the open short is valued at its fill price 5, not at (100 + 75) / 25
+		//   avgPriceNet 7 -> 4.6667: the short 5 receives 25 - 1.6667 = 23.3333, 23.3333 / 5
+		//   costBasis -52.5 -> -23.3333: -(25 - 1.6667) instead of -5 * 10.5
+		//   realProfitLoss -87.5 -> -58.3333: 50 - 3.3333 - 105 for the closed long 10
+		//   unrealProfitLoss 27.5 -> -1.6667: -25 + 23.3333, only the cost of the opening part
+		//   totalProfitLoss stays at -60
 		{"BOT position, selling, turning SLD position:",
 			posBOT,
 			&Fill{
@@ -147,12 +157,12 @@ func TestUpdatePosition(t *testing.T) {
 			&Position{
 				timestamp: exampleTime, symbol: "TEST.DE",
 				qty: -5, qtyBOT: 10, qtySLD: 15,
-				avgPrice: 7, avgPriceNet: 7, avgPriceBOT: 10, avgPriceSLD: 5,
+				avgPrice: 5, avgPriceNet: 4.6667, avgPriceBOT: 10, avgPriceSLD: 5,
 				value: -25, valueBOT: 100, valueSLD: 75,
 				netValue: -35, netValueBOT: 105, netValueSLD: 70,
 				marketPrice: 5, marketValue: 25,
-				commission: 8, exchangeFee: 2, cost: 10, costBasis: -52.5,
-				realProfitLoss: -87.5, unrealProfitLoss: 27.5, totalProfitLoss: -60,
+				commission: 8, exchangeFee: 2, cost: 10, costBasis: -23.3333,
+				realProfitLoss: -58.3333, unrealProfitLoss: -1.6667, totalProfitLoss: -60,
 			},
 		},
 		{"BOT position, exit stock:",
@@ -212,6 +222,13 @@ func TestUpdatePosition(t *testing.T) {
 				realProfitLoss: -20, unrealProfitLoss: -10, totalProfitLoss: -30,
 			},
 		},
+		// BOT 15@5 cost 5 against SLD 10@10 cost 5 is split into BOT 10@5 cost 3.3333 and BOT 5@5 cost 1.6667:
+		//   avgPrice 7 -> 5: the open long is valued at its fill price 5, not at (75 + 100) / 25
+		//   avgPriceNet 7 -> 5.3333: the long 5 costs 25 + 1.6667 = 26.6667, 26.6667 / 5
+		//   costBasis 47.5 -> 26.6667: 25 + 1.6667 instead of 5 * 9.5
+		//   realProfitLoss 62.5 -> 41.6667: 95 - 50 - 3.3333 for the closed short 10
+		//   unrealProfitLoss -22.5 -> -1.6667: 25 - 26.6667, only the cost of the opening part
+		//   totalProfitLoss stays at 40
 		{"SLD position, buying, turning BOT position:",
 			posSLD,
 			&Fill{
@@ -223,12 +240,12 @@ func TestUpdatePosition(t *testing.T) {
 			&Position{
 				timestamp: exampleTime, symbol: "TEST.DE",
 				qty: 5, qtyBOT: 15, qtySLD: 10,
-				avgPrice: 7, avgPriceNet: 7, avgPriceBOT: 5, avgPriceSLD: 10,
+				avgPrice: 5, avgPriceNet: 5.3333, avgPriceBOT: 5, avgPriceSLD: 10,
 				value: 25, valueBOT: 75, valueSLD: 100,
 				netValue: 15, netValueBOT: 80, netValueSLD: 95,
 				marketPrice: 5, marketValue: 25,
-				commission: 8, exchangeFee: 2, cost: 10, costBasis: 47.5,
-				realProfitLoss: 62.5, unrealProfitLoss: -22.5, totalProfitLoss: 40,
+				commission: 8, exchangeFee: 2, cost: 10, costBasis: 26.6667,
+				realProfitLoss: 41.6667, unrealProfitLoss: -1.6667, totalProfitLoss: 40,
 			},
 		},
 		{"SLD position, exit stock:",
@@ -464,6 +481,286 @@ func TestMultipleUpdatePosition(t *testing.T) {
 	}
 }
 
+// TestPositionVariations checks the scenarios of docs/positions-variations.csv, an export of docs/positions-variations.xls.
+// The flip scenarios split the fill crossing zero and deviate from the table, see TestUpdatePosition.
+func TestPositionVariations(t *testing.T) {
+	var exampleTime, _ = time.Parse("2006-01-02", "2017-06-01")
+
+	fill := func(direction Direction, qty int64, price, commission, exchangeFee float64) *Fill {
+		return &Fill{
+			Event:     Event{timestamp: exampleTime, symbol: "TEST.DE"},
+			direction: direction, qty: qty, price: price,
+			commission: commission, exchangeFee: exchangeFee, cost: commission + exchangeFee,
+		}
+	}
+
+	var testCases = []struct {
+		msg    string
+		fills  []*Fill
+		expPos Position
+	}{
+		{"long position:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1)},
+			Position{
+				qty: 10, qtyBOT: 10, qtySLD: 0,
+				avgPrice: 10, avgPriceNet: 10.5, avgPriceBOT: 10, avgPriceSLD: 0,
+				value: -100, valueBOT: 100, valueSLD: 0,
+				netValue: -105, netValueBOT: 105, netValueSLD: 0,
+				marketPrice: 10, marketValue: 100,
+				commission: 4, exchangeFee: 1, cost: 5, costBasis: 105,
+				realProfitLoss: 0, unrealProfitLoss: -5, totalProfitLoss: -5,
+			},
+		},
+		{"long position, BOT 15:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(BOT, 15, 15, 6, 1)},
+			Position{
+				qty: 25, qtyBOT: 25, qtySLD: 0,
+				avgPrice: 13, avgPriceNet: 13.48, avgPriceBOT: 13, avgPriceSLD: 0,
+				value: -325, valueBOT: 325, valueSLD: 0,
+				netValue: -337, netValueBOT: 337, netValueSLD: 0,
+				marketPrice: 15, marketValue: 375,
+				commission: 10, exchangeFee: 2, cost: 12, costBasis: 337,
+				realProfitLoss: 0, unrealProfitLoss: 38, totalProfitLoss: 38,
+			},
+		},
+		{"long position, SLD 6:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(SLD, 6, 12, 4, 1)},
+			Position{
+				qty: 4, qtyBOT: 10, qtySLD: 6,
+				avgPrice: 10.75, avgPriceNet: 10.75, avgPriceBOT: 10, avgPriceSLD: 12,
+				value: -28, valueBOT: 100, valueSLD: 72,
+				netValue: -38, netValueBOT: 105, netValueSLD: 67,
+				marketPrice: 12, marketValue: 48,
+				commission: 8, exchangeFee: 2, cost: 10, costBasis: 42,
+				realProfitLoss: 4, unrealProfitLoss: 6, totalProfitLoss: 10,
+			},
+		},
+		// the table has avgPrice 7, avgPriceNet 7, costBasis -52.5, realProfitLoss -87.5 and unrealProfitLoss 27.5
+		{"long position, SLD 15:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(SLD, 15, 5, 4, 1)},
+			Position{
+				qty: -5, qtyBOT: 10, qtySLD: 15,
+				avgPrice: 5, avgPriceNet: 4.6667, avgPriceBOT: 10, avgPriceSLD: 5,
+				value: -25, valueBOT: 100, valueSLD: 75,
+				netValue: -35, netValueBOT: 105, netValueSLD: 70,
+				marketPrice: 5, marketValue: 25,
+				commission: 8, exchangeFee: 2, cost: 10, costBasis: -23.3333,
+				realProfitLoss: -58.3333, unrealProfitLoss: -1.6667, totalProfitLoss: -60,
+			},
+		},
+		{"long position, SLD 10:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(SLD, 10, 12, 5, 1)},
+			Position{
+				qty: 0, qtyBOT: 10, qtySLD: 10,
+				avgPrice: 11, avgPriceNet: 10.95, avgPriceBOT: 10, avgPriceSLD: 12,
+				value: 20, valueBOT: 100, valueSLD: 120,
+				netValue: 9, netValueBOT: 105, netValueSLD: 114,
+				marketPrice: 12, marketValue: 0,
+				commission: 9, exchangeFee: 2, cost: 11, costBasis: 0,
+				realProfitLoss: 9, unrealProfitLoss: 0, totalProfitLoss: 9,
+			},
+		},
+		{"short position:",
+			[]*Fill{fill(SLD, 10, 10, 4, 1)},
+			Position{
+				qty: -10, qtyBOT: 0, qtySLD: 10,
+				avgPrice: 10, avgPriceNet: 9.5, avgPriceBOT: 0, avgPriceSLD: 10,
+				value: 100, valueBOT: 0, valueSLD: 100,
+				netValue: 95, netValueBOT: 0, netValueSLD: 95,
+				marketPrice: 10, marketValue: 100,
+				commission: 4, exchangeFee: 1, cost: 5, costBasis: -95,
+				realProfitLoss: 0, unrealProfitLoss: -5, totalProfitLoss: -5,
+			},
+		},
+		{"short position, SLD 15:",
+			[]*Fill{fill(SLD, 10, 10, 4, 1), fill(SLD, 15, 15, 6, 1)},
+			Position{
+				qty: -25, qtyBOT: 0, qtySLD: 25,
+				avgPrice: 13, avgPriceNet: 12.52, avgPriceBOT: 0, avgPriceSLD: 13,
+				value: 325, valueBOT: 0, valueSLD: 325,
+				netValue: 313, netValueBOT: 0, netValueSLD: 313,
+				marketPrice: 15, marketValue: 375,
+				commission: 10, exchangeFee: 2, cost: 12, costBasis: -313,
+				realProfitLoss: 0, unrealProfitLoss: -62, totalProfitLoss: -62,
+			},
+		},
+		{"short position, BOT 6:",
+			[]*Fill{fill(SLD, 10, 10, 4, 1), fill(BOT, 6, 12, 4, 1)},
+			Position{
+				qty: -4, qtyBOT: 6, qtySLD: 10,
+				avgPrice: 10.75, avgPriceNet: 10.75, avgPriceBOT: 12, avgPriceSLD: 10,
+				value: 28, valueBOT: 72, valueSLD: 100,
+				netValue: 18, netValueBOT: 77, netValueSLD: 95,
+				marketPrice: 12, marketValue: 48,
+				commission: 8, exchangeFee: 2, cost: 10, costBasis: -38,
+				realProfitLoss: -20, unrealProfitLoss: -10, totalProfitLoss: -30,
+			},
+		},
+		// the table has avgPrice 7, avgPriceNet 7, costBasis 47.5, realProfitLoss 62.5 and unrealProfitLoss -22.5
+		{"short position, BOT 15:",
+			[]*Fill{fill(SLD, 10, 10, 4, 1), fill(BOT, 15, 5, 4, 1)},
+			Position{
+				qty: 5, qtyBOT: 15, qtySLD: 10,
+				avgPrice: 5, avgPriceNet: 5.3333, avgPriceBOT: 5, avgPriceSLD: 10,
+				value: 25, valueBOT: 75, valueSLD: 100,
+				netValue: 15, netValueBOT: 80, netValueSLD: 95,
+				marketPrice: 5, marketValue: 25,
+				commission: 8, exchangeFee: 2, cost: 10, costBasis: 26.6667,
+				realProfitLoss: 41.6667, unrealProfitLoss: -1.6667, totalProfitLoss: 40,
+			},
+		},
+		{"short position, BOT 10:",
+			[]*Fill{fill(SLD, 10, 10, 4, 1), fill(BOT, 10, 12, 5, 1)},
+			Position{
+				qty: 0, qtyBOT: 10, qtySLD: 10,
+				avgPrice: 11, avgPriceNet: 11.05, avgPriceBOT: 12, avgPriceSLD: 10,
+				value: -20, valueBOT: 120, valueSLD: 100,
+				netValue: -31, netValueBOT: 126, netValueSLD: 95,
+				marketPrice: 12, marketValue: 0,
+				commission: 9, exchangeFee: 2, cost: 11, costBasis: 0,
+				realProfitLoss: -31, unrealProfitLoss: 0, totalProfitLoss: -31,
+			},
+		},
+		{"sequence of 2 fills:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(BOT, 15, 15, 6, 1)},
+			Position{
+				qty: 25, qtyBOT: 25, qtySLD: 0,
+				avgPrice: 13, avgPriceNet: 13.48, avgPriceBOT: 13, avgPriceSLD: 0,
+				value: -325, valueBOT: 325, valueSLD: 0,
+				netValue: -337, netValueBOT: 337, netValueSLD: 0,
+				marketPrice: 15, marketValue: 375,
+				commission: 10, exchangeFee: 2, cost: 12, costBasis: 337,
+				realProfitLoss: 0, unrealProfitLoss: 38, totalProfitLoss: 38,
+			},
+		},
+		{"sequence of 3 fills:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(BOT, 15, 15, 6, 1), fill(SLD, 18, 20, 8, 1)},
+			Position{
+				qty: 7, qtyBOT: 25, qtySLD: 18,
+				avgPrice: 15.9302, avgPriceNet: 16, avgPriceBOT: 13, avgPriceSLD: 20,
+				value: 35, valueBOT: 325, valueSLD: 360,
+				netValue: 14, netValueBOT: 337, netValueSLD: 351,
+				marketPrice: 20, marketValue: 140,
+				commission: 18, exchangeFee: 3, cost: 21, costBasis: 94.36,
+				realProfitLoss: 108.36, unrealProfitLoss: 45.64, totalProfitLoss: 154,
+			},
+		},
+		{"sequence of 4 fills:",
+			[]*Fill{fill(BOT, 10, 10, 4, 1), fill(BOT, 15, 15, 6, 1), fill(SLD, 18, 20, 8, 1), fill(BOT, 12, 18, 7, 1)},
+			Position{
+				qty: 19, qtyBOT: 37, qtySLD: 18,
+				avgPrice: 17.2375, avgPriceNet: 17.6842, avgPriceBOT: 14.6216, avgPriceSLD: 20,
+				value: -181, valueBOT: 541, valueSLD: 360,
+				netValue: -210, netValueBOT: 561, netValueSLD: 351,
+				marketPrice: 18, marketValue: 342,
+				commission: 25, exchangeFee: 4, cost: 29, costBasis: 318.36,
+				realProfitLoss: 108.36, unrealProfitLoss: 23.64, totalProfitLoss: 132,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		p := &Position{}
+		for i, fill := range tc.fills {
+			if i == 0 {
+				p.Create(fill)
+				continue
+			}
+			p.Update(fill)
+		}
+
+		// the csv holds values rounded to 4 decimal places
+		exp, actual := tc.expPos.values(), p.values()
+		for i := range exp {
+			if math.Abs(exp[i]-actual[i]) > 1e-4 {
+				t.Errorf("%v \nexpected %+v, \nactual   %+v", tc.msg, tc.expPos, *p)
+				break
+			}
+		}
+	}
+}
+
+// values returns the qty, prices, values, cost and profit and loss of the position.
+func (p Position) values() []float64 {
+	return []float64{
+		float64(p.qty), float64(p.qtyBOT), float64(p.qtySLD),
+		p.avgPrice, p.avgPriceNet, p.avgPriceBOT, p.avgPriceSLD,
+		p.value, p.valueBOT, p.valueSLD,
+		p.netValue, p.netValueBOT, p.netValueSLD,
+		p.marketPrice, p.marketValue,
+		p.commission, p.exchangeFee, p.cost, p.costBasis,
+		p.realProfitLoss, p.unrealProfitLoss, p.totalProfitLoss,
+	}
+}
+
+func TestPositionProfitLoss(t *testing.T) {
+	var exampleTime, _ = time.Parse("2006-01-02", "2017-06-01")
+
+	var testCases = []struct {
+		msg       string
+		fills     []*Fill
+		price     float64 // market price after the fills, 0 keeps the last fill price
+		expQty    int64
+		expReal   float64
+		expUnreal float64
+		expTotal  float64
+	}{
+		{"short position gains on falling price:",
+			[]*Fill{
+				{direction: SLD, qty: 10, price: 10},
+			},
+			8,
+			-10, 0, 20, 20,
+		},
+		{"short position covered at a loss:",
+			[]*Fill{
+				{direction: SLD, qty: 10, price: 10},
+				{direction: BOT, qty: 10, price: 12},
+			},
+			0,
+			0, -20, 0, -20,
+		},
+		{"long position flipped to short and covered:",
+			[]*Fill{
+				{direction: BOT, qty: 10, price: 10},
+				{direction: SLD, qty: 15, price: 12},
+				{direction: BOT, qty: 5, price: 11},
+			},
+			0,
+			0, 25, 0, 25,
+		},
+		{"short position flipped to long with cost:",
+			[]*Fill{
+				{direction: SLD, qty: 10, price: 10, commission: 2, cost: 2},
+				{direction: BOT, qty: 20, price: 9, commission: 4, cost: 4},
+			},
+			0,
+			10, 6, -2, 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		p := &Position{}
+		for i, fill := range tc.fills {
+			fill.Event = Event{timestamp: exampleTime, symbol: "TEST.DE"}
+			if i == 0 {
+				p.Create(fill)
+				continue
+			}
+			p.Update(fill)
+		}
+		if tc.price > 0 {
+			p.UpdateValue(&Bar{Event: Event{timestamp: exampleTime, symbol: "TEST.DE"}, Close: tc.price})
+		}
+
+		if (p.qty != tc.expQty) || (p.realProfitLoss != tc.expReal) || (p.unrealProfitLoss != tc.expUnreal) || (p.totalProfitLoss != tc.expTotal) {
+			t.Errorf("%v \nexpected %v %v %v %v, \nactual   %v %v %v %v", tc.msg,
+				tc.expQty, tc.expReal, tc.expUnreal, tc.expTotal,
+				p.qty, p.realProfitLoss, p.unrealProfitLoss, p.totalProfitLoss)
+		}
+	}
+}
+
 func TestUpdatePositionValue(t *testing.T) {
 	// set the example time string in format yyyy-mm-dd
 	var exampleTime, _ = time.Parse("2006-01-02", "2017-06-01")
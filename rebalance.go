@@ -0,0 +1,121 @@
+package gobacktest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RebalanceHandler is the basic interface for turning target signals into orders.
+type RebalanceHandler interface {
+	Rebalance([]TargetEvent, DataHandler, PortfolioHandler) ([]*Order, error)
+}
+
+// Rebalancer is a basic rebalance handler implementation.
+// It diffs the targets against the current holdings of the portfolio and creates the minimal set of orders.
+type Rebalancer struct {
+	LotSize       int64   // order qty is rounded down to a multiple of the lot size
+	MinTradeValue float64 // orders below this value are skipped, closing a position is always allowed
+}
+
+// Rebalance creates the orders to move the positions of the portfolio to the given targets.
+// Sell orders are returned before buy orders, buy orders are limited to the available cash
+// including the proceeds of the sell orders.
+func (r *Rebalancer) Rebalance(targets []TargetEvent, data DataHandler, pf PortfolioHandler) ([]*Order, error) {
+	var sells, buys []*Order
+	value := pf.Value()
+
+	for _, target := range targets {
+		latest := data.Latest(target.Symbol())
+		if latest == nil || latest.Price() == 0 {
+			return nil, fmt.Errorf("cannot rebalance %v: no price data", target.Symbol())
+		}
+		price := latest.Price()
+
+		// current qty of the position
+		var qty int64
+		if pos, ok := pf.IsInvested(target.Symbol()); ok {
+			qty = pos.qty
+		}
+
+		targetQty, ok := target.TargetQty()
+		if !ok {
+			weight, _ := target.TargetWeight()
+			targetQty = int64(weight * value / price)
+		}
+
+		diff := targetQty - qty
+		// closing a position is never rounded to lots
+		if targetQty != 0 {
+			diff = r.roundLot(diff)
+		}
+		if diff == 0 {
+			continue
+		}
+
+		// trade value too small, closing a position is always allowed
+		if (targetQty != 0) && (math.Abs(float64(diff))*price < r.MinTradeValue) {
+			continue
+		}
+
+		order := &Order{
+			Event:     Event{timestamp: target.Time(), symbol: target.Symbol()},
+			orderType: MarketOrder,
+		}
+
+		if diff > 0 {
+			order.SetDirection(BOT)
+			order.SetQty(diff)
+			buys = append(buys, order)
+		} else {
+			order.SetDirection(SLD)
+			order.SetQty(-diff)
+			sells = append(sells, order)
+		}
+	}
+
+	sortOrdersBySymbol(sells)
+	sortOrdersBySymbol(buys)
+
+	// available cash after all sells
+	cash := pf.Cash()
+	for _, order := range sells {
+		cash += float64(order.Qty()) * data.Latest(order.Symbol()).Price()
+	}
+
+	orders := sells
+	for _, order := range buys {
+		price := data.Latest(order.Symbol()).Price()
+
+		// limit qty to the available cash
+		if float64(order.Qty())*price > cash {
+			order.SetQty(r.roundLot(int64(math.Floor(cash / price))))
+		}
+		if order.Qty() <= 0 {
+			continue
+		}
+		if float64(order.Qty())*price < r.MinTradeValue {
+			continue
+		}
+
+		cash -= float64(order.Qty()) * price
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// roundLot rounds a qty towards zero to a multiple of the lot size.
+func (r *Rebalancer) roundLot(qty int64) int64 {
+	if r.LotSize <= 1 {
+		return qty
+	}
+	return qty / r.LotSize * r.LotSize
+}
+
+// sortOrdersBySymbol sorts a slice of orders ascending by symbol.
+func sortOrdersBySymbol(orders []*Order) {
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Symbol() < orders[j].Symbol()
+	})
+}
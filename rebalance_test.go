@@ -0,0 +1,115 @@
+package gobacktest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRebalance(t *testing.T) {
+	data := &Data{
+		latest: map[string]DataEvent{
+			"A": &Bar{Event: Event{symbol: "A"}, Close: 10},
+			"B": &Bar{Event: Event{symbol: "B"}, Close: 30},
+		},
+	}
+
+	var testCases = []struct {
+		msg        string
+		rebalancer *Rebalancer
+		portfolio  *Portfolio
+		targets    []TargetEvent
+		expOrders  []string
+		expErr     error
+	}{
+		{"test target weights on empty portfolio:",
+			&Rebalancer{},
+			&Portfolio{cash: 10000},
+			[]TargetEvent{
+				NewTargetWeight(&Event{symbol: "A"}, 0.5),
+				NewTargetWeight(&Event{symbol: "B"}, 0.5),
+			},
+			[]string{"BOT A 500", "BOT B 166"},
+			nil,
+		},
+		{"test lot size:",
+			&Rebalancer{LotSize: 100},
+			&Portfolio{cash: 10000},
+			[]TargetEvent{
+				NewTargetWeight(&Event{symbol: "A"}, 0.5),
+				NewTargetWeight(&Event{symbol: "B"}, 0.5),
+			},
+			[]string{"BOT A 500", "BOT B 100"},
+			nil,
+		},
+		{"test sell before buy:",
+			&Rebalancer{},
+			&Portfolio{holdings: map[string]Position{
+				"A": {qty: 1000, marketPrice: 10, marketValue: 10000},
+			}},
+			[]TargetEvent{
+				NewTargetQty(&Event{symbol: "B"}, 300),
+				NewTargetWeight(&Event{symbol: "A"}, 0),
+			},
+			[]string{"SLD A 1000", "BOT B 300"},
+			nil,
+		},
+		{"test buy limited by cash:",
+			&Rebalancer{},
+			&Portfolio{cash: 1000},
+			[]TargetEvent{
+				NewTargetQty(&Event{symbol: "B"}, 100),
+			},
+			[]string{"BOT B 33"},
+			nil,
+		},
+		{"test minimum trade value:",
+			&Rebalancer{MinTradeValue: 500},
+			&Portfolio{cash: 200, holdings: map[string]Position{
+				"A": {qty: 480, marketPrice: 10, marketValue: 4800},
+			}},
+			[]TargetEvent{
+				NewTargetWeight(&Event{symbol: "A"}, 1),
+			},
+			nil,
+			nil,
+		},
+		{"test short target:",
+			&Rebalancer{},
+			&Portfolio{cash: 10000},
+			[]TargetEvent{
+				NewTargetWeight(&Event{symbol: "A"}, -0.5),
+			},
+			[]string{"SLD A 500"},
+			nil,
+		},
+		{"test missing price data:",
+			&Rebalancer{},
+			&Portfolio{cash: 10000},
+			[]TargetEvent{
+				NewTargetQty(&Event{symbol: "C"}, 100),
+			},
+			nil,
+			errors.New("cannot rebalance C: no price data"),
+		},
+	}
+
+	for _, tc := range testCases {
+		orders, err := tc.rebalancer.Rebalance(tc.targets, data, tc.portfolio)
+
+		var result []string
+		for _, order := range orders {
+			dir := "BOT"
+			if order.Direction() == SLD {
+				dir = "SLD"
+			}
+			result = append(result, fmt.Sprintf("%v %v %v", dir, order.Symbol(), order.Qty()))
+		}
+
+		if !reflect.DeepEqual(result, tc.expOrders) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("%v Rebalance(): \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.expOrders, tc.expErr, result, err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package gobacktest
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+// PeriodReturn is the return of a calendar period.
+type PeriodReturn struct {
+	Start  time.Time // start of the calendar period
+	Return float64
+}
+
+// ReturnRow is a year of the monthly return table.
+type ReturnRow struct {
+	Year   int
+	Months map[time.Month]float64 // return of each month with equity points
+	Total  float64                // return of the year
+}
+
+// RollingPoint is a metric over the rolling window ending at the time.
+type RollingPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// DrawdownPeriod is a period from an equity high until the equity recovers to that high.
+type DrawdownPeriod struct {
+	Start    time.Time // time of the equity high
+	Trough   time.Time // time of the lowest equity
+	Recovery time.Time // time the equity reaches the high again, zero if not recovered
+	Depth    float64   // drawdown at the trough, negative value
+	Length   time.Duration
+}
+
+// Recovered returns true if the equity reached the high again.
+func (d DrawdownPeriod) Recovered() bool {
+	return !d.Recovery.IsZero()
+}
+
+// DailyReturns returns the return of each day with equity points.
+func (s Statistic) DailyReturns() []PeriodReturn {
+	return s.calendarReturns(func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	})
+}
+
+// MonthlyReturns returns the return of each month with equity points.
+func (s Statistic) MonthlyReturns() []PeriodReturn {
+	return s.calendarReturns(func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	})
+}
+
+// YearlyReturns returns the return of each year with equity points.
+func (s Statistic) YearlyReturns() []PeriodReturn {
+	return s.calendarReturns(func(t time.Time) time.Time {
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	})
+}
+
+// ReturnTable returns the monthly returns as month by year table, together with the return of each year.
+func (s Statistic) ReturnTable() []ReturnRow {
+	var rows []ReturnRow
+	for _, y := range s.YearlyReturns() {
+		rows = append(rows, ReturnRow{Year: y.Start.Year(), Months: make(map[time.Month]float64), Total: y.Return})
+	}
+
+	var i int
+	for _, m := range s.MonthlyReturns() {
+		for rows[i].Year != m.Start.Year() {
+			i++
+		}
+		rows[i].Months[m.Start.Month()] = m.Return
+	}
+
+	return rows
+}
+
+// RollingVolatility returns the annualised standard deviation of the returns over a rolling window of periods.
+func (s Statistic) RollingVolatility(window int, opts PerformanceOptions) []RollingPoint {
+	return s.rolling(window, opts, func(_ []EquityPoint, returns, _ []float64, periodsPerYear float64) float64 {
+		stdDev := stat.StdDev(returns, nil)
+		if !(stdDev > 0) {
+			return 0
+		}
+		return stdDev * math.Sqrt(periodsPerYear)
+	})
+}
+
+// RollingSharpe returns the annualised Sharpe ratio over a rolling window of periods.
+// A window without any variation of the returns has a ratio of 0.
+func (s Statistic) RollingSharpe(window int, opts PerformanceOptions) []RollingPoint {
+	return s.rolling(window, opts, func(_ []EquityPoint, _, excess []float64, periodsPerYear float64) float64 {
+		mean, stdDev := stat.MeanStdDev(excess, nil)
+		if !(stdDev > 0) {
+			return 0
+		}
+		return mean / stdDev * math.Sqrt(periodsPerYear)
+	})
+}
+
+// RollingDrawdown returns the maximum drawdown within a rolling window of periods.
+func (s Statistic) RollingDrawdown(window int) []RollingPoint {
+	return s.rolling(window, PerformanceOptions{PeriodsPerYear: 1}, func(points []EquityPoint, _, _ []float64, _ float64) float64 {
+		var max float64
+		high := points[0].equity
+		for _, e := range points {
+			high = math.Max(high, e.equity)
+			if drawdown := (e.equity - high) / high; (high > 0) && (drawdown < max) {
+				max = drawdown
+			}
+		}
+		return max
+	})
+}
+
+// DrawdownPeriods returns all periods the equity was below its high in the order they started.
+func (s Statistic) DrawdownPeriods() []DrawdownPeriod {
+	points := s.equity
+	if len(points) == 0 {
+		return nil
+	}
+
+	var periods []DrawdownPeriod
+	var current *DrawdownPeriod
+	high := points[0]
+	for _, e := range points {
+		if e.equity >= high.equity {
+			if current != nil {
+				current.Recovery = e.timestamp
+				current.Length = e.timestamp.Sub(current.Start)
+				periods = append(periods, *current)
+				current = nil
+			}
+			high = e
+			continue
+		}
+
+		if high.equity <= 0 {
+			continue
+		}
+		drawdown := math.Round((e.equity-high.equity)/high.equity*math.Pow10(DP)) / math.Pow10(DP)
+		if current == nil {
+			current = &DrawdownPeriod{Start: high.timestamp, Trough: e.timestamp, Depth: drawdown}
+		}
+		if drawdown < current.Depth {
+			current.Trough = e.timestamp
+			current.Depth = drawdown
+		}
+	}
+
+	if current != nil {
+		current.Length = points[len(points)-1].timestamp.Sub(current.Start)
+		periods = append(periods, *current)
+	}
+
+	return periods
+}
+
+// calendarReturns returns the return of each calendar period, the key returns the start of the period of a time.
+// The return of a period is measured from the last equity of the period before, the first period from the first equity.
+func (s Statistic) calendarReturns(key func(time.Time) time.Time) []PeriodReturn {
+	points := s.equity
+	if len(points) == 0 {
+		return nil
+	}
+
+	var returns []PeriodReturn
+	base := points[0].equity
+	for i, e := range points {
+		// last equity point of the period
+		if (i+1 < len(points)) && key(points[i+1].timestamp).Equal(key(e.timestamp)) {
+			continue
+		}
+
+		var r float64
+		if base != 0 {
+			r = math.Round((e.equity-base)/base*math.Pow10(DP)) / math.Pow10(DP)
+		}
+		returns = append(returns, PeriodReturn{Start: key(e.timestamp), Return: r})
+		base = e.equity
+	}
+
+	return returns
+}
+
+// rolling calculates a metric over each rolling window of returns.
+// The metric receives the equity points, the returns and the returns above the risk free rate of the window.
+func (s Statistic) rolling(window int, opts PerformanceOptions, metric func([]EquityPoint, []float64, []float64, float64) float64) []RollingPoint {
+	points := s.equity
+	if (window <= 0) || (len(points) <= window) {
+		return nil
+	}
+
+	periodsPerYear, err := periodsPerYear(opts, points)
+	if err != nil {
+		return nil
+	}
+
+	returns := make([]float64, len(points))
+	excess := make([]float64, len(points))
+	rate := periodRates(opts, periodsPerYear)
+	for i := 1; i < len(points); i++ {
+		if points[i-1].equity != 0 {
+			returns[i] = (points[i].equity - points[i-1].equity) / points[i-1].equity
+		}
+		excess[i] = returns[i] - rate(points[i].timestamp)
+	}
+
+	var rolling []RollingPoint
+	for i := window; i < len(points); i++ {
+		value := metric(points[i-window:i+1], returns[i-window+1:i+1], excess[i-window+1:i+1], periodsPerYear)
+		rolling = append(rolling, RollingPoint{
+			Time:  points[i].timestamp,
+			Value: math.Round(value*math.Pow10(DP)) / math.Pow10(DP),
+		})
+	}
+
+	return rolling
+}
@@ -0,0 +1,137 @@
+package gobacktest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testHelperReturnCurve returns a statistic with an equity curve over several months and years.
+func testHelperReturnCurve() Statistic {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	return Statistic{equity: []EquityPoint{
+		{timestamp: day(2017, 12, 30), equity: 100},
+		{timestamp: day(2018, 1, 2), equity: 110},
+		{timestamp: day(2018, 1, 31), equity: 99},
+		{timestamp: day(2018, 2, 1), equity: 99},
+		{timestamp: day(2018, 2, 28), equity: 108.9},
+		{timestamp: day(2019, 1, 2), equity: 120},
+		{timestamp: day(2019, 1, 3), equity: 108},
+	}}
+}
+
+func TestPeriodReturns(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	stat := testHelperReturnCurve()
+
+	var testCases = []struct {
+		msg        string
+		returns    []PeriodReturn
+		expReturns []PeriodReturn
+	}{
+		{"testing daily returns",
+			stat.DailyReturns(),
+			[]PeriodReturn{
+				{day(2017, 12, 30), 0}, {day(2018, 1, 2), 0.1}, {day(2018, 1, 31), -0.1}, {day(2018, 2, 1), 0},
+				{day(2018, 2, 28), 0.1}, {day(2019, 1, 2), 0.1019}, {day(2019, 1, 3), -0.1},
+			},
+		},
+		{"testing monthly returns",
+			stat.MonthlyReturns(),
+			[]PeriodReturn{{day(2017, 12, 1), 0}, {day(2018, 1, 1), -0.01}, {day(2018, 2, 1), 0.1}, {day(2019, 1, 1), -0.0083}},
+		},
+		{"testing yearly returns",
+			stat.YearlyReturns(),
+			[]PeriodReturn{{day(2017, 1, 1), 0}, {day(2018, 1, 1), 0.089}, {day(2019, 1, 1), -0.0083}},
+		},
+		{"testing returns without equity",
+			Statistic{}.MonthlyReturns(),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.returns, tc.expReturns) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expReturns, tc.returns)
+		}
+	}
+
+	expTable := []ReturnRow{
+		{Year: 2017, Months: map[time.Month]float64{time.December: 0}, Total: 0},
+		{Year: 2018, Months: map[time.Month]float64{time.January: -0.01, time.February: 0.1}, Total: 0.089},
+		{Year: 2019, Months: map[time.Month]float64{time.January: -0.0083}, Total: -0.0083},
+	}
+	if table := stat.ReturnTable(); !reflect.DeepEqual(table, expTable) {
+		t.Errorf("testing return table: \nexpected %v, \nactual   %v", expTable, table)
+	}
+}
+
+func TestRollingMetrics(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	stat := testHelperReturnCurve()
+	opts := PerformanceOptions{PeriodsPerYear: 1}
+
+	var testCases = []struct {
+		msg        string
+		rolling    []RollingPoint
+		expRolling []RollingPoint
+	}{
+		{"testing rolling volatility",
+			stat.RollingVolatility(2, opts),
+			[]RollingPoint{
+				{day(2018, 1, 31), 0.1414}, {day(2018, 2, 1), 0.0707}, {day(2018, 2, 28), 0.0707},
+				{day(2019, 1, 2), 0.0014}, {day(2019, 1, 3), 0.1428},
+			},
+		},
+		{"testing rolling sharpe",
+			stat.RollingSharpe(2, opts),
+			[]RollingPoint{
+				{day(2018, 1, 31), 0}, {day(2018, 2, 1), -0.7071}, {day(2018, 2, 28), 0.7071},
+				{day(2019, 1, 2), 74.0442}, {day(2019, 1, 3), 0.0068},
+			},
+		},
+		{"testing rolling drawdown",
+			stat.RollingDrawdown(2),
+			[]RollingPoint{
+				{day(2018, 1, 31), -0.1}, {day(2018, 2, 1), -0.1}, {day(2018, 2, 28), 0},
+				{day(2019, 1, 2), 0}, {day(2019, 1, 3), -0.1},
+			},
+		},
+		{"testing window larger than the equity curve",
+			stat.RollingDrawdown(10),
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.rolling, tc.expRolling) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expRolling, tc.rolling)
+		}
+	}
+}
+
+func TestDrawdownPeriods(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	expPeriods := []DrawdownPeriod{
+		{Start: day(2018, 1, 2), Trough: day(2018, 1, 31), Recovery: day(2019, 1, 2), Depth: -0.1, Length: 365 * 24 * time.Hour},
+		{Start: day(2019, 1, 2), Trough: day(2019, 1, 3), Depth: -0.1, Length: 24 * time.Hour},
+	}
+
+	periods := testHelperReturnCurve().DrawdownPeriods()
+	if !reflect.DeepEqual(periods, expPeriods) {
+		t.Errorf("testing drawdown periods: \nexpected %+v, \nactual   %+v", expPeriods, periods)
+	}
+	if !periods[0].Recovered() || periods[1].Recovered() {
+		t.Errorf("testing recovered drawdown: \nexpected %v %v, \nactual   %v %v", true, false, periods[0].Recovered(), periods[1].Recovered())
+	}
+}
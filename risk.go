@@ -9,6 +9,11 @@ type RiskHandler interface {
 	EvaluateOrder(OrderEvent, DataEvent, map[string]Position) (*Order, error)
 }
 
+// PortfolioSetter is implemented by a risk handler, which evaluates orders against the value of the portfolio.
+type PortfolioSetter interface {
+	SetPortfolio(PortfolioHandler) error
+}
+
 // Risk is a basic risk handler implementation
 type Risk struct {
 }
@@ -0,0 +1,315 @@
+package gobacktest
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// RiskLimit is a single pre-trade risk control. It returns the qty of the order it allows,
+// which is the qty of the order, a reduced qty to scale it down or 0 to reject it,
+// together with the reason for any reduction.
+type RiskLimit interface {
+	Check(order OrderEvent, price float64, account RiskAccount) (int64, string)
+}
+
+// RiskAccount is the state of the portfolio an order is evaluated against.
+type RiskAccount struct {
+	Time      time.Time
+	Equity    float64             // current value of the portfolio
+	DayStart  float64             // value of the portfolio at the start of the trading day
+	Positions map[string]Position // holdings of the portfolio
+}
+
+// RiskDecision records an order, which was scaled down or rejected by a risk limit.
+type RiskDecision struct {
+	Time      time.Time
+	Symbol    string
+	Direction Direction
+	Qty       int64 // requested qty
+	Approved  int64 // allowed qty, 0 if the order was rejected
+	Reason    string
+}
+
+// RiskRecorder returns the recorded decisions of a risk handler.
+type RiskRecorder interface {
+	Decisions() []RiskDecision
+}
+
+// RiskLimits is a risk handler, which evaluates each order against a set of pre-trade limits.
+// The limits are checked in order, each limit sees the qty allowed by the limits before.
+// Orders which reduce a position are never limited.
+type RiskLimits struct {
+	Limits    []RiskLimit
+	portfolio PortfolioHandler
+	day       time.Time
+	dayStart  float64
+	decisions []RiskDecision
+}
+
+// NewRiskLimits creates a risk handler with the given limits.
+func NewRiskLimits(limits ...RiskLimit) *RiskLimits {
+	return &RiskLimits{Limits: limits}
+}
+
+// SetPortfolio implements PortfolioSetter, the value of the portfolio is used by the limits.
+func (r *RiskLimits) SetPortfolio(portfolio PortfolioHandler) error {
+	r.portfolio = portfolio
+	return nil
+}
+
+// Update implements Updater and keeps the value of the portfolio at the start of each trading day.
+func (r *RiskLimits) Update(d DataEvent) {
+	y, m, day := d.Time().Date()
+	if start := time.Date(y, m, day, 0, 0, 0, 0, d.Time().Location()); !start.Equal(r.day) {
+		r.day = start
+		r.dayStart = r.equity()
+	}
+}
+
+// Decisions implements RiskRecorder and returns all scaled down or rejected orders.
+func (r *RiskLimits) Decisions() []RiskDecision {
+	return r.decisions
+}
+
+// Reset implements Reseter to remove the recorded decisions.
+func (r *RiskLimits) Reset() error {
+	r.day = time.Time{}
+	r.dayStart = 0
+	r.decisions = nil
+	return nil
+}
+
+// EvaluateOrder checks the order against all limits and scales it down if needed.
+// A rejected order is returned unchanged with an error.
+func (r *RiskLimits) EvaluateOrder(order OrderEvent, data DataEvent, positions map[string]Position) (*Order, error) {
+	o := order.(*Order)
+
+	account := RiskAccount{
+		Time:      data.Time(),
+		Equity:    r.equity(),
+		DayStart:  r.dayStart,
+		Positions: positions,
+	}
+
+	for _, limit := range r.Limits {
+		qty, reason := limit.Check(o, data.Price(), account)
+		if qty >= o.Qty() {
+			continue
+		}
+		if qty < 0 {
+			qty = 0
+		}
+
+		r.decisions = append(r.decisions, RiskDecision{
+			Time:      data.Time(),
+			Symbol:    o.Symbol(),
+			Direction: o.Direction(),
+			Qty:       o.Qty(),
+			Approved:  qty,
+			Reason:    reason,
+		})
+
+		if qty == 0 {
+			return o, fmt.Errorf("order rejected: %v", reason)
+		}
+		o.SetQty(qty)
+	}
+
+	return o, nil
+}
+
+// equity returns the value of the portfolio, 0 if no portfolio is set.
+func (r *RiskLimits) equity() float64 {
+	if r.portfolio == nil {
+		return 0
+	}
+	return r.portfolio.Value()
+}
+
+// MaxPositionQty limits the absolute qty of a position.
+type MaxPositionQty struct {
+	Qty int64
+}
+
+// Check implements RiskLimit.
+func (l *MaxPositionQty) Check(order OrderEvent, _ float64, account RiskAccount) (int64, string) {
+	current := account.Positions[order.Symbol()].qty
+	return capQty(current, order, l.Qty), fmt.Sprintf("max position qty %d", l.Qty)
+}
+
+// MaxPositionValue limits the absolute market value of a position.
+type MaxPositionValue struct {
+	Value float64
+}
+
+// Check implements RiskLimit.
+func (l *MaxPositionValue) Check(order OrderEvent, price float64, account RiskAccount) (int64, string) {
+	if price <= 0 {
+		return order.Qty(), ""
+	}
+	current := account.Positions[order.Symbol()].qty
+	return capQty(current, order, int64(math.Floor(l.Value/price))), fmt.Sprintf("max position value %.2f", l.Value)
+}
+
+// MaxPositionPercent limits the absolute market value of a position to a percentage of the equity, e.g. 0.1 for 10%.
+type MaxPositionPercent struct {
+	Percent float64
+}
+
+// Check implements RiskLimit.
+func (l *MaxPositionPercent) Check(order OrderEvent, price float64, account RiskAccount) (int64, string) {
+	if price <= 0 {
+		return order.Qty(), ""
+	}
+	current := account.Positions[order.Symbol()].qty
+	max := int64(math.Floor(l.Percent * account.Equity / price))
+	return capQty(current, order, max), fmt.Sprintf("max position %.2f%% of equity", l.Percent*100)
+}
+
+// MaxGrossExposure limits the sum of the absolute market values of all positions
+// to a percentage of the equity, e.g. 1.5 for 150%.
+type MaxGrossExposure struct {
+	Percent float64
+}
+
+// Check implements RiskLimit.
+func (l *MaxGrossExposure) Check(order OrderEvent, price float64, account RiskAccount) (int64, string) {
+	if price <= 0 {
+		return order.Qty(), ""
+	}
+
+	var others float64
+	for symbol, pos := range account.Positions {
+		if symbol != order.Symbol() {
+			others += math.Abs(exposure(pos))
+		}
+	}
+
+	current := account.Positions[order.Symbol()].qty
+	max := int64(math.Floor((l.Percent*account.Equity - others) / price))
+	return capQty(current, order, max), fmt.Sprintf("max gross exposure %.2f%% of equity", l.Percent*100)
+}
+
+// MaxNetExposure limits the absolute sum of the long minus the short market values of all positions
+// to a percentage of the equity, e.g. 0.5 for 50%.
+type MaxNetExposure struct {
+	Percent float64
+}
+
+// Check implements RiskLimit.
+func (l *MaxNetExposure) Check(order OrderEvent, price float64, account RiskAccount) (int64, string) {
+	if price <= 0 {
+		return order.Qty(), ""
+	}
+
+	var others float64
+	for symbol, pos := range account.Positions {
+		if symbol != order.Symbol() {
+			others += exposure(pos)
+		}
+	}
+
+	// the net exposure of the other positions counts against an order in the same direction
+	current := account.Positions[order.Symbol()].qty
+	max := int64(math.Floor((l.Percent*account.Equity - others*float64(sign(order))) / price))
+	return capQty(current, order, max), fmt.Sprintf("max net exposure %.2f%% of equity", l.Percent*100)
+}
+
+// MaxOpenPositions limits the number of open positions.
+type MaxOpenPositions struct {
+	Positions int
+}
+
+// Check implements RiskLimit.
+func (l *MaxOpenPositions) Check(order OrderEvent, _ float64, account RiskAccount) (int64, string) {
+	if account.Positions[order.Symbol()].qty != 0 {
+		return order.Qty(), ""
+	}
+
+	var open int
+	for _, pos := range account.Positions {
+		if pos.qty != 0 {
+			open++
+		}
+	}
+
+	if open < l.Positions {
+		return order.Qty(), ""
+	}
+	return 0, fmt.Sprintf("max %d open positions", l.Positions)
+}
+
+// MaxGroupExposure limits the sum of the absolute market values of all positions of a group,
+// e.g. a sector, to a percentage of the equity. Symbols without a group are not limited.
+type MaxGroupExposure struct {
+	Groups  map[string]string // group of each symbol
+	Percent float64
+}
+
+// Check implements RiskLimit.
+func (l *MaxGroupExposure) Check(order OrderEvent, price float64, account RiskAccount) (int64, string) {
+	group, ok := l.Groups[order.Symbol()]
+	if !ok || (price <= 0) {
+		return order.Qty(), ""
+	}
+
+	var others float64
+	for symbol, pos := range account.Positions {
+		if (symbol != order.Symbol()) && (l.Groups[symbol] == group) {
+			others += math.Abs(exposure(pos))
+		}
+	}
+
+	current := account.Positions[order.Symbol()].qty
+	max := int64(math.Floor((l.Percent*account.Equity - others) / price))
+	return capQty(current, order, max), fmt.Sprintf("max group %v exposure %.2f%% of equity", group, l.Percent*100)
+}
+
+// DailyLossLimit blocks new entries if the equity lost more than a percentage
+// since the start of the trading day, e.g. 0.02 for 2%.
+type DailyLossLimit struct {
+	Percent float64
+}
+
+// Check implements RiskLimit.
+func (l *DailyLossLimit) Check(order OrderEvent, _ float64, account RiskAccount) (int64, string) {
+	if (account.DayStart <= 0) || ((account.Equity-account.DayStart)/account.DayStart > -l.Percent) {
+		return order.Qty(), ""
+	}
+	current := account.Positions[order.Symbol()].qty
+	return capQty(current, order, 0), fmt.Sprintf("daily loss limit %.2f%%", l.Percent*100)
+}
+
+// capQty returns the qty of an order, which keeps the resulting position in the direction
+// of the order at or below max. The part of the order closing an opposite position is always allowed.
+func capQty(current int64, order OrderEvent, max int64) int64 {
+	held := current * sign(order)
+
+	var closing int64
+	if held < 0 {
+		closing = -held
+	}
+
+	qty := max - held
+	if qty < closing {
+		qty = closing
+	}
+	if qty > order.Qty() {
+		qty = order.Qty()
+	}
+	return qty
+}
+
+// sign returns 1 for a buy order and -1 for a sell order.
+func sign(order OrderEvent) int64 {
+	if order.Direction() == SLD {
+		return -1
+	}
+	return 1
+}
+
+// exposure returns the signed market value of a position, negative for a short position.
+func exposure(pos Position) float64 {
+	return float64(pos.qty) * pos.marketPrice
+}
@@ -0,0 +1,204 @@
+package gobacktest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRiskLimits(t *testing.T) {
+	var testCases = []struct {
+		msg          string
+		limits       []RiskLimit
+		cash         float64
+		holdings     map[string]Position
+		order        *Order
+		price        float64
+		expQty       int64
+		expErr       bool
+		expDecisions int
+	}{
+		{"testing without limits",
+			nil,
+			100000,
+			map[string]Position{},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 100},
+			50,
+			100, false, 0,
+		},
+		{"testing max position qty scales down",
+			[]RiskLimit{&MaxPositionQty{Qty: 150}},
+			90000,
+			map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}},
+			&Order{Event: Event{symbol: "A"}, direction: BOT, qty: 100},
+			100,
+			50, false, 1,
+		},
+		{"testing max position qty allows reducing order",
+			[]RiskLimit{&MaxPositionQty{Qty: 50}},
+			90000,
+			map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}},
+			&Order{Event: Event{symbol: "A"}, direction: SLD, qty: 100},
+			100,
+			100, false, 0,
+		},
+		{"testing max position qty on flip",
+			[]RiskLimit{&MaxPositionQty{Qty: 50}},
+			90000,
+			map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}},
+			&Order{Event: Event{symbol: "A"}, direction: SLD, qty: 200},
+			100,
+			150, false, 1,
+		},
+		{"testing max position value",
+			[]RiskLimit{&MaxPositionValue{Value: 5000}},
+			100000,
+			map[string]Position{},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 100},
+			100,
+			50, false, 1,
+		},
+		{"testing max position percent of equity",
+			[]RiskLimit{&MaxPositionPercent{Percent: 0.1}},
+			100000,
+			map[string]Position{},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 200},
+			100,
+			100, false, 1,
+		},
+		{"testing max gross exposure",
+			[]RiskLimit{&MaxGrossExposure{Percent: 1}},
+			20000,
+			map[string]Position{"A": {qty: 800, marketPrice: 100, marketValue: 80000}},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 500},
+			100,
+			200, false, 1,
+		},
+		{"testing max net exposure rejects order in direction of exposure",
+			[]RiskLimit{&MaxNetExposure{Percent: 0.5}},
+			40000,
+			map[string]Position{"A": {qty: 600, marketPrice: 100, marketValue: 60000}},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 500},
+			100,
+			500, true, 1,
+		},
+		{"testing max net exposure allows hedging order",
+			[]RiskLimit{&MaxNetExposure{Percent: 0.5}},
+			40000,
+			map[string]Position{"A": {qty: 600, marketPrice: 100, marketValue: 60000}},
+			&Order{Event: Event{symbol: "C"}, direction: SLD, qty: 500},
+			100,
+			500, false, 0,
+		},
+		{"testing max open positions rejects new position",
+			[]RiskLimit{&MaxOpenPositions{Positions: 1}},
+			90000,
+			map[string]Position{
+				"A": {qty: 100, marketPrice: 100, marketValue: 10000},
+				"B": {qty: 0},
+			},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 100},
+			100,
+			100, true, 1,
+		},
+		{"testing max open positions allows existing position",
+			[]RiskLimit{&MaxOpenPositions{Positions: 1}},
+			90000,
+			map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}},
+			&Order{Event: Event{symbol: "A"}, direction: BOT, qty: 100},
+			100,
+			100, false, 0,
+		},
+		{"testing max group exposure",
+			[]RiskLimit{&MaxGroupExposure{Groups: map[string]string{"A": "tech", "C": "tech"}, Percent: 0.2}},
+			90000,
+			map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 200},
+			100,
+			100, false, 1,
+		},
+		{"testing max group exposure ignores other groups",
+			[]RiskLimit{&MaxGroupExposure{Groups: map[string]string{"A": "tech", "C": "chem"}, Percent: 0.2}},
+			90000,
+			map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 200},
+			100,
+			200, false, 0,
+		},
+		{"testing chained limits",
+			[]RiskLimit{&MaxPositionQty{Qty: 150}, &MaxPositionValue{Value: 10000}},
+			100000,
+			map[string]Position{},
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 200},
+			100,
+			100, false, 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		r := NewRiskLimits(tc.limits...)
+		p := &Portfolio{cash: tc.cash, holdings: tc.holdings}
+		p.SetRiskManager(r)
+
+		data := &Bar{Event: Event{symbol: tc.order.Symbol()}, Close: tc.price}
+		order, err := p.RiskManager().EvaluateOrder(tc.order, data, p.holdings)
+		if (order.Qty() != tc.expQty) || ((err != nil) != tc.expErr) || (len(r.Decisions()) != tc.expDecisions) {
+			t.Errorf("%v: \nexpected %v %v %v, \nactual   %v %v %v",
+				tc.msg, tc.expQty, tc.expErr, tc.expDecisions, order.Qty(), err, len(r.Decisions()))
+		}
+	}
+}
+
+func TestRiskLimitsDailyLoss(t *testing.T) {
+	r := NewRiskLimits(&DailyLossLimit{Percent: 0.02})
+	p := &Portfolio{cash: 90000, holdings: map[string]Position{"A": {qty: 100, marketPrice: 100, marketValue: 10000}}}
+	p.SetRiskManager(r)
+
+	day, _ := time.Parse("2006-01-02", "2018-07-02")
+	data := &Bar{Event: Event{timestamp: day, symbol: "A"}, Close: 100}
+	p.Update(data)
+
+	var testCases = []struct {
+		msg    string
+		cash   float64
+		next   bool // evaluate on the next trading day
+		order  *Order
+		expErr bool
+	}{
+		{"testing entry within loss limit",
+			89000, false,
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 100},
+			false,
+		},
+		{"testing entry after loss limit",
+			87000, false,
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 100},
+			true,
+		},
+		{"testing exit after loss limit",
+			87000, false,
+			&Order{Event: Event{symbol: "A"}, direction: SLD, qty: 100},
+			false,
+		},
+		{"testing entry on the next day",
+			87000, true,
+			&Order{Event: Event{symbol: "C"}, direction: BOT, qty: 100},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		p.SetCash(tc.cash)
+		if tc.next {
+			p.Update(&Bar{Event: Event{timestamp: day.AddDate(0, 0, 1), symbol: "A"}, Close: 100})
+		}
+
+		_, err := r.EvaluateOrder(tc.order, data, p.holdings)
+		if (err != nil) != tc.expErr {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expErr, err)
+		}
+	}
+
+	if err := r.Reset(); (err != nil) || (len(r.Decisions()) != 0) {
+		t.Errorf("testing reset: \nexpected %v, \nactual   %v %v", 0, len(r.Decisions()), err)
+	}
+}
@@ -18,7 +18,16 @@ const (
 // Signal declares a basic signal event
 type Signal struct {
 	Event
-	direction Direction // long, short, exit or hold
+	direction  Direction // long, short, exit or hold
+	qty        int64     // optional qty, if not set the size manager decides on the qty
+	stopLoss   float64   // optional stop loss price of the bracket order
+	takeProfit float64   // optional take profit price of the bracket order
+}
+
+// Bracketer defines a signal, which requests a stop loss and take profit exit for its order
+type Bracketer interface {
+	StopLoss() float64
+	TakeProfit() float64
 }
 
 // Direction returns the Direction of a Signal
@@ -30,3 +39,33 @@ func (s Signal) Direction() Direction {
 func (s *Signal) SetDirection(dir Direction) {
 	s.direction = dir
 }
+
+// Qty returns the Qty field of a Signal
+func (s Signal) Qty() int64 {
+	return s.qty
+}
+
+// SetQty sets the Qty field of a Signal
+func (s *Signal) SetQty(i int64) {
+	s.qty = i
+}
+
+// StopLoss returns the stop loss price of a Signal
+func (s Signal) StopLoss() float64 {
+	return s.stopLoss
+}
+
+// SetStopLoss sets the stop loss price of a Signal
+func (s *Signal) SetStopLoss(price float64) {
+	s.stopLoss = price
+}
+
+// TakeProfit returns the take profit price of a Signal
+func (s Signal) TakeProfit() float64 {
+	return s.takeProfit
+}
+
+// SetTakeProfit sets the take profit price of a Signal
+func (s *Signal) SetTakeProfit(price float64) {
+	s.takeProfit = price
+}
@@ -48,3 +48,30 @@ func TestSignalGetDirection(t *testing.T) {
 		}
 	}
 }
+
+func TestSignalBracket(t *testing.T) {
+	portfolio := NewPortfolio()
+	data := &Data{
+		latest: map[string]DataEvent{
+			"TEST.DE": &Bar{Close: 10},
+		},
+	}
+
+	signal := &Signal{Event: Event{symbol: "TEST.DE"}, direction: BOT}
+	signal.SetStopLoss(9)
+	signal.SetTakeProfit(12)
+
+	order, err := portfolio.OnSignal(signal, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legs := order.Legs()
+	if (len(legs) != 2) || (legs[0].Stop() != 9) || (legs[1].Limit() != 12) {
+		t.Fatalf("OnSignal() with bracket: \nexpected stop %v and limit %v, \nactual   %+v", 9, 12, legs)
+	}
+	if (legs[0].Direction() != SLD) || (legs[0].group == nil) || (legs[0].group != legs[1].group) {
+		t.Errorf("OnSignal() bracket legs: \nexpected %v in one group, \nactual   %v %v %v",
+			SLD, legs[0].Direction(), legs[0].group, legs[1].group)
+	}
+}
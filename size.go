@@ -10,10 +10,24 @@ type SizeHandler interface {
 	SizeOrder(OrderEvent, DataEvent, PortfolioHandler) (*Order, error)
 }
 
-// Size is a basic size handler implementation
+// DataSetter is implemented by a size handler, which needs the data history to size an order.
+type DataSetter interface {
+	SetData(DataHandler) error
+}
+
+// FillTracker is implemented by a size handler, which tracks the fills of the portfolio, e.g. to size by the closed trades.
+type FillTracker interface {
+	TrackFill(FillEvent)
+}
+
+// Size is a basic size handler implementation.
+// A buy or sell order trades the default size, also against an open position, an exit order closes the position.
+// With Reverse a buy or sell order against an open position closes it and opens a default sized position
+// in the opposite direction.
 type Size struct {
 	DefaultSize  int64
 	DefaultValue float64
+	Reverse      bool
 }
 
 // SizeOrder adjusts the size of an order
@@ -27,31 +41,61 @@ func (s *Size) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler)
 
 	// decide on order direction
 	switch o.Direction() {
-	case BOT:
-		o.SetDirection(BOT)
-		o.SetQty(s.setDefaultSize(data.Price()))
-	case SLD:
-		o.SetDirection(SLD)
-		o.SetQty(s.setDefaultSize(data.Price()))
-	case EXT: // all shares should be sold or bought, depending on position
-		// poll postions
-		if _, ok := pf.IsInvested(o.Symbol()); !ok {
-
-			return o, errors.New("cannot exit order: no position to symbol in portfolio,")
-		}
-		if pos, ok := pf.IsLong(o.Symbol()); ok {
-			o.SetDirection(SLD)
-			o.SetQty(pos.qty)
+	case BOT, SLD:
+		if o.Qty() <= 0 {
+			qty, _ := directionalQty(o, pf, s.Reverse, func() (int64, error) {
+				return s.setDefaultSize(data.Price()), nil
+			})
+			o.SetQty(qty)
 		}
-		if pos, ok := pf.IsShort(o.Symbol()); ok {
-			o.SetDirection(BOT)
-			o.SetQty(pos.qty * -1)
+	case EXT: // all shares should be sold or bought, depending on position
+		if err := exitOrder(o, pf); err != nil {
+			return o, err
 		}
 	}
 
 	return o, nil
 }
 
+// directionalQty returns the qty of a buy or sell order, which is the qty of the size function.
+// With reverse an order against an open position also closes the position, so the position is flipped
+// into a position with the qty of the size function in the opposite direction.
+func directionalQty(o *Order, pf PortfolioHandler, reverse bool, size func() (int64, error)) (int64, error) {
+	qty, err := size()
+	if err != nil {
+		return 0, err
+	}
+	if !reverse {
+		return qty, nil
+	}
+
+	var opposite int64
+	if pos, ok := pf.IsLong(o.Symbol()); ok && (o.Direction() == SLD) {
+		opposite = pos.qty
+	}
+	if pos, ok := pf.IsShort(o.Symbol()); ok && (o.Direction() == BOT) {
+		opposite = -pos.qty
+	}
+	return opposite + qty, nil
+}
+
+// exitOrder sets the direction and qty of an exit order to close the position of its symbol.
+func exitOrder(o *Order, pf PortfolioHandler) error {
+	// poll postions
+	if _, ok := pf.IsInvested(o.Symbol()); !ok {
+		return errors.New("cannot exit order: no position to symbol in portfolio,")
+	}
+	if pos, ok := pf.IsLong(o.Symbol()); ok {
+		o.SetDirection(SLD)
+		o.SetQty(pos.qty)
+	}
+	if pos, ok := pf.IsShort(o.Symbol()); ok {
+		o.SetDirection(BOT)
+		o.SetQty(pos.qty * -1)
+	}
+	return nil
+}
+
 func (s *Size) setDefaultSize(price float64) int64 {
 	if (float64(s.DefaultSize) * price) > s.DefaultValue {
 		correctedQty := int64(math.Floor(s.DefaultValue / price))
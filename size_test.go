@@ -41,6 +41,46 @@ func TestSizeOrder(t *testing.T) {
 			&Order{qty: 100, direction: SLD},
 			nil,
 		},
+		{"sell order against long position sells the default size:",
+			&Size{DefaultSize: 100, DefaultValue: 1000},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: SLD},
+			&Bar{},
+			&Portfolio{holdings: map[string]Position{"TEST.DE": {qty: 15}}},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: SLD, qty: 100},
+			nil,
+		},
+		{"buy order against short position buys the default size:",
+			&Size{DefaultSize: 100, DefaultValue: 1000},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT},
+			&Bar{},
+			&Portfolio{holdings: map[string]Position{"TEST.DE": {qty: -12}}},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT, qty: 100},
+			nil,
+		},
+		{"sell order against long position with reverse flips the position:",
+			&Size{DefaultSize: 100, DefaultValue: 1000, Reverse: true},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: SLD},
+			&Bar{},
+			&Portfolio{holdings: map[string]Position{"TEST.DE": {qty: 15}}},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: SLD, qty: 115},
+			nil,
+		},
+		{"buy order against short position with reverse flips the position:",
+			&Size{DefaultSize: 100, DefaultValue: 1000, Reverse: true},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT},
+			&Bar{},
+			&Portfolio{holdings: map[string]Position{"TEST.DE": {qty: -12}}},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT, qty: 112},
+			nil,
+		},
+		{"buy order with long position increases the position:",
+			&Size{DefaultSize: 100, DefaultValue: 1000, Reverse: true},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT},
+			&Bar{},
+			&Portfolio{holdings: map[string]Position{"TEST.DE": {qty: 15}}},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT, qty: 100},
+			nil,
+		},
 		{"exit order but no position in portfolio:",
 			&Size{DefaultSize: 100, DefaultValue: 1000},
 			&Order{direction: EXT},
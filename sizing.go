@@ -0,0 +1,289 @@
+package gobacktest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dirkolbrich/gobacktest/ta"
+)
+
+// PercentOfEquity sizes an order to a percentage of the portfolio value, e.g. 0.1 for 10%.
+type PercentOfEquity struct {
+	Percent float64
+	Reverse bool // see Size
+}
+
+// SizeOrder implements SizeHandler.
+func (s *PercentOfEquity) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler) (*Order, error) {
+	return sizeOrder(order, data, pf, s.Reverse, func(price float64) (int64, error) {
+		return int64(math.Floor(s.Percent * pf.Value() / price)), nil
+	})
+}
+
+// FixedFractional sizes an order, so that a percentage of the portfolio value is lost, if the price moves
+// by the stop distance against the position, e.g. a Risk of 0.01 for 1%. The stop distance is either
+// a fixed Distance or a Percent of the price.
+type FixedFractional struct {
+	Risk     float64
+	Distance float64
+	Percent  float64
+	Reverse  bool // see Size
+}
+
+// SizeOrder implements SizeHandler.
+func (s *FixedFractional) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler) (*Order, error) {
+	return sizeOrder(order, data, pf, s.Reverse, func(price float64) (int64, error) {
+		distance := s.Distance
+		if distance <= 0 {
+			distance = s.Percent * price
+		}
+		if distance <= 0 {
+			return 0, errors.New("cannot size order: no stop distance set")
+		}
+		return int64(math.Floor(s.Risk * pf.Value() / distance)), nil
+	})
+}
+
+// ATRSize sizes an order, so that a move of one average true range over the period
+// changes the portfolio value by the Risk percentage, e.g. 0.01 for 1%.
+type ATRSize struct {
+	Period  int
+	Risk    float64
+	Reverse bool // see Size
+	data    DataHandler
+}
+
+// SetData implements DataSetter.
+func (s *ATRSize) SetData(data DataHandler) error {
+	s.data = data
+	return nil
+}
+
+// SizeOrder implements SizeHandler.
+func (s *ATRSize) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler) (*Order, error) {
+	return sizeOrder(order, data, pf, s.Reverse, func(price float64) (int64, error) {
+		if s.data == nil {
+			return 0, errors.New("cannot size order: no data set")
+		}
+
+		atr, err := ta.ATR(taBars(s.data.List(order.Symbol())), s.Period)
+		if err != nil {
+			return 0, err
+		}
+		last := atr[len(atr)-1]
+		if last <= 0 {
+			return 0, fmt.Errorf("cannot size order: invalid atr of %v", order.Symbol())
+		}
+
+		return int64(math.Floor(s.Risk * pf.Value() / last)), nil
+	})
+}
+
+// VolatilityTarget sizes an order, so that the volatility of the position is the Target percentage
+// of the portfolio value, e.g. 0.01 for 1% per bar. The volatility is the standard deviation
+// of the returns over the period.
+type VolatilityTarget struct {
+	Period  int
+	Target  float64
+	Reverse bool // see Size
+	data    DataHandler
+}
+
+// SetData implements DataSetter.
+func (s *VolatilityTarget) SetData(data DataHandler) error {
+	s.data = data
+	return nil
+}
+
+// SizeOrder implements SizeHandler.
+func (s *VolatilityTarget) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler) (*Order, error) {
+	return sizeOrder(order, data, pf, s.Reverse, func(price float64) (int64, error) {
+		if s.data == nil {
+			return 0, errors.New("cannot size order: no data set")
+		}
+
+		list := s.data.List(order.Symbol())
+		if len(list) > s.Period+1 {
+			list = list[len(list)-s.Period-1:]
+		}
+		returns := make([]float64, 0, len(list))
+		for i := 1; i < len(list); i++ {
+			if last := list[i-1].Price(); last != 0 {
+				returns = append(returns, (list[i].Price()-last)/last)
+			}
+		}
+
+		vol, err := ta.StdDev(returns, s.Period)
+		if err != nil {
+			return 0, err
+		}
+		last := vol[len(vol)-1]
+		if last <= 0 {
+			return 0, fmt.Errorf("cannot size order: invalid volatility of %v", order.Symbol())
+		}
+
+		return int64(math.Floor(s.Target * pf.Value() / (last * price))), nil
+	})
+}
+
+// KellySize sizes an order with a fraction of the Kelly criterion, e.g. 0.5 for half-Kelly.
+// The win rate and the win/loss ratio are taken from the profit and loss of the last closed
+// round trip trades of the portfolio, which the size handler pairs from the fills it tracks.
+// Until MinTrades and Lookback trades are closed, or as long as none of them is a loss,
+// the order is sized with the Default percentage of the portfolio value.
+type KellySize struct {
+	Lookback  int
+	MinTrades int
+	Fraction  float64
+	Default   float64
+	Reverse   bool // see Size
+	ledger    TradeLedger
+}
+
+// Reset implements Reseter to remove the tracked trades.
+func (s *KellySize) Reset() error {
+	return s.ledger.Reset()
+}
+
+// TrackFill implements FillTracker to pair the fills of the portfolio into round trip trades.
+func (s *KellySize) TrackFill(fill FillEvent) {
+	s.ledger.OnFill(fill)
+}
+
+// SizeOrder implements SizeHandler.
+func (s *KellySize) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler) (*Order, error) {
+	return sizeOrder(order, data, pf, s.Reverse, func(price float64) (int64, error) {
+		defaultQty := int64(math.Floor(s.Default * pf.Value() / price))
+
+		trades := s.ledger.Trades()
+		if (len(trades) == 0) || (len(trades) < s.Lookback) || (len(trades) < s.MinTrades) {
+			return defaultQty, nil
+		}
+		if s.Lookback > 0 {
+			trades = trades[len(trades)-s.Lookback:]
+		}
+
+		outcomes := make([]float64, len(trades))
+		for i, t := range trades {
+			outcomes[i] = t.ProfitLoss
+		}
+		fraction, ok := kelly(outcomes)
+		if !ok {
+			return defaultQty, nil
+		}
+		weight := s.Fraction * fraction
+		if weight <= 0 {
+			return 0, errors.New("cannot size order: no positive kelly fraction")
+		}
+		return int64(math.Floor(math.Min(weight, 1) * pf.Value() / price)), nil
+	})
+}
+
+// kelly returns the Kelly criterion W - (1 - W) / R of the trade outcomes,
+// with the win rate W and the ratio R of the average win to the average loss.
+// Without a loss the ratio is unknown, kelly returns false.
+func kelly(outcomes []float64) (float64, bool) {
+	var wins, losses int
+	var won, lost float64
+	for _, o := range outcomes {
+		switch {
+		case o > 0:
+			wins++
+			won += o
+		case o < 0:
+			losses++
+			lost -= o
+		}
+	}
+
+	if losses == 0 {
+		return 0, false
+	}
+	if wins == 0 {
+		return 0, true
+	}
+
+	rate := float64(wins) / float64(wins+losses)
+	ratio := (won / float64(wins)) / (lost / float64(losses))
+	return rate - (1-rate)/ratio, true
+}
+
+// CashLimit limits the qty of buy orders sized by another size handler to the available cash of the portfolio.
+type CashLimit struct {
+	Size SizeHandler
+}
+
+// SetData implements DataSetter and passes the data to the wrapped size handler.
+func (s *CashLimit) SetData(data DataHandler) error {
+	if d, ok := s.Size.(DataSetter); ok {
+		return d.SetData(data)
+	}
+	return nil
+}
+
+// Reset implements Reseter and resets the wrapped size handler.
+func (s *CashLimit) Reset() error {
+	if r, ok := s.Size.(Reseter); ok {
+		return r.Reset()
+	}
+	return nil
+}
+
+// TrackFill implements FillTracker and passes the fill to the wrapped size handler.
+func (s *CashLimit) TrackFill(fill FillEvent) {
+	if t, ok := s.Size.(FillTracker); ok {
+		t.TrackFill(fill)
+	}
+}
+
+// SizeOrder implements SizeHandler.
+func (s *CashLimit) SizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler) (*Order, error) {
+	o, err := s.Size.SizeOrder(order, data, pf)
+	if (err != nil) || (o.Direction() != BOT) || (data.Price() <= 0) {
+		return o, err
+	}
+
+	max := int64(math.Floor(pf.Cash() / data.Price()))
+	if max <= 0 {
+		return o, errors.New("cannot size order: no cash available")
+	}
+	if o.Qty() > max {
+		o.SetQty(max)
+	}
+	return o, nil
+}
+
+// sizeOrder sets the qty of an order with the given size function of the price.
+// An order with a qty is not changed, an exit order closes the position of its symbol.
+// With reverse an order against an open position closes it and opens a position in the opposite direction.
+func sizeOrder(order OrderEvent, data DataEvent, pf PortfolioHandler, reverse bool, size func(float64) (int64, error)) (*Order, error) {
+	o := order.(*Order)
+
+	if o.Direction() == EXT {
+		return o, exitOrder(o, pf)
+	}
+
+	// signal already knows the qty
+	if o.Qty() > 0 {
+		return o, nil
+	}
+
+	price := data.Price()
+	if price <= 0 {
+		return o, fmt.Errorf("cannot size order: invalid price of %v", o.Symbol())
+	}
+
+	qty, err := directionalQty(o, pf, reverse, func() (int64, error) {
+		return size(price)
+	})
+	if err != nil {
+		return o, err
+	}
+	if qty <= 0 {
+		return o, fmt.Errorf("cannot size order: qty of %v is 0", o.Symbol())
+	}
+
+	o.SetQty(qty)
+	return o, nil
+}
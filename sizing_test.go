@@ -0,0 +1,253 @@
+package gobacktest
+
+import (
+	"testing"
+)
+
+func TestSizeHandlers(t *testing.T) {
+	var testCases = []struct {
+		msg      string
+		size     SizeHandler
+		bars     []*Bar // data history, the last bar is the data event of the order
+		cash     float64
+		holdings map[string]Position
+		order    *Order
+		expQty   int64
+		expErr   bool
+	}{
+		{"testing percent of equity",
+			&PercentOfEquity{Percent: 0.1},
+			[]*Bar{{Close: 100}},
+			100000, nil,
+			&Order{direction: BOT},
+			100, false,
+		},
+		{"testing percent of equity with known qty",
+			&PercentOfEquity{Percent: 0.1},
+			[]*Bar{{Close: 100}},
+			100000, nil,
+			&Order{direction: BOT, qty: 7},
+			7, false,
+		},
+		{"testing percent of equity with exit order",
+			&PercentOfEquity{Percent: 0.1},
+			[]*Bar{{Close: 100}},
+			100000, map[string]Position{"TEST.DE": {qty: 15}},
+			&Order{Event: Event{symbol: "TEST.DE"}, direction: EXT},
+			15, false,
+		},
+		{"testing percent of equity without price",
+			&PercentOfEquity{Percent: 0.1},
+			[]*Bar{{Close: 0}},
+			100000, nil,
+			&Order{direction: BOT},
+			0, true,
+		},
+		{"testing fixed fractional with stop distance",
+			&FixedFractional{Risk: 0.01, Distance: 5},
+			[]*Bar{{Close: 100}},
+			100000, nil,
+			&Order{direction: BOT},
+			200, false,
+		},
+		{"testing fixed fractional with percent stop distance",
+			&FixedFractional{Risk: 0.01, Percent: 0.1},
+			[]*Bar{{Close: 100}},
+			100000, nil,
+			&Order{direction: SLD},
+			100, false,
+		},
+		{"testing fixed fractional without stop distance",
+			&FixedFractional{Risk: 0.01},
+			[]*Bar{{Close: 100}},
+			100000, nil,
+			&Order{direction: BOT},
+			0, true,
+		},
+		{"testing atr size",
+			&ATRSize{Period: 2, Risk: 0.01},
+			[]*Bar{{High: 105, Low: 95, Close: 100}, {High: 105, Low: 95, Close: 100}, {High: 105, Low: 95, Close: 100}},
+			100000, nil,
+			&Order{direction: BOT},
+			100, false,
+		},
+		{"testing atr size without enough data",
+			&ATRSize{Period: 2, Risk: 0.01},
+			[]*Bar{{High: 105, Low: 95, Close: 100}},
+			100000, nil,
+			&Order{direction: BOT},
+			0, true,
+		},
+		{"testing volatility target",
+			&VolatilityTarget{Period: 2, Target: 0.01},
+			[]*Bar{{Close: 80}, {Close: 100}, {Close: 110}, {Close: 99}},
+			100000, nil,
+			&Order{direction: BOT},
+			101, false,
+		},
+		{"testing volatility target without volatility",
+			&VolatilityTarget{Period: 2, Target: 0.01},
+			[]*Bar{{Close: 100}, {Close: 100}, {Close: 100}},
+			100000, nil,
+			&Order{direction: BOT},
+			0, true,
+		},
+		{"testing cash limit on buy order",
+			&CashLimit{Size: &PercentOfEquity{Percent: 0.5}},
+			[]*Bar{{Close: 100}},
+			50000, map[string]Position{"BAS.DE": {qty: 1000, marketValue: 100000}},
+			&Order{direction: BOT},
+			500, false,
+		},
+		{"testing cash limit on sell order",
+			&CashLimit{Size: &PercentOfEquity{Percent: 0.5}},
+			[]*Bar{{Close: 100}},
+			50000, map[string]Position{"BAS.DE": {qty: 1000, marketValue: 100000}},
+			&Order{direction: SLD},
+			750, false,
+		},
+		{"testing cash limit without cash",
+			&CashLimit{Size: &PercentOfEquity{Percent: 0.5}},
+			[]*Bar{{Close: 100}},
+			0, map[string]Position{"BAS.DE": {qty: 1000, marketValue: 100000}},
+			&Order{direction: BOT},
+			0, true,
+		},
+	}
+
+	for _, tc := range testCases {
+		data := testHelperExchangeBars(tc.bars...)
+		var latest DataEvent
+		for event, ok := data.Next(); ok; event, ok = data.Next() {
+			latest = event
+		}
+
+		p := &Portfolio{cash: tc.cash, holdings: tc.holdings}
+		if s, ok := tc.size.(DataSetter); ok {
+			s.SetData(data)
+		}
+		if tc.order.Symbol() == "" {
+			tc.order.SetSymbol("TEST.DE")
+		}
+
+		order, err := tc.size.SizeOrder(tc.order, latest, p)
+		if ((err != nil) != tc.expErr) || (!tc.expErr && (order.Qty() != tc.expQty)) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.expQty, tc.expErr, order.Qty(), err)
+		}
+	}
+}
+
+func TestKellySize(t *testing.T) {
+	size := &KellySize{Lookback: 4, Fraction: 0.5, Default: 0.1}
+	p := &Portfolio{cash: 100000, holdings: map[string]Position{}}
+	data := &Bar{Event: Event{symbol: "TEST.DE"}, Close: 100}
+
+	fill := func(direction Direction, qty int64, price float64) *Fill {
+		return &Fill{Event: Event{symbol: "TEST.DE"}, direction: direction, qty: qty, price: price}
+	}
+
+	var testCases = []struct {
+		msg    string
+		fills  []*Fill // fills tracked before the order is sized
+		reset  bool
+		expQty int64
+		expErr bool
+	}{
+		{"testing without trades", nil, false, 100, false},
+		{"testing after a win",
+			[]*Fill{fill(BOT, 10, 100), fill(SLD, 10, 120)},
+			false, 100, false,
+		},
+		{"testing after a loss closed in two parts",
+			[]*Fill{fill(BOT, 10, 100), fill(SLD, 5, 90), fill(SLD, 5, 90)},
+			false, 100, false,
+		},
+		{"testing half kelly after two more trades closed before the sizing",
+			[]*Fill{fill(BOT, 10, 100), fill(SLD, 10, 120), fill(SLD, 10, 100), fill(BOT, 10, 110)},
+			false, 125, false,
+		},
+		{"testing open trade is not counted",
+			[]*Fill{fill(BOT, 10, 100)},
+			false, 125, false,
+		},
+		{"testing negative kelly",
+			[]*Fill{fill(SLD, 10, 50)},
+			false, 0, true,
+		},
+		{"testing after reset", nil, true, 100, false},
+	}
+
+	for _, tc := range testCases {
+		if tc.reset {
+			size.Reset()
+		}
+		for _, f := range tc.fills {
+			size.TrackFill(f)
+		}
+
+		order, err := size.SizeOrder(&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT}, data, p)
+		if ((err != nil) != tc.expErr) || (!tc.expErr && (order.Qty() != tc.expQty)) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.expQty, tc.expErr, order.Qty(), err)
+		}
+	}
+}
+
+func TestKellySizeWithoutLosses(t *testing.T) {
+	size := &KellySize{MinTrades: 3, Fraction: 0.5, Default: 0.1}
+	p := &Portfolio{cash: 100000, holdings: map[string]Position{}}
+	data := &Bar{Event: Event{symbol: "TEST.DE"}, Close: 100}
+
+	fill := func(direction Direction, qty int64, price float64) *Fill {
+		return &Fill{Event: Event{symbol: "TEST.DE"}, direction: direction, qty: qty, price: price}
+	}
+
+	var testCases = []struct {
+		msg    string
+		fills  []*Fill // fills tracked before the order is sized
+		expQty int64
+	}{
+		{"testing below the minimum trades",
+			[]*Fill{fill(BOT, 10, 100), fill(SLD, 10, 120)},
+			100,
+		},
+		{"testing wins without a loss",
+			[]*Fill{fill(BOT, 10, 100), fill(SLD, 10, 120), fill(BOT, 10, 100), fill(SLD, 10, 120)},
+			100,
+		},
+		{"testing half kelly after the first loss",
+			[]*Fill{fill(BOT, 10, 100), fill(SLD, 10, 90)},
+			312,
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, f := range tc.fills {
+			size.TrackFill(f)
+		}
+
+		order, err := size.SizeOrder(&Order{Event: Event{symbol: "TEST.DE"}, direction: BOT}, data, p)
+		if (err != nil) || (order.Qty() != tc.expQty) {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.expQty, nil, order.Qty(), err)
+		}
+	}
+}
+
+func TestKellySizeTracksPortfolioFills(t *testing.T) {
+	size := &KellySize{Lookback: 4, Fraction: 0.5, Default: 0.1}
+	p := &Portfolio{cash: 100000}
+	p.SetSizeManager(&CashLimit{Size: size})
+
+	for _, f := range []*Fill{
+		{Event: Event{symbol: "TEST.DE"}, direction: BOT, qty: 10, price: 100},
+		{Event: Event{symbol: "TEST.DE"}, direction: SLD, qty: 10, price: 120},
+	} {
+		p.OnFill(f, &Data{})
+	}
+
+	if trades := size.ledger.Trades(); (len(trades) != 1) || (trades[0].ProfitLoss != 200) {
+		t.Errorf("testing fills of the portfolio: \nexpected %v, \nactual   %v", "one trade with 200", trades)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"gonum.org/v1/gonum/stat"
@@ -31,6 +32,29 @@ type TransactionTracker interface {
 	Transactions() []FillEvent
 }
 
+// RiskTracker is responsible for tracking the decisions of the risk manager during a backtest
+type RiskTracker interface {
+	TrackRisk(RiskDecision)
+	RiskDecisions() []RiskDecision
+}
+
+// EquityCurver returns the equity curve build during a backtest
+type EquityCurver interface {
+	EquityCurve() []EquityPoint
+}
+
+// BreakerTracker is responsible for tracking the events of the circuit breaker during a backtest
+type BreakerTracker interface {
+	TrackBreaker(BreakerEvent)
+	BreakerEvents() []BreakerEvent
+}
+
+// EquitySampler records an equity point of the portfolio without updating the trades and the benchmark,
+// e.g. to sample the portfolio again after the fills of a timestamp.
+type EquitySampler interface {
+	Sample(DataEvent, PortfolioHandler)
+}
+
 // StatisticPrinter handles printing of the statistics to screen
 type StatisticPrinter interface {
 	PrintResult()
@@ -51,28 +75,176 @@ type Resulter interface {
 	SortinoRatio(float64) float64
 }
 
+// TradeResulter returns the closed round trip trades of the backtest and their metrics
+type TradeResulter interface {
+	Trades() []Trade
+	WinRate() float64
+	AverageWin() float64
+	AverageLoss() float64
+	ProfitFactor() float64
+	Expectancy() float64
+	AverageHoldingTime() time.Duration
+	MaxConsecutiveLosses() int
+}
+
+// Performer returns the performance summary of the equity curve
+type Performer interface {
+	Performance(PerformanceOptions) (Performance, error)
+}
+
 // Statistic is a basic test statistic, which holds simple lists of historic events
 type Statistic struct {
 	eventHistory       []EventHandler
 	transactionHistory []FillEvent
-	equity             []equityPoint
-	high               equityPoint
-	low                equityPoint
+	riskHistory        []RiskDecision
+	breakerHistory     []BreakerEvent
+	ledger             TradeLedger
+	benchmarkSymbols   []string
+	benchmarkFirst     map[string]float64
+	benchmarkLast      map[string]float64
+	benchmark          []EquityPoint
+	equity             []EquityPoint
+	high               EquityPoint
+	low                EquityPoint
+	lastHigh           EquityPoint // high before the last equity point
+	lastLow            EquityPoint // low before the last equity point
+}
+
+// EquityPoint is a single point of the equity curve, together with the exposure of the portfolio at that time.
+type EquityPoint struct {
+	timestamp      time.Time
+	equity         float64
+	equityReturn   float64
+	drawdown       float64
+	cash           float64
+	long           float64 // market value of the long positions
+	short          float64 // market value of the short positions
+	longPositions  int
+	shortPositions int
+	holdings       []PositionSnapshot // open positions sorted by symbol
+}
+
+// PositionSnapshot is an open position of the portfolio at the time of an equity point.
+type PositionSnapshot struct {
+	Symbol           string
+	Qty              int64 // positive on a long position, negative on a short position
+	AvgPrice         float64
+	MarketPrice      float64
+	MarketValue      float64
+	UnrealProfitLoss float64
+}
+
+// Time returns the timestamp of the equity point.
+func (e EquityPoint) Time() time.Time {
+	return e.timestamp
+}
+
+// Equity returns the value of the portfolio.
+func (e EquityPoint) Equity() float64 {
+	return e.equity
+}
+
+// Return returns the return relative to the equity point before.
+func (e EquityPoint) Return() float64 {
+	return e.equityReturn
+}
+
+// Drawdown returns the drawdown relative to the equity high.
+func (e EquityPoint) Drawdown() float64 {
+	return e.drawdown
+}
+
+// Cash returns the cash of the portfolio.
+func (e EquityPoint) Cash() float64 {
+	return e.cash
+}
+
+// LongExposure returns the market value of the long positions.
+func (e EquityPoint) LongExposure() float64 {
+	return e.long
+}
+
+// ShortExposure returns the market value of the short positions as positive value.
+func (e EquityPoint) ShortExposure() float64 {
+	return e.short
+}
+
+// GrossExposure returns the market value of all long and short positions.
+func (e EquityPoint) GrossExposure() float64 {
+	return e.long + e.short
 }
 
-type equityPoint struct {
-	timestamp    time.Time
-	equity       float64
-	equityReturn float64
-	drawdown     float64
+// NetExposure returns the market value of the long minus the short positions.
+func (e EquityPoint) NetExposure() float64 {
+	return e.long - e.short
+}
+
+// GrossLeverage returns the gross exposure relative to the equity.
+func (e EquityPoint) GrossLeverage() float64 {
+	if e.equity == 0 {
+		return 0
+	}
+	return math.Round(e.GrossExposure()/e.equity*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// NetLeverage returns the net exposure relative to the equity.
+func (e EquityPoint) NetLeverage() float64 {
+	if e.equity == 0 {
+		return 0
+	}
+	return math.Round(e.NetExposure()/e.equity*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// Positions returns the number of open positions.
+func (e EquityPoint) Positions() int {
+	return e.longPositions + e.shortPositions
+}
+
+// LongPositions returns the number of open long positions.
+func (e EquityPoint) LongPositions() int {
+	return e.longPositions
+}
+
+// ShortPositions returns the number of open short positions.
+func (e EquityPoint) ShortPositions() int {
+	return e.shortPositions
+}
+
+// Holdings returns the open positions sorted by symbol.
+func (e EquityPoint) Holdings() []PositionSnapshot {
+	return e.holdings
 }
 
 // Update the complete statistics to a given data event.
+// The equity curve keeps one equity point per timestamp, a later update of the same timestamp
+// replaces the equity point with the current state of the portfolio.
 func (s *Statistic) Update(d DataEvent, p PortfolioHandler) {
+	// record the excursions of the open trades
+	s.ledger.Update(d)
+
+	// track the value of the benchmark
+	s.updateBenchmark(d)
+
+	s.Sample(d, p)
+}
+
+// Sample records the equity point of the portfolio at the timestamp of the data event.
+// An equity point of the same timestamp is replaced with the current state of the portfolio.
+func (s *Statistic) Sample(d DataEvent, p PortfolioHandler) {
+	// replace the equity point of the same timestamp
+	if last, ok := s.lastEquityPoint(); ok && last.timestamp.Equal(d.Time()) {
+		s.equity = s.equity[:len(s.equity)-1]
+		s.high = s.lastHigh
+		s.low = s.lastLow
+	}
+	s.lastHigh = s.high
+	s.lastLow = s.low
+
 	// create new equity point based on current data timestamp and portfolio value
-	e := equityPoint{}
+	e := EquityPoint{}
 	e.timestamp = d.Time()
 	e.equity = p.Value()
+	e = s.calcExposure(e, p)
 
 	// calc equity return for current equity point
 	if len(s.equity) > 0 {
@@ -106,9 +278,10 @@ func (s Statistic) Events() []EventHandler {
 	return s.eventHistory
 }
 
-// TrackTransaction tracks a transaction aka a fill event
+// TrackTransaction tracks a transaction aka a fill event and pairs it into the round trip trades
 func (s *Statistic) TrackTransaction(f FillEvent) {
 	s.transactionHistory = append(s.transactionHistory, f)
+	s.ledger.OnFill(f)
 }
 
 // Transactions returns the complete events history
@@ -116,13 +289,56 @@ func (s Statistic) Transactions() []FillEvent {
 	return s.transactionHistory
 }
 
+// Trades returns all closed round trip trades
+func (s Statistic) Trades() []Trade {
+	return s.ledger.Trades()
+}
+
+// OpenTrades returns the trades, which are still open
+func (s Statistic) OpenTrades() []Trade {
+	return s.ledger.OpenTrades()
+}
+
+// TrackRisk tracks a scaled down or rejected order of the risk manager
+func (s *Statistic) TrackRisk(d RiskDecision) {
+	s.riskHistory = append(s.riskHistory, d)
+}
+
+// RiskDecisions returns the complete history of risk decisions
+func (s Statistic) RiskDecisions() []RiskDecision {
+	return s.riskHistory
+}
+
+// TrackBreaker tracks a state change of the circuit breaker
+func (s *Statistic) TrackBreaker(e BreakerEvent) {
+	s.breakerHistory = append(s.breakerHistory, e)
+}
+
+// BreakerEvents returns the complete history of circuit breaker events
+func (s Statistic) BreakerEvents() []BreakerEvent {
+	return s.breakerHistory
+}
+
+// EquityCurve returns the equity curve
+func (s Statistic) EquityCurve() []EquityPoint {
+	return s.equity
+}
+
 // Reset the statistic to a clean state
 func (s *Statistic) Reset() error {
 	s.eventHistory = nil
 	s.transactionHistory = nil
+	s.riskHistory = nil
+	s.breakerHistory = nil
+	s.ledger.Reset()
+	s.benchmarkFirst = nil
+	s.benchmarkLast = nil
+	s.benchmark = nil
 	s.equity = nil
-	s.high = equityPoint{}
-	s.low = equityPoint{}
+	s.high = EquityPoint{}
+	s.low = EquityPoint{}
+	s.lastHigh = EquityPoint{}
+	s.lastLow = EquityPoint{}
 	return nil
 }
 
@@ -135,6 +351,27 @@ func (s Statistic) PrintResult() {
 	for k, v := range s.Transactions() {
 		fmt.Printf("%d. Transaction: %v Action: %v Price: %f Qty: %d\n", k+1, v.Time().Format("2006-01-02"), v.Direction(), v.Price(), v.Qty())
 	}
+
+	if len(s.Trades()) > 0 {
+		fmt.Printf("Counted %d closed trades:\n", len(s.Trades()))
+		fmt.Printf("Win rate: %.2f%% Profit factor: %.2f Expectancy: %.2f\n", s.WinRate()*100, s.ProfitFactor(), s.Expectancy())
+		fmt.Printf("Average win: %.2f Average loss: %.2f Max consecutive losses: %d Average holding time: %v\n",
+			s.AverageWin(), s.AverageLoss(), s.MaxConsecutiveLosses(), s.AverageHoldingTime())
+	}
+
+	if len(s.RiskDecisions()) > 0 {
+		fmt.Printf("Counted %d risk decisions:\n", len(s.RiskDecisions()))
+	}
+	for k, v := range s.RiskDecisions() {
+		fmt.Printf("%d. Risk: %v %v Action: %v Qty: %d Approved: %d Reason: %v\n", k+1, v.Time.Format("2006-01-02"), v.Symbol, v.Direction, v.Qty, v.Approved, v.Reason)
+	}
+
+	if len(s.BreakerEvents()) > 0 {
+		fmt.Printf("Counted %d circuit breaker events:\n", len(s.BreakerEvents()))
+	}
+	for k, v := range s.BreakerEvents() {
+		fmt.Printf("%d. Breaker: %v Tripped: %v Flatten: %v Equity: %f Reason: %v\n", k+1, v.Time.Format("2006-01-02"), v.Tripped, v.Flatten, v.Equity, v.Reason)
+	}
 }
 
 // TotalEquityReturn calculates the the total return on the first and last equity point
@@ -177,7 +414,7 @@ func (s Statistic) MaxDrawdownDuration() (d time.Duration) {
 	}
 
 	// walk the equity slice up to find a higher equity point
-	maxPoint := equityPoint{}
+	maxPoint := EquityPoint{}
 	for index := i; index >= 0; index-- {
 		if s.equity[index].equity > maxPoint.equity {
 			maxPoint = s.equity[index]
@@ -188,7 +425,8 @@ func (s Statistic) MaxDrawdownDuration() (d time.Duration) {
 	return d
 }
 
-// SharpRatio returns the Sharp ratio compared to a risk free benchmark return.
+// SharpRatio returns the Sharp ratio per period compared to a risk free return per period.
+// Without any variation of the returns the ratio is 0. See Performance for the annualised ratio.
 func (s *Statistic) SharpRatio(riskfree float64) float64 {
 	var equityReturns = make([]float64, len(s.equity))
 
@@ -196,35 +434,144 @@ func (s *Statistic) SharpRatio(riskfree float64) float64 {
 		equityReturns[i] = v.equityReturn
 	}
 	mean, stddev := stat.MeanStdDev(equityReturns, nil)
+	if !(stddev > 0) {
+		return 0
+	}
 
 	sharp := (mean - riskfree) / stddev
 	return sharp
 }
 
-// SortinoRatio returns the Sortino ratio compared to a risk free benchmark return.
+// SortinoRatio returns the Sortino ratio per period compared to a risk free return per period.
+// Without any return below the risk free return the ratio is 0. See Performance for the annualised ratio.
 func (s *Statistic) SortinoRatio(riskfree float64) float64 {
-	var equityReturns = make([]float64, len(s.equity))
+	var excessReturns = make([]float64, len(s.equity))
 
 	for i, v := range s.equity {
-		equityReturns[i] = v.equityReturn
+		excessReturns[i] = v.equityReturn - riskfree
 	}
-	mean := stat.Mean(equityReturns, nil)
+	mean := stat.Mean(excessReturns, nil)
 
-	// sortino uses the stddev of only the negativ returns
-	var negReturns = []float64{}
-	for _, v := range equityReturns {
-		if v < 0 {
-			negReturns = append(negReturns, v)
-		}
+	// sortino uses the downside deviation of the returns below the risk free return
+	downside := downsideDeviation(excessReturns)
+	if downside == 0 {
+		return 0
 	}
-	stdDev := stat.StdDev(negReturns, nil)
 
-	sortino := (mean - riskfree) / stdDev
+	sortino := mean / downside
 	return sortino
 }
 
-// returns the first equityPoint
-func (s Statistic) firstEquityPoint() (ep equityPoint, ok bool) {
+// WinRate returns the share of closed trades with a profit.
+func (s Statistic) WinRate() float64 {
+	trades := s.Trades()
+	if len(trades) == 0 {
+		return 0
+	}
+
+	wins, _ := splitTrades(trades)
+	return math.Round(float64(len(wins))/float64(len(trades))*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// AverageWin returns the average profit of the winning trades.
+func (s Statistic) AverageWin() float64 {
+	wins, _ := splitTrades(s.Trades())
+	if len(wins) == 0 {
+		return 0
+	}
+	return math.Round(stat.Mean(wins, nil)*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// AverageLoss returns the average loss of the losing trades as negative value.
+func (s Statistic) AverageLoss() float64 {
+	_, losses := splitTrades(s.Trades())
+	if len(losses) == 0 {
+		return 0
+	}
+	return math.Round(stat.Mean(losses, nil)*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// ProfitFactor returns the gross profit of the winning trades divided by the gross loss of the losing trades.
+// Without losing trades the profit factor is infinite, without any trades it is 0.
+func (s Statistic) ProfitFactor() float64 {
+	wins, losses := splitTrades(s.Trades())
+	if len(wins) == 0 {
+		return 0
+	}
+	if len(losses) == 0 {
+		return math.Inf(1)
+	}
+
+	var won, lost float64
+	for _, w := range wins {
+		won += w
+	}
+	for _, l := range losses {
+		lost -= l
+	}
+	return math.Round(won/lost*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// Expectancy returns the average profit and loss per closed trade.
+func (s Statistic) Expectancy() float64 {
+	trades := s.Trades()
+	if len(trades) == 0 {
+		return 0
+	}
+
+	var total float64
+	for _, t := range trades {
+		total += t.ProfitLoss
+	}
+	return math.Round(total/float64(len(trades))*math.Pow10(DP)) / math.Pow10(DP)
+}
+
+// AverageHoldingTime returns the average holding period of the closed trades.
+func (s Statistic) AverageHoldingTime() time.Duration {
+	trades := s.Trades()
+	if len(trades) == 0 {
+		return 0
+	}
+
+	var total time.Duration
+	for _, t := range trades {
+		total += t.HoldingPeriod()
+	}
+	return total / time.Duration(len(trades))
+}
+
+// MaxConsecutiveLosses returns the longest series of losing trades.
+func (s Statistic) MaxConsecutiveLosses() int {
+	var max, current int
+	for _, t := range s.Trades() {
+		if t.ProfitLoss >= 0 {
+			current = 0
+			continue
+		}
+		current++
+		if current > max {
+			max = current
+		}
+	}
+	return max
+}
+
+// splitTrades returns the profit of the winning and the loss of the losing trades.
+// Trades without profit and loss are neither.
+func splitTrades(trades []Trade) (wins, losses []float64) {
+	for _, t := range trades {
+		switch {
+		case t.ProfitLoss > 0:
+			wins = append(wins, t.ProfitLoss)
+		case t.ProfitLoss < 0:
+			losses = append(losses, t.ProfitLoss)
+		}
+	}
+	return wins, losses
+}
+
+// returns the first EquityPoint
+func (s Statistic) firstEquityPoint() (ep EquityPoint, ok bool) {
 	if len(s.equity) <= 0 {
 		return ep, false
 	}
@@ -233,8 +580,8 @@ func (s Statistic) firstEquityPoint() (ep equityPoint, ok bool) {
 	return ep, true
 }
 
-// returns the last equityPoint
-func (s Statistic) lastEquityPoint() (ep equityPoint, ok bool) {
+// returns the last EquityPoint
+func (s Statistic) lastEquityPoint() (ep EquityPoint, ok bool) {
 	if len(s.equity) <= 0 {
 		return ep, false
 	}
@@ -244,7 +591,7 @@ func (s Statistic) lastEquityPoint() (ep equityPoint, ok bool) {
 }
 
 // calculates the equity return of an equity point relativ to the last equity point
-func (s Statistic) calcEquityReturn(e equityPoint) equityPoint {
+func (s Statistic) calcEquityReturn(e EquityPoint) EquityPoint {
 	last, ok := s.lastEquityPoint()
 	// no equity point before the current
 	if !ok {
@@ -267,8 +614,43 @@ func (s Statistic) calcEquityReturn(e equityPoint) equityPoint {
 	return e
 }
 
+// records the cash and the exposure of the portfolio with an equity point
+func (s Statistic) calcExposure(e EquityPoint, p PortfolioHandler) EquityPoint {
+	e.cash = p.Cash()
+
+	holder, ok := p.(Holder)
+	if !ok {
+		return e
+	}
+
+	for _, pos := range holder.Holdings() {
+		switch {
+		case pos.qty > 0:
+			e.long += pos.marketValue
+			e.longPositions++
+		case pos.qty < 0:
+			e.short += pos.marketValue
+			e.shortPositions++
+		default:
+			continue
+		}
+
+		e.holdings = append(e.holdings, PositionSnapshot{
+			Symbol:           pos.symbol,
+			Qty:              pos.qty,
+			AvgPrice:         pos.avgPrice,
+			MarketPrice:      pos.marketPrice,
+			MarketValue:      pos.marketValue,
+			UnrealProfitLoss: pos.unrealProfitLoss,
+		})
+	}
+	sort.Slice(e.holdings, func(i, j int) bool { return e.holdings[i].Symbol < e.holdings[j].Symbol })
+
+	return e
+}
+
 // calculates the drawdown of an equity point relativ to the latest high of the statistic handler
-func (s Statistic) calcDrawdown(e equityPoint) equityPoint {
+func (s Statistic) calcDrawdown(e EquityPoint) EquityPoint {
 	if s.high.equity == 0 {
 		e.drawdown = 0
 		return e
@@ -289,7 +671,7 @@ func (s Statistic) calcDrawdown(e equityPoint) equityPoint {
 }
 
 // returns the equity point with the maximum drawdown
-func (s Statistic) maxDrawdownPoint() (i int, ep equityPoint) {
+func (s Statistic) maxDrawdownPoint() (i int, ep EquityPoint) {
 	if len(s.equity) == 0 {
 		return 0, ep
 	}
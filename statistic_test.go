@@ -2,6 +2,7 @@ package gobacktest
 
 import (
 	"errors"
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -93,6 +94,11 @@ func TestTrackTransaction(t *testing.T) {
 				transactionHistory: []FillEvent{
 					&Fill{direction: BOT, qty: 100},
 				},
+				ledger: TradeLedger{
+					open: map[string]*Trade{
+						"": {Direction: BOT, Qty: 100, Fills: 1, qty: 100, entryQty: 100},
+					},
+				},
 			},
 		},
 	}
@@ -169,12 +175,12 @@ func TestResetStatistic(t *testing.T) {
 				transactionHistory: []FillEvent{
 					&Fill{qty: 100},
 				},
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 100},
 					{equity: 90},
 				},
-				high: equityPoint{equity: 100},
-				low:  equityPoint{equity: 90},
+				high: EquityPoint{equity: 100},
+				low:  EquityPoint{equity: 90},
 			},
 			Statistic{},
 		},
@@ -182,9 +188,9 @@ func TestResetStatistic(t *testing.T) {
 			Statistic{
 				eventHistory:       []EventHandler{},
 				transactionHistory: []FillEvent{},
-				equity:             []equityPoint{},
-				high:               equityPoint{},
-				low:                equityPoint{},
+				equity:             []EquityPoint{},
+				high:               EquityPoint{},
+				low:                EquityPoint{},
 			},
 			Statistic{},
 		},
@@ -209,7 +215,7 @@ func TestTotalEquityReturn(t *testing.T) {
 	}{
 		{"testing for multiple entryPoints",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 100, equityReturn: 0},
 					{equity: 120, equityReturn: 0.2},
 				},
@@ -218,7 +224,7 @@ func TestTotalEquityReturn(t *testing.T) {
 			nil},
 		{"testing for multiple entryPoints with same value",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 100, equityReturn: 0},
 					{equity: 100, equityReturn: 0},
 				},
@@ -227,7 +233,7 @@ func TestTotalEquityReturn(t *testing.T) {
 			nil},
 		{"testing for last entryPoints with 0 equity",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 100, equityReturn: 0.1},
 					{equity: 0, equityReturn: -1},
 				},
@@ -251,19 +257,19 @@ func TestTotalEquityReturn(t *testing.T) {
 func TestGetEquityPoint(t *testing.T) {
 	var statCases = map[string]Statistic{
 		"multiple": {
-			equity: []equityPoint{
+			equity: []EquityPoint{
 				{equity: 100, equityReturn: 0.1},
 				{equity: 110, equityReturn: 0.2},
 				{equity: 120, equityReturn: 0.3},
 			},
 		},
 		"single": {
-			equity: []equityPoint{
+			equity: []EquityPoint{
 				{equity: 150, equityReturn: 0.25},
 			},
 		},
 		"empty": {
-			equity: []equityPoint{},
+			equity: []EquityPoint{},
 		},
 	}
 
@@ -271,7 +277,7 @@ func TestGetEquityPoint(t *testing.T) {
 	type testCase struct {
 		msg   string
 		stat  Statistic
-		expEP equityPoint
+		expEP EquityPoint
 		expOk bool
 	}
 
@@ -279,15 +285,15 @@ func TestGetEquityPoint(t *testing.T) {
 	var testCasesFirst = []testCase{
 		{"testing first for multiple entryPoints",
 			statCases["multiple"],
-			equityPoint{equity: 100, equityReturn: 0.1},
+			EquityPoint{equity: 100, equityReturn: 0.1},
 			true},
 		{"testing first for single entryPoints",
 			statCases["single"],
-			equityPoint{equity: 150, equityReturn: 0.25},
+			EquityPoint{equity: 150, equityReturn: 0.25},
 			true},
 		{"testing first for nil entryPoints",
 			statCases["empty"],
-			equityPoint{},
+			EquityPoint{},
 			false},
 	}
 
@@ -303,15 +309,15 @@ func TestGetEquityPoint(t *testing.T) {
 	var testCasesLast = []testCase{
 		{"testing last for multiple entryPoints",
 			statCases["multiple"],
-			equityPoint{equity: 120, equityReturn: 0.3},
+			EquityPoint{equity: 120, equityReturn: 0.3},
 			true},
 		{"testing last for single entryPoints",
 			statCases["single"],
-			equityPoint{equity: 150, equityReturn: 0.25},
+			EquityPoint{equity: 150, equityReturn: 0.25},
 			true},
 		{"testing last for nil entryPoints",
 			statCases["empty"],
-			equityPoint{},
+			EquityPoint{},
 			false},
 	}
 
@@ -324,57 +330,103 @@ func TestGetEquityPoint(t *testing.T) {
 	}
 }
 
+func TestUpdateEquityPoint(t *testing.T) {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	portfolio := &Portfolio{
+		cash: 1000,
+		holdings: map[string]Position{
+			"A": {qty: 10, marketValue: 1000},
+			"B": {qty: 5, marketValue: 500},
+			"C": {qty: -10, marketValue: 500},
+		},
+	}
+
+	stat := &Statistic{}
+	stat.Update(&Bar{Event: Event{timestamp: start, symbol: "A"}}, &Portfolio{cash: 1000})
+	stat.Update(&Bar{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "A"}}, &Portfolio{cash: 500})
+	stat.Update(&Bar{Event: Event{timestamp: start.AddDate(0, 0, 1), symbol: "B"}}, portfolio)
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing one equity point per timestamp", len(stat.equity), 2},
+		{"testing replaced equity", stat.equity[1].Equity(), 2000.0},
+		{"testing replaced return", stat.equity[1].Return(), 1.0},
+		{"testing replaced drawdown", stat.equity[1].Drawdown(), 0.0},
+		{"testing high of replaced equity point", stat.high.Equity(), 2000.0},
+		{"testing cash", stat.equity[1].Cash(), 1000.0},
+		{"testing long exposure", stat.equity[1].LongExposure(), 1500.0},
+		{"testing short exposure", stat.equity[1].ShortExposure(), 500.0},
+		{"testing gross exposure", stat.equity[1].GrossExposure(), 2000.0},
+		{"testing net exposure", stat.equity[1].NetExposure(), 1000.0},
+		{"testing gross leverage", stat.equity[1].GrossLeverage(), 1.0},
+		{"testing net leverage", stat.equity[1].NetLeverage(), 0.5},
+		{"testing positions", stat.equity[1].Positions(), 3},
+		{"testing long positions", stat.equity[1].LongPositions(), 2},
+		{"testing short positions", stat.equity[1].ShortPositions(), 1},
+		{"testing leverage without equity", EquityPoint{long: 100}.GrossLeverage(), 0.0},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
+
 func TestCalcEquityReturn(t *testing.T) {
 	var testCases = []struct {
 		msg   string
 		stat  Statistic
-		ep    equityPoint
-		expEP equityPoint
+		ep    EquityPoint
+		expEP EquityPoint
 	}{
 		{"testing equity return with single equity points",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 100},
 				},
 			},
-			equityPoint{equity: 90},
-			equityPoint{
+			EquityPoint{equity: 90},
+			EquityPoint{
 				equity:       90,
 				equityReturn: -0.1,
 			},
 		},
 		{"testing equity return with multiple equity points",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 100},
 					{equity: 90},
 					{equity: 110},
 				},
 			},
-			equityPoint{equity: 100},
-			equityPoint{
+			EquityPoint{equity: 100},
+			EquityPoint{
 				equity:       100,
 				equityReturn: -0.0909,
 			},
 		},
 		{"testing equity return with single equity points but 0 equity",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equity: 0},
 				},
 			},
-			equityPoint{equity: 100},
-			equityPoint{
+			EquityPoint{equity: 100},
+			EquityPoint{
 				equity:       100,
 				equityReturn: 1,
 			},
 		},
 		{"testing equity return with nil equity points",
 			Statistic{
-				equity: []equityPoint{},
+				equity: []EquityPoint{},
 			},
-			equityPoint{equity: 100},
-			equityPoint{
+			EquityPoint{equity: 100},
+			EquityPoint{
 				equity:   100,
 				drawdown: 0,
 			},
@@ -395,43 +447,43 @@ func TestCalcDrawdown(t *testing.T) {
 	var testCases = []struct {
 		msg   string
 		stat  Statistic
-		ep    equityPoint
-		expEP equityPoint
+		ep    EquityPoint
+		expEP EquityPoint
 	}{
-		{"testing drawdown with simple high equityPoint",
+		{"testing drawdown with simple high EquityPoint",
 			Statistic{
-				high: equityPoint{equity: 100},
+				high: EquityPoint{equity: 100},
 			},
-			equityPoint{equity: 90},
-			equityPoint{
+			EquityPoint{equity: 90},
+			EquityPoint{
 				equity:   90,
 				drawdown: -0.1,
 			},
 		},
-		{"testing drawdown with simple high equityPoint equal equity",
+		{"testing drawdown with simple high EquityPoint equal equity",
 			Statistic{
-				high: equityPoint{equity: 100},
+				high: EquityPoint{equity: 100},
 			},
-			equityPoint{equity: 100},
-			equityPoint{
+			EquityPoint{equity: 100},
+			EquityPoint{
 				equity:   100,
 				drawdown: 0,
 			},
 		},
-		{"testing drawdown with simple high equityPoint lower equity",
+		{"testing drawdown with simple high EquityPoint lower equity",
 			Statistic{
-				high: equityPoint{equity: 90},
+				high: EquityPoint{equity: 90},
 			},
-			equityPoint{equity: 100},
-			equityPoint{
+			EquityPoint{equity: 100},
+			EquityPoint{
 				equity:   100,
 				drawdown: 0,
 			},
 		},
-		{"testing drawdown with empty high equityPoint",
+		{"testing drawdown with empty high EquityPoint",
 			Statistic{},
-			equityPoint{equity: 100},
-			equityPoint{
+			EquityPoint{equity: 100},
+			EquityPoint{
 				equity:   100,
 				drawdown: 0,
 			},
@@ -459,7 +511,7 @@ func TestMaxDrawdown(t *testing.T) {
 	var testCases = []struct {
 		msg     string
 		stat    Statistic
-		expEP   equityPoint
+		expEP   EquityPoint
 		expInt  int
 		expMax  float64
 		expTime time.Time
@@ -467,7 +519,7 @@ func TestMaxDrawdown(t *testing.T) {
 	}{
 		{"testing maxdrawdown for multiple entryPoints",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{timestamp: time1, equity: 100, drawdown: 0},
 					{timestamp: time2, equity: 110, drawdown: 0},
 					{timestamp: time3, equity: 105, drawdown: -0.0455},
@@ -475,7 +527,7 @@ func TestMaxDrawdown(t *testing.T) {
 					{timestamp: time5, drawdown: 0},
 				},
 			},
-			equityPoint{timestamp: time4, equity: 95, drawdown: -0.1364},
+			EquityPoint{timestamp: time4, equity: 95, drawdown: -0.1364},
 			3,
 			-0.1364,
 			time4,
@@ -483,11 +535,11 @@ func TestMaxDrawdown(t *testing.T) {
 		},
 		{"testing maxdrawdown for single entryPoints",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{timestamp: time1, equity: 100, drawdown: 0},
 				},
 			},
-			equityPoint{timestamp: time1, equity: 100, drawdown: 0},
+			EquityPoint{timestamp: time1, equity: 100, drawdown: 0},
 			0,
 			0,
 			time1,
@@ -495,7 +547,7 @@ func TestMaxDrawdown(t *testing.T) {
 		},
 		{"testing maxdrawdown for nil entryPoints",
 			Statistic{},
-			equityPoint{},
+			EquityPoint{},
 			0,
 			0,
 			time.Time{},
@@ -549,7 +601,7 @@ func TestSharpRatio(t *testing.T) {
 	}{
 		{"testing simple positiv sharp ratio",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equityReturn: 1},
 					{equityReturn: 2},
 					{equityReturn: 3},
@@ -559,7 +611,7 @@ func TestSharpRatio(t *testing.T) {
 			2},
 		{"testing simple zero sharp ratio",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equityReturn: -1},
 					{equityReturn: 0},
 					{equityReturn: 1},
@@ -567,6 +619,15 @@ func TestSharpRatio(t *testing.T) {
 			},
 			0,
 			0},
+		{"testing sharp ratio without variation",
+			Statistic{
+				equity: []EquityPoint{
+					{equityReturn: 1},
+					{equityReturn: 1},
+				},
+			},
+			0,
+			0},
 	}
 
 	for _, tc := range testCases {
@@ -587,7 +648,7 @@ func TestSortinoRatio(t *testing.T) {
 	}{
 		{"testing simple sortino ratio",
 			Statistic{
-				equity: []equityPoint{
+				equity: []EquityPoint{
 					{equityReturn: -3},
 					{equityReturn: -2},
 					{equityReturn: -1},
@@ -596,7 +657,25 @@ func TestSortinoRatio(t *testing.T) {
 				},
 			},
 			0,
-			-1},
+			-1 / math.Sqrt(2.8)},
+		{"testing sortino ratio against risk free return",
+			Statistic{
+				equity: []EquityPoint{
+					{equityReturn: 1},
+					{equityReturn: 3},
+				},
+			},
+			2,
+			0},
+		{"testing sortino ratio without downside",
+			Statistic{
+				equity: []EquityPoint{
+					{equityReturn: 1},
+					{equityReturn: 2},
+				},
+			},
+			0,
+			0},
 	}
 
 	for _, tc := range testCases {
@@ -15,14 +15,45 @@ type StrategyHandler interface {
 	OnData(DataEvent) ([]SignalEvent, error)
 }
 
+// DataSlicer carries the data slice of the current run of a strategy.
+type DataSlicer interface {
+	DataSlice() (*DataSlice, bool)
+	SetDataSlice(*DataSlice) error
+}
+
+// OnDataSlicer is an interface for the OnDataSlice method of a strategy.
+type OnDataSlicer interface {
+	OnDataSlice(*DataSlice) ([]SignalEvent, error)
+}
+
+// Selector carries the symbols selected by the algo stack.
+// It is not part of StrategyHandler, selection algos need a strategy implementing it.
+type Selector interface {
+	Selected() ([]string, bool)
+	SetSelected(...string)
+}
+
+// Weigher carries the target weights computed by the algo stack.
+// It is not part of StrategyHandler, weighting algos need a strategy implementing it.
+type Weigher interface {
+	Weights() (map[string]float64, bool)
+	SetWeights(map[string]float64)
+}
+
 // Strategy implements NodeHandler via Node, used as a strategy building block.
 type Strategy struct {
 	Node
-	algos     AlgoStack
-	data      DataHandler
-	portfolio PortfolioHandler
-	event     DataEvent
-	signals   []SignalEvent
+	algos       AlgoStack
+	sliceAlgos  AlgoStack
+	data        DataHandler
+	portfolio   PortfolioHandler
+	event       DataEvent
+	dataSlice   *DataSlice
+	signals     []SignalEvent
+	selected    []string
+	weights     map[string]float64
+	capital     float64
+	performance []NodePerformance
 }
 
 // NewStrategy return a new strategy node ready to use.
@@ -71,6 +102,13 @@ func (s *Strategy) Portfolio() (PortfolioHandler, bool) {
 
 // SetPortfolio sets the portfolio property.
 func (s *Strategy) SetPortfolio(portfolio PortfolioHandler) error {
+	// the nodes of the tree share the positions of the portfolio
+	if s.Root() {
+		if err := s.checkAssets(make(map[string]string)); err != nil {
+			return err
+		}
+	}
+
 	s.portfolio = portfolio
 
 	// check for sub strategies and set their portfolio as well
@@ -101,6 +139,49 @@ func (s *Strategy) SetEvent(event DataEvent) error {
 	return nil
 }
 
+// DataSlice returns the current data slice of the strategy.
+func (s *Strategy) DataSlice() (*DataSlice, bool) {
+	if s.dataSlice == nil {
+		return nil, false
+	}
+
+	return s.dataSlice, true
+}
+
+// SetDataSlice sets the data slice property.
+func (s *Strategy) SetDataSlice(slice *DataSlice) error {
+	s.dataSlice = slice
+	return nil
+}
+
+// Selected returns the symbols selected by the algo stack.
+func (s *Strategy) Selected() ([]string, bool) {
+	if len(s.selected) == 0 {
+		return s.selected, false
+	}
+
+	return s.selected, true
+}
+
+// SetSelected sets the selected symbols.
+func (s *Strategy) SetSelected(symbols ...string) {
+	s.selected = symbols
+}
+
+// Weights returns the target weights computed by the algo stack.
+func (s *Strategy) Weights() (map[string]float64, bool) {
+	if len(s.weights) == 0 {
+		return s.weights, false
+	}
+
+	return s.weights, true
+}
+
+// SetWeights sets the target weights.
+func (s *Strategy) SetWeights(weights map[string]float64) {
+	s.weights = weights
+}
+
 // Signals returns a slice of all from th ealgo loop created signals.
 func (s *Strategy) Signals() ([]SignalEvent, bool) {
 	if len(s.signals) == 0 {
@@ -126,6 +207,44 @@ func (s *Strategy) SetAlgo(algos ...AlgoHandler) *Strategy {
 	return s
 }
 
+// Reset the strategy and its sub strategies into a clean state.
+func (s *Strategy) Reset() error {
+	s.event = nil
+	s.dataSlice = nil
+	s.signals = nil
+	s.selected = nil
+	s.weights = nil
+	s.capital = 0
+	s.performance = nil
+
+	if err := s.algos.Reset(); err != nil {
+		return err
+	}
+	if err := s.sliceAlgos.Reset(); err != nil {
+		return err
+	}
+
+	if strategies, ok := s.Strategies(); ok {
+		for _, strategy := range strategies {
+			if r, ok := strategy.(Reseter); ok {
+				if err := r.Reset(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetSliceAlgo sets the algo stack for the Strategy, which runs on every data slice.
+func (s *Strategy) SetSliceAlgo(algos ...AlgoHandler) *Strategy {
+	for _, algo := range algos {
+		s.sliceAlgos.stack = append(s.sliceAlgos.stack, algo)
+	}
+	return s
+}
+
 // Strategies return all children which are a strategy.
 func (s *Strategy) Strategies() ([]StrategyHandler, bool) {
 	var strategies []StrategyHandler
@@ -185,6 +304,8 @@ func (s *Strategy) Assets() ([]*Asset, bool) {
 // OnData handles an incoming data event. It runs the algo stack on this data.
 func (s *Strategy) OnData(event DataEvent) (signals []SignalEvent, err error) {
 	s.SetEvent(event)
+	// a single data event is not part of a complete data slice
+	s.SetDataSlice(nil)
 
 	// run the algo stack of this strategy
 	ok, err := s.algos.Run(s)
@@ -213,3 +334,65 @@ func (s *Strategy) OnData(event DataEvent) (signals []SignalEvent, err error) {
 
 	return signals, nil
 }
+
+// OnDataSlice handles an incoming data slice. It runs the slice algo stack on this data slice.
+// If the strategy has child assets, the data slice is limited to the symbols of these assets.
+func (s *Strategy) OnDataSlice(slice *DataSlice) (signals []SignalEvent, err error) {
+	if assets, ok := s.Assets(); ok {
+		var symbols []string
+		for _, asset := range assets {
+			symbols = append(symbols, asset.Name())
+		}
+		s.SetDataSlice(slice.Filter(symbols...))
+	} else {
+		s.SetDataSlice(slice)
+	}
+
+	// selection and weights are computed fresh by the algo stack on every data slice
+	s.selected = nil
+	s.weights = nil
+
+	// allocate capital to this strategy and its sub strategies
+	s.allocate()
+
+	// run the slice algo stack of this strategy
+	ok, err := s.sliceAlgos.Run(s)
+	if !ok {
+		return nil, err
+	}
+
+	// rebalance the child assets to their target weights
+	s.AddSignal(s.rebalance()...)
+
+	// pass the complete data slice down to child strategies
+	if strategies, ok := s.Strategies(); ok {
+		for _, strategy := range strategies {
+			slicer, ok := strategy.(OnDataSlicer)
+			if !ok {
+				continue
+			}
+			signals, err := slicer.OnDataSlice(slice)
+			if err != nil {
+				return nil, err
+			}
+			s.AddSignal(signals...)
+		}
+	}
+
+	s.track(slice.Time())
+
+	signals, ok = s.Signals()
+	if !ok {
+		return nil, nil
+	}
+
+	// the root strategy releases sells before buys, so the cash of the sells is available for the buys
+	if s.Root() {
+		sortSellsFirst(signals)
+	}
+
+	// empty strategy signals collection
+	s.signals = nil
+
+	return signals, nil
+}
@@ -422,3 +422,64 @@ func TestStrategyMultipleSetAlgo(t *testing.T) {
 		t.Errorf("set multiple algos SetAlgo(): \nexpected %#v, \nactual %#v", testStrategy, strategy)
 	}
 }
+
+// sliceRecorder is an algo which records the symbols of the data slice it runs on.
+type sliceRecorder struct {
+	Algo
+	symbols *[]string
+}
+
+func (sr sliceRecorder) Run(s StrategyHandler) (bool, error) {
+	slice, ok := s.(DataSlicer).DataSlice()
+	if !ok {
+		return false, nil
+	}
+	*sr.symbols = slice.Symbols()
+	return true, nil
+}
+
+func TestStrategyOnDataSlice(t *testing.T) {
+	slice := NewDataSlice(
+		&Bar{Event: Event{symbol: "TEST.A"}},
+		&Bar{Event: Event{symbol: "TEST.B"}},
+		&Bar{Event: Event{symbol: "TEST.C"}},
+	)
+
+	var rootSymbols, subSymbols []string
+
+	sub := NewStrategy("sub")
+	sub.SetSliceAlgo(&sliceRecorder{symbols: &subSymbols})
+	sub.SetChildren(NewAsset("TEST.B"), NewAsset("TEST.C"))
+
+	root := NewStrategy("root")
+	root.SetSliceAlgo(&sliceRecorder{symbols: &rootSymbols})
+	root.SetChildren(sub)
+
+	_, err := root.OnDataSlice(slice)
+	if err != nil {
+		t.Errorf("OnDataSlice(): unexpected error %v", err)
+	}
+
+	if exp := []string{"TEST.A", "TEST.B", "TEST.C"}; !reflect.DeepEqual(rootSymbols, exp) {
+		t.Errorf("OnDataSlice() root strategy: \nexpected %v, \nactual   %v", exp, rootSymbols)
+	}
+	if exp := []string{"TEST.B", "TEST.C"}; !reflect.DeepEqual(subSymbols, exp) {
+		t.Errorf("OnDataSlice() sub strategy limited by assets: \nexpected %v, \nactual   %v", exp, subSymbols)
+	}
+}
+
+func TestOnDataWithAssets(t *testing.T) {
+	var events int
+	strategy := NewStrategy("assets").SetAlgo(&testCountAlgo{events: &events})
+	strategy.SetChildren(NewAsset("TEST.A"))
+
+	// the child assets limit the data slice, but not the data events of OnData
+	for _, symbol := range []string{"TEST.A", "TEST.B"} {
+		if _, err := strategy.OnData(&Bar{Event: Event{symbol: symbol}}); err != nil {
+			t.Errorf("OnData() %v: unexpected error %v", symbol, err)
+		}
+	}
+	if events != 2 {
+		t.Errorf("OnData() with child assets: \nexpected %v, \nactual   %v", 2, events)
+	}
+}
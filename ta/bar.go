@@ -0,0 +1,37 @@
+package ta
+
+// Bar holds the OHLCV values of a single bar,
+// used as input for indicators which need more than one price.
+type Bar struct {
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// typicalPrice returns the average of high, low and close.
+func (b Bar) typicalPrice() float64 {
+	return (b.High + b.Low + b.Close) / 3
+}
+
+// trueRange returns the true range of a bar compared to the close of the bar before.
+func (b Bar) trueRange(prevClose float64) float64 {
+	tr := b.High - b.Low
+	if d := b.High - prevClose; d > tr {
+		tr = d
+	}
+	if d := prevClose - b.Low; d > tr {
+		tr = d
+	}
+	return tr
+}
+
+// closes returns the close prices of a slice of bars.
+func closes(bars []Bar) []float64 {
+	values := make([]float64, len(bars))
+	for i, bar := range bars {
+		values[i] = bar.Close
+	}
+	return values
+}
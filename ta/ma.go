@@ -72,3 +72,107 @@ func EMA(values []float64, period int) ([]float64, error) {
 
 	return result, nil
 }
+
+// WMA calculates the linear Weighted Moving Average for the
+// supplied slice of float64 values for a given period.
+// The most recent value has the weight period, the oldest value the weight 1.
+func WMA(values []float64, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(values), period); err != nil {
+		return result, err
+	}
+
+	// sum of weights 1 + 2 + ... + period
+	divisor := float64(period*(period+1)) / 2
+
+	for i := period - 1; i < len(values); i++ {
+		var sum float64
+		for j := 0; j < period; j++ {
+			sum += values[i-period+1+j] * float64(j+1)
+		}
+		result = append(result, sum/divisor)
+	}
+
+	return result, nil
+}
+
+// DEMA calculates the Double Exponential Moving Average for the
+// supplied slice of float64 values for a given period.
+// DEMA = 2 * EMA - EMA(EMA)
+func DEMA(values []float64, period int) ([]float64, error) {
+	ema1, err := EMA(values, period)
+	if err != nil {
+		return nil, err
+	}
+
+	ema2, err := EMA(ema1, period)
+	if err != nil {
+		return nil, err
+	}
+
+	ema1 = tail(ema1, len(ema2))
+
+	result := make([]float64, len(ema2))
+	for i := range ema2 {
+		result[i] = 2*ema1[i] - ema2[i]
+	}
+
+	return result, nil
+}
+
+// TEMA calculates the Triple Exponential Moving Average for the
+// supplied slice of float64 values for a given period.
+// TEMA = 3 * EMA - 3 * EMA(EMA) + EMA(EMA(EMA))
+func TEMA(values []float64, period int) ([]float64, error) {
+	ema1, err := EMA(values, period)
+	if err != nil {
+		return nil, err
+	}
+
+	ema2, err := EMA(ema1, period)
+	if err != nil {
+		return nil, err
+	}
+
+	ema3, err := EMA(ema2, period)
+	if err != nil {
+		return nil, err
+	}
+
+	ema1 = tail(ema1, len(ema3))
+	ema2 = tail(ema2, len(ema3))
+
+	result := make([]float64, len(ema3))
+	for i := range ema3 {
+		result[i] = 3*ema1[i] - 3*ema2[i] + ema3[i]
+	}
+
+	return result, nil
+}
+
+// checkValues checks if enough values are given for a period.
+func checkValues(length, period int) error {
+	if length == 0 {
+		return fmt.Errorf("no values given")
+	}
+
+	if period <= 0 {
+		return fmt.Errorf("invalid period %v", period)
+	}
+
+	// enough values ?
+	if length < period {
+		return fmt.Errorf("invalid length of values, given %v, needs %v", length, period)
+	}
+
+	return nil
+}
+
+// tail returns the last n values of a slice.
+func tail(values []float64, n int) []float64 {
+	if n >= len(values) {
+		return values
+	}
+	return values[len(values)-n:]
+}
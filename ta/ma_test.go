@@ -124,3 +124,63 @@ func TestEMA(t *testing.T) {
 		}
 	}
 }
+
+func TestWMA(t *testing.T) {
+	var testCases = []struct {
+		msg    string
+		values []float64
+		period int
+		expWMA []float64
+		expErr error
+	}{
+		{"test zero values",
+			[]float64{},
+			3,
+			nil,
+			errors.New("no values given"),
+		},
+		{"test length of values less than period",
+			[]float64{1, 2},
+			3,
+			nil,
+			errors.New("invalid length of values, given 2, needs 3"),
+		},
+		{"test simple values",
+			[]float64{1, 2, 3, 4, 5},
+			3,
+			[]float64{14.0 / 6, 20.0 / 6, 26.0 / 6},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		wma, err := WMA(tc.values, tc.period)
+		if !testHelperEqual(wma, tc.expWMA) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("%v WMA(%v, %v): \nexpected %#v %v, \nactual   %#v %v",
+				tc.msg, tc.values, tc.period, tc.expWMA, tc.expErr, wma, err)
+		}
+	}
+}
+
+func TestDEMA(t *testing.T) {
+	// a linear series has no lag on the double exponential moving average
+	dema, err := DEMA(testHelperLinear(10), 3)
+	exp := []float64{5, 6, 7, 8, 9, 10}
+	if !testHelperEqual(dema, exp) || (err != nil) {
+		t.Errorf("DEMA(): \nexpected %v %v, \nactual   %v %v", exp, nil, dema, err)
+	}
+
+	_, err = DEMA(testHelperLinear(4), 3)
+	if err == nil {
+		t.Errorf("DEMA() too few values: expected error, actual %v", err)
+	}
+}
+
+func TestTEMA(t *testing.T) {
+	// a linear series has no lag on the triple exponential moving average
+	tema, err := TEMA(testHelperLinear(10), 3)
+	exp := []float64{7, 8, 9, 10}
+	if !testHelperEqual(tema, exp) || (err != nil) {
+		t.Errorf("TEMA(): \nexpected %v %v, \nactual   %v %v", exp, nil, tema, err)
+	}
+}
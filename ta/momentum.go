@@ -0,0 +1,174 @@
+package ta
+
+import (
+	"math"
+)
+
+// RSI calculates the Relative Strength Index with Wilder's smoothing
+// for the supplied slice of float64 values for a given period.
+// The first value is available after period + 1 values.
+func RSI(values []float64, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(values), period+1); err != nil {
+		return result, err
+	}
+
+	// average gain and loss of the first period
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	result = append(result, rsi(gain, loss))
+
+	// Wilder's smoothing for the following values
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		var g, l float64
+		if change > 0 {
+			g = change
+		} else {
+			l = -change
+		}
+		gain = (gain*float64(period-1) + g) / float64(period)
+		loss = (loss*float64(period-1) + l) / float64(period)
+		result = append(result, rsi(gain, loss))
+	}
+
+	return result, nil
+}
+
+// rsi calculates the rsi value from an average gain and loss.
+func rsi(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACD calculates the Moving Average Convergence Divergence for the supplied slice of float64 values.
+// It returns the macd line (fast EMA - slow EMA), the signal line (EMA of the macd line)
+// and the histogram (macd - signal), all aligned to the length of the signal line.
+func MACD(values []float64, fast, slow, signal int) (macd, sig, hist []float64, err error) {
+	emaFast, err := EMA(values, fast)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	emaSlow, err := EMA(values, slow)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	n := len(emaSlow)
+	if len(emaFast) < n {
+		n = len(emaFast)
+	}
+	emaFast = tail(emaFast, n)
+	emaSlow = tail(emaSlow, n)
+
+	macd = make([]float64, n)
+	for i := range macd {
+		macd[i] = emaFast[i] - emaSlow[i]
+	}
+
+	sig, err = EMA(macd, signal)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	macd = tail(macd, len(sig))
+	hist = make([]float64, len(sig))
+	for i := range sig {
+		hist[i] = macd[i] - sig[i]
+	}
+
+	return macd, sig, hist, nil
+}
+
+// Stochastic calculates the Stochastic Oscillator for the supplied slice of bars.
+// It returns %K over kPeriod bars and %D as the simple moving average of %K over dPeriod,
+// both aligned to the length of %D.
+func Stochastic(bars []Bar, kPeriod, dPeriod int) (k, d []float64, err error) {
+	if err := checkValues(len(bars), kPeriod); err != nil {
+		return nil, nil, err
+	}
+
+	for i := kPeriod - 1; i < len(bars); i++ {
+		high, low := highLow(bars[i-kPeriod+1 : i+1])
+		if high == low {
+			k = append(k, 50)
+			continue
+		}
+		k = append(k, (bars[i].Close-low)/(high-low)*100)
+	}
+
+	d, err = SMA(k, dPeriod)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tail(k, len(d)), d, nil
+}
+
+// CCI calculates the Commodity Channel Index for the supplied slice of bars for a given period.
+func CCI(bars []Bar, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(bars), period); err != nil {
+		return result, err
+	}
+
+	tp := make([]float64, len(bars))
+	for i, bar := range bars {
+		tp[i] = bar.typicalPrice()
+	}
+
+	for i := period - 1; i < len(tp); i++ {
+		window := tp[i-period+1 : i+1]
+		mean := Mean(window)
+
+		var meanDev float64
+		for _, v := range window {
+			meanDev += math.Abs(v - mean)
+		}
+		meanDev /= float64(period)
+
+		if meanDev == 0 {
+			result = append(result, 0)
+			continue
+		}
+		result = append(result, (tp[i]-mean)/(0.015*meanDev))
+	}
+
+	return result, nil
+}
+
+// ROC calculates the Rate of Change in percent for the supplied slice of float64 values for a given period.
+func ROC(values []float64, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(values), period+1); err != nil {
+		return result, err
+	}
+
+	for i := period; i < len(values); i++ {
+		if values[i-period] == 0 {
+			result = append(result, 0)
+			continue
+		}
+		result = append(result, (values[i]-values[i-period])/values[i-period]*100)
+	}
+
+	return result, nil
+}
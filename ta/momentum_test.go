@@ -0,0 +1,108 @@
+package ta
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRSI(t *testing.T) {
+	var testCases = []struct {
+		msg    string
+		values []float64
+		period int
+		expRSI []float64
+		expErr error
+	}{
+		{"test length of values less than period",
+			[]float64{1, 2},
+			2,
+			nil,
+			errors.New("invalid length of values, given 2, needs 3"),
+		},
+		{"test simple values",
+			[]float64{1, 2, 3, 2, 3},
+			2,
+			[]float64{100, 50, 75},
+			nil,
+		},
+		{"test constant values",
+			[]float64{1, 1, 1},
+			2,
+			[]float64{50},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		rsi, err := RSI(tc.values, tc.period)
+		if !testHelperEqual(rsi, tc.expRSI) || !reflect.DeepEqual(err, tc.expErr) {
+			t.Errorf("%v RSI(%v, %v): \nexpected %v %v, \nactual   %v %v",
+				tc.msg, tc.values, tc.period, tc.expRSI, tc.expErr, rsi, err)
+		}
+	}
+}
+
+func TestMACD(t *testing.T) {
+	// on a linear series the fast and slow ema have a constant distance
+	macd, sig, hist, err := MACD(testHelperLinear(10), 2, 3, 2)
+
+	expMACD := []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5}
+	expHist := []float64{0, 0, 0, 0, 0, 0, 0}
+	if !testHelperEqual(macd, expMACD) || !testHelperEqual(sig, expMACD) || !testHelperEqual(hist, expHist) || (err != nil) {
+		t.Errorf("MACD(): \nexpected %v %v %v %v, \nactual   %v %v %v %v",
+			expMACD, expMACD, expHist, nil, macd, sig, hist, err)
+	}
+}
+
+func TestStochastic(t *testing.T) {
+	bars := []Bar{
+		{High: 1, Low: 0, Close: 1},
+		{High: 2, Low: 1, Close: 2},
+		{High: 3, Low: 2, Close: 3},
+		{High: 4, Low: 3, Close: 3},
+		{High: 5, Low: 4, Close: 2},
+	}
+
+	k, d, err := Stochastic(bars, 3, 2)
+	// %K: close 3 in 1..4 = 66.67, close 2 in 2..5 = 0
+	expK := []float64{200.0 / 3, 0}
+	expD := []float64{(100 + 200.0/3) / 2, 100.0 / 3}
+	if !testHelperEqual(k, expK) || !testHelperEqual(d, expD) || (err != nil) {
+		t.Errorf("Stochastic(): \nexpected %v %v %v, \nactual   %v %v %v", expK, expD, nil, k, d, err)
+	}
+}
+
+func TestCCI(t *testing.T) {
+	bars := []Bar{
+		{High: 1, Low: 1, Close: 1},
+		{High: 2, Low: 2, Close: 2},
+		{High: 3, Low: 3, Close: 3},
+	}
+
+	cci, err := CCI(bars, 3)
+	exp := []float64{100}
+	if !testHelperEqual(cci, exp) || (err != nil) {
+		t.Errorf("CCI(): \nexpected %v %v, \nactual   %v %v", exp, nil, cci, err)
+	}
+}
+
+func TestROC(t *testing.T) {
+	var testCases = []struct {
+		msg    string
+		values []float64
+		period int
+		expROC []float64
+	}{
+		{"test period 1", []float64{100, 110, 121}, 1, []float64{10, 10}},
+		{"test period 2", []float64{100, 110, 121}, 2, []float64{21}},
+	}
+
+	for _, tc := range testCases {
+		roc, err := ROC(tc.values, tc.period)
+		if !testHelperEqual(roc, tc.expROC) || (err != nil) {
+			t.Errorf("%v ROC(%v, %v): \nexpected %v, \nactual   %v %v",
+				tc.msg, tc.values, tc.period, tc.expROC, roc, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ta
+
+// Indicator defines a streaming indicator, which is updated with one value at a time.
+// Each update costs O(1), independent of the length of the data already seen.
+type Indicator interface {
+	Update(float64)
+	Value() float64
+	Ready() bool
+	Reset()
+}
+
+// BarIndicator defines a streaming indicator, which is updated with one bar at a time.
+type BarIndicator interface {
+	Update(Bar)
+	Value() float64
+	Ready() bool
+	Reset()
+}
+
+// window is a fixed size ring buffer of float64 values.
+type window struct {
+	values []float64
+	next   int
+	count  int
+}
+
+// newWindow creates a ring buffer of the given size.
+func newWindow(size int) *window {
+	if size < 1 {
+		size = 1
+	}
+	return &window{values: make([]float64, size)}
+}
+
+// push adds a value to the window. If the window was full, it returns the dropped oldest value and true.
+func (w *window) push(v float64) (old float64, dropped bool) {
+	if w.full() {
+		old = w.values[w.next]
+		dropped = true
+	} else {
+		w.count++
+	}
+
+	w.values[w.next] = v
+	w.next = (w.next + 1) % len(w.values)
+
+	return old, dropped
+}
+
+// full checks if the window holds as many values as its size.
+func (w *window) full() bool {
+	return w.count == len(w.values)
+}
+
+// oldest returns the oldest value in the window.
+func (w *window) oldest() float64 {
+	if !w.full() {
+		return w.values[0]
+	}
+	return w.values[w.next]
+}
+
+// each calls fn for every value in the window, from the oldest to the newest.
+func (w *window) each(fn func(float64)) {
+	start := 0
+	if w.full() {
+		start = w.next
+	}
+	for i := 0; i < w.count; i++ {
+		fn(w.values[(start+i)%len(w.values)])
+	}
+}
+
+// reset empties the window.
+func (w *window) reset() {
+	w.next = 0
+	w.count = 0
+}
+
+// extremum keeps the maximum or minimum of the last period values in a monotonic queue,
+// with an amortized cost of O(1) per update.
+type extremum struct {
+	period int
+	max    bool
+	n      int
+	index  []int
+	values []float64
+}
+
+// newExtremum creates a rolling maximum, or minimum if max is false, over period values.
+func newExtremum(period int, max bool) *extremum {
+	return &extremum{period: period, max: max}
+}
+
+// push adds a value to the rolling extremum.
+func (e *extremum) push(v float64) {
+	// remove all values from the back, which can never be the extremum again
+	for len(e.values) > 0 {
+		last := e.values[len(e.values)-1]
+		if (e.max && last > v) || (!e.max && last < v) {
+			break
+		}
+		e.index = e.index[:len(e.index)-1]
+		e.values = e.values[:len(e.values)-1]
+	}
+
+	e.index = append(e.index, e.n)
+	e.values = append(e.values, v)
+	e.n++
+
+	// remove the front value, if it is out of the period
+	if e.index[0] <= e.n-1-e.period {
+		e.index = e.index[1:]
+		e.values = e.values[1:]
+	}
+}
+
+// value returns the current extremum.
+func (e *extremum) value() float64 {
+	if len(e.values) == 0 {
+		return 0
+	}
+	return e.values[0]
+}
+
+// reset empties the rolling extremum.
+func (e *extremum) reset() {
+	e.n = 0
+	e.index = nil
+	e.values = nil
+}
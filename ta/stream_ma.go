@@ -0,0 +1,224 @@
+package ta
+
+// SimpleMovingAverage is the streaming version of SMA.
+type SimpleMovingAverage struct {
+	period int
+	window *window
+	sum    float64
+}
+
+// NewSMA creates a streaming simple moving average for a given period.
+func NewSMA(period int) *SimpleMovingAverage {
+	return &SimpleMovingAverage{period: period, window: newWindow(period)}
+}
+
+// Update adds a new value.
+func (i *SimpleMovingAverage) Update(v float64) {
+	old, dropped := i.window.push(v)
+	i.sum += v
+	if dropped {
+		i.sum -= old
+	}
+}
+
+// Value returns the current moving average.
+func (i *SimpleMovingAverage) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.sum / float64(i.period)
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *SimpleMovingAverage) Ready() bool {
+	return i.window.full()
+}
+
+// Reset the indicator into a clean state.
+func (i *SimpleMovingAverage) Reset() {
+	i.window.reset()
+	i.sum = 0
+}
+
+// ExponentialMovingAverage is the streaming version of EMA.
+// Like EMA, it uses the simple moving average of the first period as first value.
+type ExponentialMovingAverage struct {
+	multiplier float64
+	sma        *SimpleMovingAverage
+	value      float64
+	ready      bool
+}
+
+// NewEMA creates a streaming exponential moving average for a given period.
+func NewEMA(period int) *ExponentialMovingAverage {
+	return &ExponentialMovingAverage{
+		multiplier: float64(2) / float64(period+1),
+		sma:        NewSMA(period),
+	}
+}
+
+// Update adds a new value.
+func (i *ExponentialMovingAverage) Update(v float64) {
+	if !i.ready {
+		i.sma.Update(v)
+		if i.sma.Ready() {
+			i.value = i.sma.Value()
+			i.ready = true
+		}
+		return
+	}
+
+	i.value = (v-i.value)*i.multiplier + i.value
+}
+
+// Value returns the current moving average.
+func (i *ExponentialMovingAverage) Value() float64 {
+	return i.value
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *ExponentialMovingAverage) Ready() bool {
+	return i.ready
+}
+
+// Reset the indicator into a clean state.
+func (i *ExponentialMovingAverage) Reset() {
+	i.sma.Reset()
+	i.value = 0
+	i.ready = false
+}
+
+// WeightedMovingAverage is the streaming version of WMA.
+type WeightedMovingAverage struct {
+	period int
+	window *window
+	sum    float64 // sum of the values in the window
+	wsum   float64 // weighted sum of the values in the window
+}
+
+// NewWMA creates a streaming weighted moving average for a given period.
+func NewWMA(period int) *WeightedMovingAverage {
+	return &WeightedMovingAverage{period: period, window: newWindow(period)}
+}
+
+// Update adds a new value.
+func (i *WeightedMovingAverage) Update(v float64) {
+	// while filling the window, the sums are calculated once it is full
+	if !i.window.full() {
+		i.window.push(v)
+		if i.window.full() {
+			var weight float64
+			i.window.each(func(value float64) {
+				weight++
+				i.sum += value
+				i.wsum += weight * value
+			})
+		}
+		return
+	}
+
+	// each value loses one weight, the new value gets the full weight
+	old, _ := i.window.push(v)
+	i.wsum = i.wsum - i.sum + float64(i.period)*v
+	i.sum = i.sum - old + v
+}
+
+// Value returns the current moving average.
+func (i *WeightedMovingAverage) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.wsum / (float64(i.period*(i.period+1)) / 2)
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *WeightedMovingAverage) Ready() bool {
+	return i.window.full()
+}
+
+// Reset the indicator into a clean state.
+func (i *WeightedMovingAverage) Reset() {
+	i.window.reset()
+	i.sum = 0
+	i.wsum = 0
+}
+
+// DoubleExponentialMovingAverage is the streaming version of DEMA.
+type DoubleExponentialMovingAverage struct {
+	ema1, ema2 *ExponentialMovingAverage
+}
+
+// NewDEMA creates a streaming double exponential moving average for a given period.
+func NewDEMA(period int) *DoubleExponentialMovingAverage {
+	return &DoubleExponentialMovingAverage{ema1: NewEMA(period), ema2: NewEMA(period)}
+}
+
+// Update adds a new value.
+func (i *DoubleExponentialMovingAverage) Update(v float64) {
+	i.ema1.Update(v)
+	if i.ema1.Ready() {
+		i.ema2.Update(i.ema1.Value())
+	}
+}
+
+// Value returns the current moving average.
+func (i *DoubleExponentialMovingAverage) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return 2*i.ema1.Value() - i.ema2.Value()
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *DoubleExponentialMovingAverage) Ready() bool {
+	return i.ema2.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *DoubleExponentialMovingAverage) Reset() {
+	i.ema1.Reset()
+	i.ema2.Reset()
+}
+
+// TripleExponentialMovingAverage is the streaming version of TEMA.
+type TripleExponentialMovingAverage struct {
+	ema1, ema2, ema3 *ExponentialMovingAverage
+}
+
+// NewTEMA creates a streaming triple exponential moving average for a given period.
+func NewTEMA(period int) *TripleExponentialMovingAverage {
+	return &TripleExponentialMovingAverage{ema1: NewEMA(period), ema2: NewEMA(period), ema3: NewEMA(period)}
+}
+
+// Update adds a new value.
+func (i *TripleExponentialMovingAverage) Update(v float64) {
+	i.ema1.Update(v)
+	if !i.ema1.Ready() {
+		return
+	}
+	i.ema2.Update(i.ema1.Value())
+	if !i.ema2.Ready() {
+		return
+	}
+	i.ema3.Update(i.ema2.Value())
+}
+
+// Value returns the current moving average.
+func (i *TripleExponentialMovingAverage) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return 3*i.ema1.Value() - 3*i.ema2.Value() + i.ema3.Value()
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *TripleExponentialMovingAverage) Ready() bool {
+	return i.ema3.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *TripleExponentialMovingAverage) Reset() {
+	i.ema1.Reset()
+	i.ema2.Reset()
+	i.ema3.Reset()
+}
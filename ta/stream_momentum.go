@@ -0,0 +1,298 @@
+package ta
+
+// RelativeStrengthIndex is the streaming version of RSI.
+type RelativeStrengthIndex struct {
+	period int
+	count  int
+	last   float64
+	gain   float64
+	loss   float64
+}
+
+// NewRSI creates a streaming relative strength index for a given period.
+func NewRSI(period int) *RelativeStrengthIndex {
+	return &RelativeStrengthIndex{period: period}
+}
+
+// Update adds a new value.
+func (i *RelativeStrengthIndex) Update(v float64) {
+	i.count++
+	if i.count == 1 {
+		i.last = v
+		return
+	}
+
+	change := v - i.last
+	i.last = v
+
+	var g, l float64
+	if change > 0 {
+		g = change
+	} else {
+		l = -change
+	}
+
+	// sum up the first period, then use Wilder's smoothing
+	changes := i.count - 1
+	switch {
+	case changes < i.period:
+		i.gain += g
+		i.loss += l
+	case changes == i.period:
+		i.gain = (i.gain + g) / float64(i.period)
+		i.loss = (i.loss + l) / float64(i.period)
+	default:
+		i.gain = (i.gain*float64(i.period-1) + g) / float64(i.period)
+		i.loss = (i.loss*float64(i.period-1) + l) / float64(i.period)
+	}
+}
+
+// Value returns the current rsi.
+func (i *RelativeStrengthIndex) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return rsi(i.gain, i.loss)
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *RelativeStrengthIndex) Ready() bool {
+	return i.count > i.period
+}
+
+// Reset the indicator into a clean state.
+func (i *RelativeStrengthIndex) Reset() {
+	i.count = 0
+	i.last = 0
+	i.gain = 0
+	i.loss = 0
+}
+
+// MovingAverageConvergenceDivergence is the streaming version of MACD.
+// Value returns the macd line, Signal and Hist the signal line and the histogram.
+type MovingAverageConvergenceDivergence struct {
+	fast, slow, signal *ExponentialMovingAverage
+	macd               float64
+}
+
+// NewMACD creates a streaming moving average convergence divergence.
+func NewMACD(fast, slow, signal int) *MovingAverageConvergenceDivergence {
+	return &MovingAverageConvergenceDivergence{
+		fast:   NewEMA(fast),
+		slow:   NewEMA(slow),
+		signal: NewEMA(signal),
+	}
+}
+
+// Update adds a new value.
+func (i *MovingAverageConvergenceDivergence) Update(v float64) {
+	i.fast.Update(v)
+	i.slow.Update(v)
+
+	if i.fast.Ready() && i.slow.Ready() {
+		i.macd = i.fast.Value() - i.slow.Value()
+		i.signal.Update(i.macd)
+	}
+}
+
+// Value returns the current macd line.
+func (i *MovingAverageConvergenceDivergence) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.macd
+}
+
+// Signal returns the current signal line.
+func (i *MovingAverageConvergenceDivergence) Signal() float64 {
+	return i.signal.Value()
+}
+
+// Hist returns the current histogram.
+func (i *MovingAverageConvergenceDivergence) Hist() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.macd - i.signal.Value()
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *MovingAverageConvergenceDivergence) Ready() bool {
+	return i.signal.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *MovingAverageConvergenceDivergence) Reset() {
+	i.fast.Reset()
+	i.slow.Reset()
+	i.signal.Reset()
+	i.macd = 0
+}
+
+// StochasticOscillator is the streaming version of Stochastic.
+// Value returns %K, D returns %D.
+type StochasticOscillator struct {
+	kPeriod int
+	count   int
+	high    *extremum
+	low     *extremum
+	k       float64
+	d       *SimpleMovingAverage
+}
+
+// NewStochastic creates a streaming stochastic oscillator.
+func NewStochastic(kPeriod, dPeriod int) *StochasticOscillator {
+	return &StochasticOscillator{
+		kPeriod: kPeriod,
+		high:    newExtremum(kPeriod, true),
+		low:     newExtremum(kPeriod, false),
+		d:       NewSMA(dPeriod),
+	}
+}
+
+// Update adds a new bar.
+func (i *StochasticOscillator) Update(bar Bar) {
+	i.count++
+	i.high.push(bar.High)
+	i.low.push(bar.Low)
+
+	if i.count < i.kPeriod {
+		return
+	}
+
+	high, low := i.high.value(), i.low.value()
+	if high == low {
+		i.k = 50
+	} else {
+		i.k = (bar.Close - low) / (high - low) * 100
+	}
+	i.d.Update(i.k)
+}
+
+// Value returns the current %K.
+func (i *StochasticOscillator) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.k
+}
+
+// D returns the current %D.
+func (i *StochasticOscillator) D() float64 {
+	return i.d.Value()
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *StochasticOscillator) Ready() bool {
+	return i.d.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *StochasticOscillator) Reset() {
+	i.count = 0
+	i.high.reset()
+	i.low.reset()
+	i.k = 0
+	i.d.Reset()
+}
+
+// CommodityChannelIndex is the streaming version of CCI.
+// The mean deviation has to be recalculated over the window, so an update costs O(period).
+type CommodityChannelIndex struct {
+	period int
+	window *window
+	sum    float64
+	last   float64
+}
+
+// NewCCI creates a streaming commodity channel index for a given period.
+func NewCCI(period int) *CommodityChannelIndex {
+	return &CommodityChannelIndex{period: period, window: newWindow(period)}
+}
+
+// Update adds a new bar.
+func (i *CommodityChannelIndex) Update(bar Bar) {
+	tp := bar.typicalPrice()
+	old, dropped := i.window.push(tp)
+	i.sum += tp
+	if dropped {
+		i.sum -= old
+	}
+	i.last = tp
+}
+
+// Value returns the current cci.
+func (i *CommodityChannelIndex) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+
+	mean := i.sum / float64(i.period)
+	var meanDev float64
+	i.window.each(func(v float64) {
+		if v > mean {
+			meanDev += v - mean
+		} else {
+			meanDev += mean - v
+		}
+	})
+	meanDev /= float64(i.period)
+
+	if meanDev == 0 {
+		return 0
+	}
+	return (i.last - mean) / (0.015 * meanDev)
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *CommodityChannelIndex) Ready() bool {
+	return i.window.full()
+}
+
+// Reset the indicator into a clean state.
+func (i *CommodityChannelIndex) Reset() {
+	i.window.reset()
+	i.sum = 0
+	i.last = 0
+}
+
+// RateOfChange is the streaming version of ROC.
+type RateOfChange struct {
+	window *window
+	last   float64
+}
+
+// NewROC creates a streaming rate of change for a given period.
+func NewROC(period int) *RateOfChange {
+	return &RateOfChange{window: newWindow(period + 1)}
+}
+
+// Update adds a new value.
+func (i *RateOfChange) Update(v float64) {
+	i.window.push(v)
+	i.last = v
+}
+
+// Value returns the current rate of change in percent.
+func (i *RateOfChange) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+
+	old := i.window.oldest()
+	if old == 0 {
+		return 0
+	}
+	return (i.last - old) / old * 100
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *RateOfChange) Ready() bool {
+	return i.window.full()
+}
+
+// Reset the indicator into a clean state.
+func (i *RateOfChange) Reset() {
+	i.window.reset()
+	i.last = 0
+}
@@ -0,0 +1,303 @@
+package ta
+
+import (
+	"math"
+	"testing"
+)
+
+// testHelperWave returns a deterministic, not monotonic series of n values.
+func testHelperWave(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 100 + 10*math.Sin(float64(i)/3) + float64(i%7)
+	}
+	return values
+}
+
+// testHelperWaveBars returns a deterministic series of n bars around testHelperWave.
+func testHelperWaveBars(n int) []Bar {
+	bars := make([]Bar, n)
+	for i, v := range testHelperWave(n) {
+		bars[i] = Bar{
+			Open:   v - 0.5,
+			High:   v + 1 + float64(i%3),
+			Low:    v - 1 - float64(i%4),
+			Close:  v,
+			Volume: float64(1000 + 100*(i%5)),
+		}
+	}
+	return bars
+}
+
+// testHelperStream feeds all values into the indicator and collects the output once it is ready.
+func testHelperStream(values []float64, ind Indicator, output func() float64) []float64 {
+	var result []float64
+	for _, v := range values {
+		ind.Update(v)
+		if ind.Ready() {
+			result = append(result, output())
+		}
+	}
+	return result
+}
+
+// testHelperBarStream feeds all bars into the indicator and collects the output once it is ready.
+func testHelperBarStream(bars []Bar, ind BarIndicator, output func() float64) []float64 {
+	var result []float64
+	for _, bar := range bars {
+		ind.Update(bar)
+		if ind.Ready() {
+			result = append(result, output())
+		}
+	}
+	return result
+}
+
+func TestStreamMatchesBatch(t *testing.T) {
+	values := testHelperWave(60)
+	bars := testHelperWaveBars(60)
+
+	sma := NewSMA(5)
+	ema := NewEMA(5)
+	wma := NewWMA(5)
+	dema := NewDEMA(4)
+	tema := NewTEMA(4)
+	rsi := NewRSI(14)
+	macd := NewMACD(3, 6, 4)
+	roc := NewROC(5)
+	sd := NewStdDev(10)
+	z := NewZScore(10)
+	bb := NewBollinger(10, 2)
+	atr := NewATR(14)
+	adx := NewADX(7)
+	stoch := NewStochastic(5, 3)
+	cci := NewCCI(10)
+	obv := NewOBV()
+	vwap := NewVWAP(5)
+	donchian := NewDonchian(10)
+	keltner := NewKeltner(10, 2)
+
+	batch := func(result []float64, err error) []float64 {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+	macdLine, macdSignal, macdHist, _ := MACD(values, 3, 6, 4)
+	bbUpper, bbMiddle, bbLower, _ := BollingerBands(values, 10, 2)
+	adxLine, plusDI, minusDI, _ := ADX(bars, 7)
+	stochK, stochD, _ := Stochastic(bars, 5, 3)
+	dcUpper, dcLower, _ := Donchian(bars, 10)
+	kcUpper, kcMiddle, kcLower, _ := Keltner(bars, 10, 2)
+
+	// streams with several outputs are fed once and compared for each output
+	var macdS, macdSigS, macdHistS []float64
+	for _, v := range values {
+		macd.Update(v)
+		if macd.Ready() {
+			macdS = append(macdS, macd.Value())
+			macdSigS = append(macdSigS, macd.Signal())
+			macdHistS = append(macdHistS, macd.Hist())
+		}
+	}
+	var bbU, bbM, bbL []float64
+	for _, v := range values {
+		bb.Update(v)
+		if bb.Ready() {
+			bbU = append(bbU, bb.Upper())
+			bbM = append(bbM, bb.Middle())
+			bbL = append(bbL, bb.Lower())
+		}
+	}
+	var adxS, plusS, minusS, kS, dS, dcU, dcL, kcU, kcM, kcL []float64
+	for _, bar := range bars {
+		adx.Update(bar)
+		if adx.Ready() {
+			adxS = append(adxS, adx.Value())
+			plusS = append(plusS, adx.PlusDI())
+			minusS = append(minusS, adx.MinusDI())
+		}
+		stoch.Update(bar)
+		if stoch.Ready() {
+			kS = append(kS, stoch.Value())
+			dS = append(dS, stoch.D())
+		}
+		donchian.Update(bar)
+		if donchian.Ready() {
+			dcU = append(dcU, donchian.Upper())
+			dcL = append(dcL, donchian.Lower())
+		}
+		keltner.Update(bar)
+		if keltner.Ready() {
+			kcU = append(kcU, keltner.Upper())
+			kcM = append(kcM, keltner.Middle())
+			kcL = append(kcL, keltner.Lower())
+		}
+	}
+
+	var testCases = []struct {
+		msg      string
+		stream   []float64
+		expected []float64
+	}{
+		{"testing sma",
+			testHelperStream(values, sma, sma.Value),
+			batch(SMA(values, 5)),
+		},
+		{"testing ema",
+			testHelperStream(values, ema, ema.Value),
+			batch(EMA(values, 5)),
+		},
+		{"testing wma",
+			testHelperStream(values, wma, wma.Value),
+			batch(WMA(values, 5)),
+		},
+		{"testing dema",
+			testHelperStream(values, dema, dema.Value),
+			batch(DEMA(values, 4)),
+		},
+		{"testing tema",
+			testHelperStream(values, tema, tema.Value),
+			batch(TEMA(values, 4)),
+		},
+		{"testing rsi",
+			testHelperStream(values, rsi, rsi.Value),
+			batch(RSI(values, 14)),
+		},
+		{"testing roc",
+			testHelperStream(values, roc, roc.Value),
+			batch(ROC(values, 5)),
+		},
+		{"testing stddev",
+			testHelperStream(values, sd, sd.Value),
+			batch(StdDev(values, 10)),
+		},
+		{"testing zscore",
+			testHelperStream(values, z, z.Value),
+			batch(ZScore(values, 10)),
+		},
+		{"testing macd line", macdS, macdLine},
+		{"testing macd signal", macdSigS, macdSignal},
+		{"testing macd hist", macdHistS, macdHist},
+		{"testing bollinger upper", bbU, bbUpper},
+		{"testing bollinger middle", bbM, bbMiddle},
+		{"testing bollinger lower", bbL, bbLower},
+		{"testing atr",
+			testHelperBarStream(bars, atr, atr.Value),
+			batch(ATR(bars, 14)),
+		},
+		{"testing adx", adxS, adxLine},
+		{"testing plus di", plusS, plusDI},
+		{"testing minus di", minusS, minusDI},
+		{"testing stochastic k", kS, stochK},
+		{"testing stochastic d", dS, stochD},
+		{"testing cci",
+			testHelperBarStream(bars, cci, cci.Value),
+			batch(CCI(bars, 10)),
+		},
+		{"testing obv",
+			testHelperBarStream(bars, obv, obv.Value),
+			batch(OBV(bars)),
+		},
+		{"testing vwap",
+			testHelperBarStream(bars, vwap, vwap.Value),
+			batch(VWAP(bars, 5)),
+		},
+		{"testing donchian upper", dcU, dcUpper},
+		{"testing donchian lower", dcL, dcLower},
+		{"testing keltner upper", kcU, kcUpper},
+		{"testing keltner middle", kcM, kcMiddle},
+		{"testing keltner lower", kcL, kcLower},
+	}
+
+	for _, tc := range testCases {
+		if !testHelperEqual(tc.stream, tc.expected) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expected, tc.stream)
+		}
+	}
+}
+
+func TestStreamStdDevLongSeries(t *testing.T) {
+	// a high price level with a volatile first half and a calm second half
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = 1e6 + 1e4*math.Sin(float64(i)/3)
+		if i >= len(values)/2 {
+			values[i] = 1e6 + 1e-3*float64(i%5)
+		}
+	}
+
+	sd := NewStdDev(20)
+	z := NewZScore(20)
+	batch := func(result []float64, err error) []float64 {
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	var testCases = []struct {
+		msg      string
+		stream   []float64
+		expected []float64
+	}{
+		{"testing stddev",
+			testHelperStream(values, sd, sd.Value),
+			batch(StdDev(values, 20)),
+		},
+		{"testing zscore",
+			testHelperStream(values, z, z.Value),
+			batch(ZScore(values, 20)),
+		},
+	}
+
+	for _, tc := range testCases {
+		if !testHelperEqual(tc.stream, tc.expected) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expected[len(tc.expected)-5:], tc.stream[len(tc.stream)-5:])
+		}
+	}
+}
+
+func TestStreamReset(t *testing.T) {
+	var testCases = []struct {
+		msg    string
+		ind    Indicator
+		values []float64
+	}{
+		{"testing sma reset", NewSMA(3), testHelperLinear(5)},
+		{"testing ema reset", NewEMA(3), testHelperLinear(5)},
+		{"testing wma reset", NewWMA(3), testHelperLinear(5)},
+		{"testing rsi reset", NewRSI(3), testHelperWave(10)},
+		{"testing macd reset", NewMACD(2, 3, 2), testHelperWave(10)},
+	}
+
+	for _, tc := range testCases {
+		first := testHelperStream(tc.values, tc.ind, tc.ind.Value)
+		tc.ind.Reset()
+		if tc.ind.Ready() {
+			t.Errorf("%v: expected not ready after reset", tc.msg)
+		}
+		second := testHelperStream(tc.values, tc.ind, tc.ind.Value)
+		if !testHelperEqual(first, second) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, first, second)
+		}
+	}
+}
+
+func TestStreamNotReady(t *testing.T) {
+	sma := NewSMA(3)
+	sma.Update(1)
+	sma.Update(2)
+
+	if sma.Ready() {
+		t.Errorf("testing sma not ready: expected %v, actual %v", false, sma.Ready())
+	}
+	if sma.Value() != 0 {
+		t.Errorf("testing sma not ready: expected %v, actual %v", 0, sma.Value())
+	}
+
+	sma.Update(3)
+	if !sma.Ready() || sma.Value() != 2 {
+		t.Errorf("testing sma ready: expected %v, actual %v", 2, sma.Value())
+	}
+}
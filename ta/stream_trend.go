@@ -0,0 +1,117 @@
+package ta
+
+import (
+	"math"
+)
+
+// AverageDirectionalIndex is the streaming version of ADX.
+// Value returns the adx, PlusDI and MinusDI the directional indicators.
+type AverageDirectionalIndex struct {
+	period int
+	count  int // number of bars seen
+	prev   Bar
+
+	sPlus, sMinus, sTR float64 // Wilder's smoothed sums
+	plus, minus        float64 // current directional indicators
+
+	dxCount int
+	adx     float64
+}
+
+// NewADX creates a streaming average directional index for a given period.
+func NewADX(period int) *AverageDirectionalIndex {
+	return &AverageDirectionalIndex{period: period}
+}
+
+// Update adds a new bar.
+func (i *AverageDirectionalIndex) Update(bar Bar) {
+	i.count++
+	prev := i.prev
+	i.prev = bar
+	if i.count == 1 {
+		return
+	}
+
+	// directional movement and true range
+	var plusDM, minusDM float64
+	up := bar.High - prev.High
+	down := prev.Low - bar.Low
+	if (up > down) && (up > 0) {
+		plusDM = up
+	}
+	if (down > up) && (down > 0) {
+		minusDM = down
+	}
+	tr := bar.trueRange(prev.Close)
+
+	// sum up the first period, then use Wilder's smoothing
+	moves := i.count - 1
+	if moves <= i.period {
+		i.sPlus += plusDM
+		i.sMinus += minusDM
+		i.sTR += tr
+		if moves < i.period {
+			return
+		}
+	} else {
+		i.sPlus = i.sPlus - i.sPlus/float64(i.period) + plusDM
+		i.sMinus = i.sMinus - i.sMinus/float64(i.period) + minusDM
+		i.sTR = i.sTR - i.sTR/float64(i.period) + tr
+	}
+
+	i.plus, i.minus = 0, 0
+	if i.sTR != 0 {
+		i.plus = 100 * i.sPlus / i.sTR
+		i.minus = 100 * i.sMinus / i.sTR
+	}
+
+	var dx float64
+	if i.plus+i.minus != 0 {
+		dx = 100 * math.Abs(i.plus-i.minus) / (i.plus + i.minus)
+	}
+
+	// adx is the Wilder's smoothed average of dx
+	i.dxCount++
+	switch {
+	case i.dxCount < i.period:
+		i.adx += dx
+	case i.dxCount == i.period:
+		i.adx = (i.adx + dx) / float64(i.period)
+	default:
+		i.adx = (i.adx*float64(i.period-1) + dx) / float64(i.period)
+	}
+}
+
+// Value returns the current adx.
+func (i *AverageDirectionalIndex) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.adx
+}
+
+// PlusDI returns the current positive directional indicator.
+func (i *AverageDirectionalIndex) PlusDI() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.plus
+}
+
+// MinusDI returns the current negative directional indicator.
+func (i *AverageDirectionalIndex) MinusDI() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.minus
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *AverageDirectionalIndex) Ready() bool {
+	return i.dxCount >= i.period
+}
+
+// Reset the indicator into a clean state.
+func (i *AverageDirectionalIndex) Reset() {
+	*i = AverageDirectionalIndex{period: i.period}
+}
@@ -0,0 +1,371 @@
+package ta
+
+import (
+	"math"
+)
+
+// RollingStdDev is the streaming version of StdDev.
+// It keeps compensated running sums of the deviations of the window values from an anchor.
+// The anchor is moved to the mean of the window once per period and the sums are recalculated,
+// so the deviations stay small and the rounding errors of a long series do not add up.
+type RollingStdDev struct {
+	period  int
+	window  *window
+	anchor  float64
+	sum     kahan // sum of the deviations from the anchor
+	sumSq   kahan // sum of the squared deviations from the anchor
+	updates int   // updates since the last re-anchoring
+}
+
+// NewStdDev creates a streaming population standard deviation for a given period.
+func NewStdDev(period int) *RollingStdDev {
+	return &RollingStdDev{period: period, window: newWindow(period)}
+}
+
+// Update adds a new value.
+func (i *RollingStdDev) Update(v float64) {
+	if i.window.count == 0 {
+		i.anchor = v
+	}
+
+	old, dropped := i.window.push(v)
+
+	d := v - i.anchor
+	i.sum.add(d)
+	i.sumSq.add(d * d)
+
+	if !dropped {
+		return
+	}
+
+	d = old - i.anchor
+	i.sum.add(-d)
+	i.sumSq.add(-d * d)
+
+	i.updates++
+	if i.updates >= i.window.count {
+		i.reanchor()
+	}
+}
+
+// Value returns the current standard deviation.
+func (i *RollingStdDev) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+
+	n := float64(i.window.count)
+	sum := i.sum.value()
+	variance := (i.sumSq.value() - sum*sum/n) / float64(i.period)
+	return math.Sqrt(math.Max(variance, 0))
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *RollingStdDev) Ready() bool {
+	return i.window.full()
+}
+
+// Reset the indicator into a clean state.
+func (i *RollingStdDev) Reset() {
+	i.window.reset()
+	i.anchor = 0
+	i.sum = kahan{}
+	i.sumSq = kahan{}
+	i.updates = 0
+}
+
+// mean returns the mean of the values in the window.
+func (i *RollingStdDev) mean() float64 {
+	return i.anchor + i.deviation()
+}
+
+// deviation returns the distance of the mean of the window from the anchor.
+func (i *RollingStdDev) deviation() float64 {
+	if i.window.count == 0 {
+		return 0
+	}
+	return i.sum.value() / float64(i.window.count)
+}
+
+// reanchor moves the anchor to the mean of the window and recalculates the sums from the values of the window.
+func (i *RollingStdDev) reanchor() {
+	i.anchor = i.mean()
+	i.sum = kahan{}
+	i.sumSq = kahan{}
+	i.window.each(func(v float64) {
+		d := v - i.anchor
+		i.sum.add(d)
+		i.sumSq.add(d * d)
+	})
+	i.updates = 0
+}
+
+// kahan is a running sum with Kahan compensation of the rounding error.
+type kahan struct {
+	sum float64
+	c   float64
+}
+
+// add adds a value to the sum.
+func (k *kahan) add(v float64) {
+	y := v - k.c
+	t := k.sum + y
+	k.c = (t - k.sum) - y
+	k.sum = t
+}
+
+// value returns the compensated sum.
+func (k *kahan) value() float64 {
+	return k.sum
+}
+
+// RollingZScore is the streaming version of ZScore.
+type RollingZScore struct {
+	sd   *RollingStdDev
+	last float64
+}
+
+// NewZScore creates a streaming z-score for a given period.
+func NewZScore(period int) *RollingZScore {
+	return &RollingZScore{sd: NewStdDev(period)}
+}
+
+// Update adds a new value.
+func (i *RollingZScore) Update(v float64) {
+	i.sd.Update(v)
+	i.last = v
+}
+
+// Value returns the current z-score.
+func (i *RollingZScore) Value() float64 {
+	sd := i.sd.Value()
+	if !i.Ready() || sd == 0 {
+		return 0
+	}
+	return ((i.last - i.sd.anchor) - i.sd.deviation()) / sd
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *RollingZScore) Ready() bool {
+	return i.sd.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *RollingZScore) Reset() {
+	i.sd.Reset()
+	i.last = 0
+}
+
+// Bollinger is the streaming version of BollingerBands.
+// Value returns the middle band.
+type Bollinger struct {
+	k  float64
+	sd *RollingStdDev
+}
+
+// NewBollinger creates streaming bollinger bands, k standard deviations around the moving average of period.
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{k: k, sd: NewStdDev(period)}
+}
+
+// Update adds a new value.
+func (i *Bollinger) Update(v float64) {
+	i.sd.Update(v)
+}
+
+// Value returns the current middle band.
+func (i *Bollinger) Value() float64 {
+	return i.Middle()
+}
+
+// Upper returns the current upper band.
+func (i *Bollinger) Upper() float64 {
+	return i.Middle() + i.k*i.sd.Value()
+}
+
+// Middle returns the current middle band.
+func (i *Bollinger) Middle() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.sd.mean()
+}
+
+// Lower returns the current lower band.
+func (i *Bollinger) Lower() float64 {
+	return i.Middle() - i.k*i.sd.Value()
+}
+
+// Ready checks if enough values are seen for a valid value.
+func (i *Bollinger) Ready() bool {
+	return i.sd.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *Bollinger) Reset() {
+	i.sd.Reset()
+}
+
+// AverageTrueRange is the streaming version of ATR.
+type AverageTrueRange struct {
+	period    int
+	count     int
+	prevClose float64
+	atr       float64
+}
+
+// NewATR creates a streaming average true range for a given period.
+func NewATR(period int) *AverageTrueRange {
+	return &AverageTrueRange{period: period}
+}
+
+// Update adds a new bar.
+func (i *AverageTrueRange) Update(bar Bar) {
+	tr := bar.High - bar.Low
+	if i.count > 0 {
+		tr = bar.trueRange(i.prevClose)
+	}
+	i.prevClose = bar.Close
+	i.count++
+
+	// sum up the first period, then use Wilder's smoothing
+	switch {
+	case i.count < i.period:
+		i.atr += tr
+	case i.count == i.period:
+		i.atr = (i.atr + tr) / float64(i.period)
+	default:
+		i.atr = (i.atr*float64(i.period-1) + tr) / float64(i.period)
+	}
+}
+
+// Value returns the current average true range.
+func (i *AverageTrueRange) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.atr
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *AverageTrueRange) Ready() bool {
+	return i.count >= i.period
+}
+
+// Reset the indicator into a clean state.
+func (i *AverageTrueRange) Reset() {
+	i.count = 0
+	i.prevClose = 0
+	i.atr = 0
+}
+
+// DonchianChannel is the streaming version of Donchian.
+// Value returns the middle of the channel.
+type DonchianChannel struct {
+	period int
+	count  int
+	high   *extremum
+	low    *extremum
+}
+
+// NewDonchian creates a streaming donchian channel for a given period.
+func NewDonchian(period int) *DonchianChannel {
+	return &DonchianChannel{
+		period: period,
+		high:   newExtremum(period, true),
+		low:    newExtremum(period, false),
+	}
+}
+
+// Update adds a new bar.
+func (i *DonchianChannel) Update(bar Bar) {
+	i.count++
+	i.high.push(bar.High)
+	i.low.push(bar.Low)
+}
+
+// Value returns the current middle of the channel.
+func (i *DonchianChannel) Value() float64 {
+	return (i.Upper() + i.Lower()) / 2
+}
+
+// Upper returns the current upper band.
+func (i *DonchianChannel) Upper() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.high.value()
+}
+
+// Lower returns the current lower band.
+func (i *DonchianChannel) Lower() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.low.value()
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *DonchianChannel) Ready() bool {
+	return i.count >= i.period
+}
+
+// Reset the indicator into a clean state.
+func (i *DonchianChannel) Reset() {
+	i.count = 0
+	i.high.reset()
+	i.low.reset()
+}
+
+// KeltnerChannel is the streaming version of Keltner.
+// Value returns the middle band.
+type KeltnerChannel struct {
+	k   float64
+	ema *ExponentialMovingAverage
+	atr *AverageTrueRange
+}
+
+// NewKeltner creates a streaming keltner channel, k average true ranges around the moving average of period.
+func NewKeltner(period int, k float64) *KeltnerChannel {
+	return &KeltnerChannel{k: k, ema: NewEMA(period), atr: NewATR(period)}
+}
+
+// Update adds a new bar.
+func (i *KeltnerChannel) Update(bar Bar) {
+	i.ema.Update(bar.Close)
+	i.atr.Update(bar)
+}
+
+// Value returns the current middle band.
+func (i *KeltnerChannel) Value() float64 {
+	return i.Middle()
+}
+
+// Upper returns the current upper band.
+func (i *KeltnerChannel) Upper() float64 {
+	return i.Middle() + i.k*i.atr.Value()
+}
+
+// Middle returns the current middle band.
+func (i *KeltnerChannel) Middle() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	return i.ema.Value()
+}
+
+// Lower returns the current lower band.
+func (i *KeltnerChannel) Lower() float64 {
+	return i.Middle() - i.k*i.atr.Value()
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *KeltnerChannel) Ready() bool {
+	return i.ema.Ready() && i.atr.Ready()
+}
+
+// Reset the indicator into a clean state.
+func (i *KeltnerChannel) Reset() {
+	i.ema.Reset()
+	i.atr.Reset()
+}
@@ -0,0 +1,99 @@
+package ta
+
+// OnBalanceVolume is the streaming version of OBV.
+type OnBalanceVolume struct {
+	count     int
+	prevClose float64
+	obv       float64
+}
+
+// NewOBV creates a streaming on balance volume.
+func NewOBV() *OnBalanceVolume {
+	return &OnBalanceVolume{}
+}
+
+// Update adds a new bar.
+func (i *OnBalanceVolume) Update(bar Bar) {
+	if i.count > 0 {
+		switch {
+		case bar.Close > i.prevClose:
+			i.obv += bar.Volume
+		case bar.Close < i.prevClose:
+			i.obv -= bar.Volume
+		}
+	}
+	i.prevClose = bar.Close
+	i.count++
+}
+
+// Value returns the current on balance volume.
+func (i *OnBalanceVolume) Value() float64 {
+	return i.obv
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *OnBalanceVolume) Ready() bool {
+	return i.count > 0
+}
+
+// Reset the indicator into a clean state.
+func (i *OnBalanceVolume) Reset() {
+	i.count = 0
+	i.prevClose = 0
+	i.obv = 0
+}
+
+// VolumeWeightedAveragePrice is the streaming version of VWAP.
+type VolumeWeightedAveragePrice struct {
+	values  *window
+	volumes *window
+	value   float64
+	volume  float64
+	last    float64
+}
+
+// NewVWAP creates a streaming rolling volume weighted average price for a given period.
+func NewVWAP(period int) *VolumeWeightedAveragePrice {
+	return &VolumeWeightedAveragePrice{values: newWindow(period), volumes: newWindow(period)}
+}
+
+// Update adds a new bar.
+func (i *VolumeWeightedAveragePrice) Update(bar Bar) {
+	tp := bar.typicalPrice()
+	value := tp * bar.Volume
+
+	if old, dropped := i.values.push(value); dropped {
+		i.value -= old
+	}
+	if old, dropped := i.volumes.push(bar.Volume); dropped {
+		i.volume -= old
+	}
+	i.value += value
+	i.volume += bar.Volume
+	i.last = tp
+}
+
+// Value returns the current volume weighted average price.
+func (i *VolumeWeightedAveragePrice) Value() float64 {
+	if !i.Ready() {
+		return 0
+	}
+	if i.volume == 0 {
+		return i.last
+	}
+	return i.value / i.volume
+}
+
+// Ready checks if enough bars are seen for a valid value.
+func (i *VolumeWeightedAveragePrice) Ready() bool {
+	return i.values.full()
+}
+
+// Reset the indicator into a clean state.
+func (i *VolumeWeightedAveragePrice) Reset() {
+	i.values.reset()
+	i.volumes.reset()
+	i.value = 0
+	i.volume = 0
+	i.last = 0
+}
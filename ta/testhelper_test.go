@@ -0,0 +1,37 @@
+package ta
+
+import (
+	"math"
+)
+
+// testHelperEqual compares two slices of float64 values with a small tolerance.
+func testHelperEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
+
+// testHelperLinear returns the values 1 to n.
+func testHelperLinear(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	return values
+}
+
+// testHelperBars returns a set of bars with known true ranges.
+func testHelperBars() []Bar {
+	return []Bar{
+		{High: 10, Low: 8, Close: 9},
+		{High: 11, Low: 9, Close: 10},
+		{High: 12, Low: 10, Close: 11},
+		{High: 11, Low: 7, Close: 8},
+	}
+}
@@ -0,0 +1,72 @@
+package ta
+
+import (
+	"math"
+)
+
+// ADX calculates the Average Directional Index with Wilder's smoothing for the supplied slice of bars.
+// It returns the adx, the positive and the negative directional indicator (+DI, -DI),
+// all aligned to the length of the adx. The first value is available after 2 * period bars.
+func ADX(bars []Bar, period int) (adx, plusDI, minusDI []float64, err error) {
+	if err := checkValues(len(bars), 2*period); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// directional movement and true range, starting with the second bar
+	n := len(bars) - 1
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	tr := make([]float64, n)
+	for i := 1; i < len(bars); i++ {
+		up := bars[i].High - bars[i-1].High
+		down := bars[i-1].Low - bars[i].Low
+		if (up > down) && (up > 0) {
+			plusDM[i-1] = up
+		}
+		if (down > up) && (down > 0) {
+			minusDM[i-1] = down
+		}
+		tr[i-1] = bars[i].trueRange(bars[i-1].Close)
+	}
+
+	// Wilder's smoothed sums, starting with the sum of the first period
+	var sPlus, sMinus, sTR float64
+	for i := 0; i < period; i++ {
+		sPlus += plusDM[i]
+		sMinus += minusDM[i]
+		sTR += tr[i]
+	}
+
+	var dx, plus, minus []float64
+	for i := period - 1; i < n; i++ {
+		if i >= period {
+			sPlus = sPlus - sPlus/float64(period) + plusDM[i]
+			sMinus = sMinus - sMinus/float64(period) + minusDM[i]
+			sTR = sTR - sTR/float64(period) + tr[i]
+		}
+
+		var p, m float64
+		if sTR != 0 {
+			p = 100 * sPlus / sTR
+			m = 100 * sMinus / sTR
+		}
+		plus = append(plus, p)
+		minus = append(minus, m)
+
+		if p+m == 0 {
+			dx = append(dx, 0)
+			continue
+		}
+		dx = append(dx, 100*math.Abs(p-m)/(p+m))
+	}
+
+	// adx is the Wilder's smoothed average of dx
+	value := Mean(dx[:period])
+	adx = append(adx, value)
+	for i := period; i < len(dx); i++ {
+		value = (value*float64(period-1) + dx[i]) / float64(period)
+		adx = append(adx, value)
+	}
+
+	return adx, tail(plus, len(adx)), tail(minus, len(adx)), nil
+}
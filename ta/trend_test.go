@@ -0,0 +1,28 @@
+package ta
+
+import (
+	"testing"
+)
+
+func TestADX(t *testing.T) {
+	// a steady uptrend has only positive directional movement
+	var bars []Bar
+	for i := 0; i < 10; i++ {
+		bars = append(bars, Bar{High: float64(i + 1), Low: float64(i), Close: float64(i) + 0.5})
+	}
+
+	adx, plusDI, minusDI, err := ADX(bars, 3)
+	expADX := []float64{100, 100, 100, 100, 100}
+	expPlus := []float64{200.0 / 3, 200.0 / 3, 200.0 / 3, 200.0 / 3, 200.0 / 3}
+	expMinus := []float64{0, 0, 0, 0, 0}
+	if !testHelperEqual(adx, expADX) || !testHelperEqual(plusDI, expPlus) ||
+		!testHelperEqual(minusDI, expMinus) || (err != nil) {
+		t.Errorf("ADX(): \nexpected %v %v %v %v, \nactual   %v %v %v %v",
+			expADX, expPlus, expMinus, nil, adx, plusDI, minusDI, err)
+	}
+
+	_, _, _, err = ADX(bars[:5], 3)
+	if err == nil {
+		t.Errorf("ADX() too few bars: expected error, actual %v", err)
+	}
+}
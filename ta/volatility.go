@@ -0,0 +1,172 @@
+package ta
+
+import (
+	"math"
+)
+
+// StdDev calculates the rolling population standard deviation
+// for the supplied slice of float64 values for a given period.
+func StdDev(values []float64, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(values), period); err != nil {
+		return result, err
+	}
+
+	for i := period - 1; i < len(values); i++ {
+		result = append(result, stdDev(values[i-period+1:i+1]))
+	}
+
+	return result, nil
+}
+
+// ZScore calculates the rolling z-score, the distance of the value to the mean in standard deviations,
+// for the supplied slice of float64 values for a given period.
+func ZScore(values []float64, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(values), period); err != nil {
+		return result, err
+	}
+
+	for i := period - 1; i < len(values); i++ {
+		window := values[i-period+1 : i+1]
+		sd := stdDev(window)
+		if sd == 0 {
+			result = append(result, 0)
+			continue
+		}
+		// measure the distance from the first value of the window,
+		// the mean of a high level of values would round away a small distance
+		var sum float64
+		for _, v := range window {
+			sum += v - window[0]
+		}
+		result = append(result, ((values[i]-window[0])-sum/float64(period))/sd)
+	}
+
+	return result, nil
+}
+
+// BollingerBands calculates the Bollinger Bands for the supplied slice of float64 values.
+// The middle band is the simple moving average over period, the upper and lower band
+// are k standard deviations above and below the middle band.
+func BollingerBands(values []float64, period int, k float64) (upper, middle, lower []float64, err error) {
+	middle, err = SMA(values, period)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	sd, err := StdDev(values, period)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	upper = make([]float64, len(middle))
+	lower = make([]float64, len(middle))
+	for i := range middle {
+		upper[i] = middle[i] + k*sd[i]
+		lower[i] = middle[i] - k*sd[i]
+	}
+
+	return upper, middle, lower, nil
+}
+
+// ATR calculates the Average True Range with Wilder's smoothing
+// for the supplied slice of bars for a given period.
+// The true range of the first bar is its high - low range.
+func ATR(bars []Bar, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(bars), period); err != nil {
+		return result, err
+	}
+
+	tr := trueRanges(bars)
+
+	atr := Mean(tr[:period])
+	result = append(result, atr)
+
+	for i := period; i < len(tr); i++ {
+		atr = (atr*float64(period-1) + tr[i]) / float64(period)
+		result = append(result, atr)
+	}
+
+	return result, nil
+}
+
+// Donchian calculates the Donchian Channel for the supplied slice of bars for a given period.
+// The upper band is the highest high, the lower band the lowest low of the period.
+func Donchian(bars []Bar, period int) (upper, lower []float64, err error) {
+	if err := checkValues(len(bars), period); err != nil {
+		return nil, nil, err
+	}
+
+	for i := period - 1; i < len(bars); i++ {
+		high, low := highLow(bars[i-period+1 : i+1])
+		upper = append(upper, high)
+		lower = append(lower, low)
+	}
+
+	return upper, lower, nil
+}
+
+// Keltner calculates the Keltner Channel for the supplied slice of bars.
+// The middle band is the exponential moving average of the close over period,
+// the upper and lower band are k average true ranges above and below the middle band.
+func Keltner(bars []Bar, period int, k float64) (upper, middle, lower []float64, err error) {
+	middle, err = EMA(closes(bars), period)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	atr, err := ATR(bars, period)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	upper = make([]float64, len(middle))
+	lower = make([]float64, len(middle))
+	for i := range middle {
+		upper[i] = middle[i] + k*atr[i]
+		lower[i] = middle[i] - k*atr[i]
+	}
+
+	return upper, middle, lower, nil
+}
+
+// stdDev calculates the population standard deviation of a slice of float64 values.
+func stdDev(values []float64) float64 {
+	mean := Mean(values)
+
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+
+	return math.Sqrt(sum / float64(len(values)))
+}
+
+// trueRanges returns the true range of each bar, the first bar uses its high - low range.
+func trueRanges(bars []Bar) []float64 {
+	tr := make([]float64, len(bars))
+	for i, bar := range bars {
+		if i == 0 {
+			tr[i] = bar.High - bar.Low
+			continue
+		}
+		tr[i] = bar.trueRange(bars[i-1].Close)
+	}
+	return tr
+}
+
+// highLow returns the highest high and the lowest low of a slice of bars.
+func highLow(bars []Bar) (high, low float64) {
+	high = bars[0].High
+	low = bars[0].Low
+	for _, bar := range bars[1:] {
+		high = math.Max(high, bar.High)
+		low = math.Min(low, bar.Low)
+	}
+	return high, low
+}
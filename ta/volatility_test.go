@@ -0,0 +1,66 @@
+package ta
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStdDev(t *testing.T) {
+	sd, err := StdDev([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 8)
+	exp := []float64{2}
+	if !testHelperEqual(sd, exp) || (err != nil) {
+		t.Errorf("StdDev(): \nexpected %v %v, \nactual   %v %v", exp, nil, sd, err)
+	}
+}
+
+func TestZScore(t *testing.T) {
+	z, err := ZScore([]float64{1, 2, 3, 3, 3}, 3)
+	// windows 1,2,3 and 2,3,3 and 3,3,3
+	exp := []float64{1 / math.Sqrt(2.0/3), (3 - 8.0/3) / math.Sqrt(2.0/9), 0}
+	if !testHelperEqual(z, exp) || (err != nil) {
+		t.Errorf("ZScore(): \nexpected %v %v, \nactual   %v %v", exp, nil, z, err)
+	}
+}
+
+func TestBollingerBands(t *testing.T) {
+	upper, middle, lower, err := BollingerBands([]float64{2, 4, 4, 4, 5, 5, 7, 9}, 8, 2)
+	if !testHelperEqual(upper, []float64{9}) || !testHelperEqual(middle, []float64{5}) ||
+		!testHelperEqual(lower, []float64{1}) || (err != nil) {
+		t.Errorf("BollingerBands(): \nexpected %v %v %v %v, \nactual   %v %v %v %v",
+			9, 5, 1, nil, upper, middle, lower, err)
+	}
+}
+
+func TestATR(t *testing.T) {
+	atr, err := ATR(testHelperBars(), 2)
+	exp := []float64{2, 2, 3}
+	if !testHelperEqual(atr, exp) || (err != nil) {
+		t.Errorf("ATR(): \nexpected %v %v, \nactual   %v %v", exp, nil, atr, err)
+	}
+
+	_, err = ATR(testHelperBars(), 5)
+	if err == nil {
+		t.Errorf("ATR() too few bars: expected error, actual %v", err)
+	}
+}
+
+func TestDonchian(t *testing.T) {
+	upper, lower, err := Donchian(testHelperBars(), 2)
+	expUpper := []float64{11, 12, 12}
+	expLower := []float64{8, 9, 7}
+	if !testHelperEqual(upper, expUpper) || !testHelperEqual(lower, expLower) || (err != nil) {
+		t.Errorf("Donchian(): \nexpected %v %v %v, \nactual   %v %v %v", expUpper, expLower, nil, upper, lower, err)
+	}
+}
+
+func TestKeltner(t *testing.T) {
+	upper, middle, lower, err := Keltner(testHelperBars(), 2, 1)
+	expMiddle := []float64{9.5, 10.5, 8.5 + 1.0/3}
+	expUpper := []float64{11.5, 12.5, 11.5 + 1.0/3}
+	expLower := []float64{7.5, 8.5, 5.5 + 1.0/3}
+	if !testHelperEqual(upper, expUpper) || !testHelperEqual(middle, expMiddle) ||
+		!testHelperEqual(lower, expLower) || (err != nil) {
+		t.Errorf("Keltner(): \nexpected %v %v %v %v, \nactual   %v %v %v %v",
+			expUpper, expMiddle, expLower, nil, upper, middle, lower, err)
+	}
+}
@@ -0,0 +1,52 @@
+package ta
+
+// OBV calculates the On Balance Volume for the supplied slice of bars.
+// The volume is added on a higher close and subtracted on a lower close, starting with 0.
+func OBV(bars []Bar) ([]float64, error) {
+	var result []float64
+
+	if len(bars) == 0 {
+		return result, checkValues(0, 1)
+	}
+
+	var obv float64
+	result = append(result, obv)
+
+	for i := 1; i < len(bars); i++ {
+		switch {
+		case bars[i].Close > bars[i-1].Close:
+			obv += bars[i].Volume
+		case bars[i].Close < bars[i-1].Close:
+			obv -= bars[i].Volume
+		}
+		result = append(result, obv)
+	}
+
+	return result, nil
+}
+
+// VWAP calculates the rolling Volume Weighted Average Price of the typical price
+// for the supplied slice of bars for a given period.
+func VWAP(bars []Bar, period int) ([]float64, error) {
+	var result []float64
+
+	if err := checkValues(len(bars), period); err != nil {
+		return result, err
+	}
+
+	for i := period - 1; i < len(bars); i++ {
+		var value, volume float64
+		for _, bar := range bars[i-period+1 : i+1] {
+			value += bar.typicalPrice() * bar.Volume
+			volume += bar.Volume
+		}
+
+		if volume == 0 {
+			result = append(result, bars[i].typicalPrice())
+			continue
+		}
+		result = append(result, value/volume)
+	}
+
+	return result, nil
+}
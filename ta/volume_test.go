@@ -0,0 +1,38 @@
+package ta
+
+import (
+	"testing"
+)
+
+func TestOBV(t *testing.T) {
+	bars := []Bar{
+		{Close: 10, Volume: 100},
+		{Close: 11, Volume: 200},
+		{Close: 10, Volume: 300},
+		{Close: 10, Volume: 400},
+	}
+
+	obv, err := OBV(bars)
+	exp := []float64{0, 200, -100, -100}
+	if !testHelperEqual(obv, exp) || (err != nil) {
+		t.Errorf("OBV(): \nexpected %v %v, \nactual   %v %v", exp, nil, obv, err)
+	}
+
+	_, err = OBV([]Bar{})
+	if err == nil {
+		t.Errorf("OBV() no bars: expected error, actual %v", err)
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	bars := []Bar{
+		{High: 10, Low: 10, Close: 10, Volume: 1},
+		{High: 20, Low: 20, Close: 20, Volume: 3},
+	}
+
+	vwap, err := VWAP(bars, 2)
+	exp := []float64{17.5}
+	if !testHelperEqual(vwap, exp) || (err != nil) {
+		t.Errorf("VWAP(): \nexpected %v %v, \nactual   %v %v", exp, nil, vwap, err)
+	}
+}
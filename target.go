@@ -0,0 +1,71 @@
+package gobacktest
+
+// TargetEvent declares a target signal, which sets the target of a position
+// instead of a trade direction.
+type TargetEvent interface {
+	SignalEvent
+	Targeter
+}
+
+// Targeter defines the target of a position, either as weight of the portfolio value or as qty.
+type Targeter interface {
+	TargetWeight() (float64, bool)
+	TargetQty() (int64, bool)
+}
+
+// Target declares a target signal for a symbol.
+type Target struct {
+	Event
+	direction Direction
+	weight    float64 // target weight of the portfolio value
+	qty       int64   // target qty of the position
+	byQty     bool    // target is given as qty
+}
+
+// NewTargetWeight creates a target signal with a target weight of the portfolio value.
+// A negative weight targets a short position.
+func NewTargetWeight(event EventHandler, weight float64) *Target {
+	return &Target{
+		Event:     Event{timestamp: event.Time(), symbol: event.Symbol()},
+		direction: HLD,
+		weight:    weight,
+	}
+}
+
+// NewTargetQty creates a target signal with a target qty of the position.
+// A negative qty targets a short position.
+func NewTargetQty(event EventHandler, qty int64) *Target {
+	return &Target{
+		Event:     Event{timestamp: event.Time(), symbol: event.Symbol()},
+		direction: HLD,
+		qty:       qty,
+		byQty:     true,
+	}
+}
+
+// Direction returns the Direction of a Target, which is HLD as the direction
+// results from the difference to the current position.
+func (t Target) Direction() Direction {
+	return t.direction
+}
+
+// SetDirection sets the Directions field of a Target
+func (t *Target) SetDirection(dir Direction) {
+	t.direction = dir
+}
+
+// TargetWeight returns the target weight, returns false if the target is given as qty.
+func (t Target) TargetWeight() (float64, bool) {
+	if t.byQty {
+		return 0, false
+	}
+	return t.weight, true
+}
+
+// TargetQty returns the target qty, returns false if the target is given as weight.
+func (t Target) TargetQty() (int64, bool) {
+	if !t.byQty {
+		return 0, false
+	}
+	return t.qty, true
+}
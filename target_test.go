@@ -0,0 +1,40 @@
+package gobacktest
+
+import (
+	"testing"
+)
+
+func TestTarget(t *testing.T) {
+	var testCases = []struct {
+		msg       string
+		target    *Target
+		expWeight float64
+		expWOk    bool
+		expQty    int64
+		expQOk    bool
+	}{
+		{"test target weight:",
+			NewTargetWeight(&Event{symbol: "TEST.DE"}, 0.25),
+			0.25, true,
+			0, false,
+		},
+		{"test target qty:",
+			NewTargetQty(&Event{symbol: "TEST.DE"}, -100),
+			0, false,
+			-100, true,
+		},
+	}
+
+	for _, tc := range testCases {
+		weight, wOk := tc.target.TargetWeight()
+		qty, qOk := tc.target.TargetQty()
+		if (weight != tc.expWeight) || (wOk != tc.expWOk) || (qty != tc.expQty) || (qOk != tc.expQOk) {
+			t.Errorf("%v TargetWeight() TargetQty(): \nexpected %v %v %v %v, \nactual   %v %v %v %v",
+				tc.msg, tc.expWeight, tc.expWOk, tc.expQty, tc.expQOk, weight, wOk, qty, qOk)
+		}
+		if tc.target.Direction() != HLD || tc.target.Symbol() != "TEST.DE" {
+			t.Errorf("%v Direction() Symbol(): expected %v %v, actual %v %v",
+				tc.msg, HLD, "TEST.DE", tc.target.Direction(), tc.target.Symbol())
+		}
+	}
+}
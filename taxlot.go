@@ -0,0 +1,331 @@
+package gobacktest
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LotMethod determines which open lots are matched by a closing trade.
+type LotMethod int
+
+// Methods to match the open lots of a position.
+const (
+	FIFO        LotMethod = iota // first in, first out
+	LIFO                         // last in, first out
+	HighestCost                  // the lot with the highest cost, which realises the lowest gain first
+	AverageCost                  // all open lots share the average cost, matched first in, first out
+)
+
+// String returns the name of the lot method.
+func (m LotMethod) String() string {
+	switch m {
+	case FIFO:
+		return "FIFO"
+	case LIFO:
+		return "LIFO"
+	case HighestCost:
+		return "HighestCost"
+	case AverageCost:
+		return "AverageCost"
+	}
+	return fmt.Sprintf("LotMethod(%d)", int(m))
+}
+
+// washSaleWindow is the period before and after a loss in which a new lot is a replacement lot.
+const washSaleWindow = 30 * 24 * time.Hour
+
+// Lot is the open part of a position from a single opening fill.
+type Lot struct {
+	Symbol string
+	Opened time.Time
+	Qty    int64   // positive for a long lot, negative for a short lot
+	Price  float64 // price per unit including the cost of the fill and disallowed wash sale losses
+}
+
+// ClosedLot is a lot or the part of a lot, which was closed by a trade.
+type ClosedLot struct {
+	Symbol        string
+	Opened        time.Time
+	Closed        time.Time
+	Qty           int64   // positive for a long lot, negative for a short lot
+	OpenPrice     float64 // price per unit including cost
+	ClosePrice    float64 // price per unit including cost
+	ProfitLoss    float64 // realised profit and loss
+	HoldingPeriod time.Duration
+	LongTerm      bool    // held at least for the long-term period
+	WashSale      bool    // the loss is disallowed by a wash sale
+	Disallowed    float64 // the disallowed part of the loss, added to the cost of the replacement lot
+}
+
+// TaxLots keeps every opening fill of the portfolio as a lot and matches the closing fills against
+// the open lots of the symbol by the lot method. A lot held for at least the long-term period is
+// classified as long-term. With the wash sale option a loss is disallowed, if a lot in the same direction
+// is opened within 30 days before or after the loss, and the loss is added to the cost of that replacement lot.
+type TaxLots struct {
+	Method   LotMethod
+	LongTerm time.Duration // holding period of a long-term lot, 0 classifies no lot as long-term
+	WashSale bool
+	open     map[string][]*Lot
+	closed   []ClosedLot
+	losses   []washLoss     // losses waiting for a replacement lot
+	replaced map[*Lot]int64 // qty of a replacement lot, which already absorbed a disallowed loss
+}
+
+// washLoss is the part of the loss of a closed lot, which waits for a replacement lot.
+type washLoss struct {
+	index int   // index of the closed lot
+	qty   int64 // qty of the loss, which is not yet disallowed
+}
+
+// NewTaxLots creates a lot tracker with the given method and a long-term period of one year.
+func NewTaxLots(method LotMethod) *TaxLots {
+	return &TaxLots{
+		Method:   method,
+		LongTerm: 365 * 24 * time.Hour,
+	}
+}
+
+// Open returns the open lots of a symbol in the order they were opened.
+func (t *TaxLots) Open(symbol string) []Lot {
+	lots := make([]Lot, 0, len(t.open[symbol]))
+	for _, l := range t.open[symbol] {
+		lots = append(lots, *l)
+	}
+	sort.SliceStable(lots, func(i, j int) bool { return lots[i].Opened.Before(lots[j].Opened) })
+	return lots
+}
+
+// Closed returns all closed lots in the order they were closed.
+func (t *TaxLots) Closed() []ClosedLot {
+	return t.closed
+}
+
+// Reset implements Reseter to remove all lots.
+func (t *TaxLots) Reset() error {
+	t.open = nil
+	t.closed = nil
+	t.losses = nil
+	t.replaced = nil
+	return nil
+}
+
+// OnFill matches a fill against the open lots of its symbol. The part of the fill closing
+// the open lots realises the closed lots, the remaining part opens a new lot.
+func (t *TaxLots) OnFill(fill FillEvent) {
+	if (fill.Qty() == 0) || ((fill.Direction() != BOT) && (fill.Direction() != SLD)) {
+		return
+	}
+	if t.open == nil {
+		t.open = make(map[string][]*Lot)
+	}
+
+	// price per unit including the cost of the fill
+	qty := fill.Qty()
+	price := fill.Price() + fill.Cost()/float64(qty)
+	if fill.Direction() == SLD {
+		qty = -qty
+		price = fill.Price() - fill.Cost()/float64(fill.Qty())
+	}
+
+	qty = t.close(fill.Symbol(), fill.Time(), qty, price)
+	if qty == 0 {
+		return
+	}
+
+	lot := &Lot{Symbol: fill.Symbol(), Opened: fill.Time(), Qty: qty, Price: price}
+	t.replace(lot)
+	t.open[fill.Symbol()] = append(t.open[fill.Symbol()], lot)
+	if t.Method == AverageCost {
+		t.average(fill.Symbol())
+	}
+}
+
+// close matches the qty against the open lots in the opposite direction and returns the unmatched qty.
+func (t *TaxLots) close(symbol string, at time.Time, qty int64, price float64) int64 {
+	lots := t.open[symbol]
+	if (len(lots) == 0) || ((lots[0].Qty > 0) == (qty > 0)) {
+		return qty
+	}
+
+	t.sort(lots)
+	for (len(lots) > 0) && (qty != 0) {
+		lot := lots[0]
+
+		matched := lot.Qty
+		if abs(qty) < abs(lot.Qty) {
+			matched = -qty
+		}
+		lot.Qty -= matched
+		qty += matched
+		if lot.Qty == 0 {
+			lots = lots[1:]
+			delete(t.replaced, lot)
+		}
+
+		closed := ClosedLot{
+			Symbol:        symbol,
+			Opened:        lot.Opened,
+			Closed:        at,
+			Qty:           matched,
+			OpenPrice:     lot.Price,
+			ClosePrice:    price,
+			ProfitLoss:    math.Round((price-lot.Price)*float64(matched)*math.Pow10(DP)) / math.Pow10(DP),
+			HoldingPeriod: at.Sub(lot.Opened),
+		}
+		closed.LongTerm = (t.LongTerm > 0) && (closed.HoldingPeriod >= t.LongTerm)
+		t.closed = append(t.closed, closed)
+
+		if t.WashSale && (closed.ProfitLoss < 0) {
+			t.wash(len(t.closed)-1, lots)
+		}
+	}
+
+	t.open[symbol] = lots
+	return qty
+}
+
+// sort orders the open lots of a symbol by the lot method, the next lot to match first.
+func (t *TaxLots) sort(lots []*Lot) {
+	switch t.Method {
+	case LIFO:
+		sort.SliceStable(lots, func(i, j int) bool { return lots[i].Opened.After(lots[j].Opened) })
+	case HighestCost:
+		// a short lot with the lowest sale price has the highest cost
+		sort.SliceStable(lots, func(i, j int) bool {
+			if lots[i].Qty < 0 {
+				return lots[i].Price < lots[j].Price
+			}
+			return lots[i].Price > lots[j].Price
+		})
+	default:
+		sort.SliceStable(lots, func(i, j int) bool { return lots[i].Opened.Before(lots[j].Opened) })
+	}
+}
+
+// average sets the price of all open lots of a symbol to their average price.
+func (t *TaxLots) average(symbol string) {
+	var qty int64
+	var value float64
+	for _, l := range t.open[symbol] {
+		qty += l.Qty
+		value += float64(l.Qty) * l.Price
+	}
+	if qty == 0 {
+		return
+	}
+
+	price := value / float64(qty)
+	for _, l := range t.open[symbol] {
+		l.Price = price
+	}
+}
+
+// wash disallows the loss of a closed lot, if lots in the same direction were opened within the window
+// before the loss. The part of the loss above the qty of these lots waits for a replacement lot opened
+// within the window after it.
+func (t *TaxLots) wash(i int, lots []*Lot) {
+	loss := t.closed[i]
+	qty := abs(loss.Qty)
+	for _, l := range lots {
+		if ((l.Qty > 0) == (loss.Qty > 0)) && l.Opened.After(loss.Opened) && (loss.Closed.Sub(l.Opened) <= washSaleWindow) {
+			qty = t.disallow(i, qty, l)
+		}
+		if qty == 0 {
+			return
+		}
+	}
+	t.losses = append(t.losses, washLoss{index: i, qty: qty})
+}
+
+// replace uses a new lot as replacement lot for the waiting losses within the window before it.
+func (t *TaxLots) replace(lot *Lot) {
+	if !t.WashSale {
+		return
+	}
+
+	var waiting []washLoss
+	for _, w := range t.losses {
+		loss := t.closed[w.index]
+		if lot.Opened.Sub(loss.Closed) > washSaleWindow {
+			continue
+		}
+		if (loss.Symbol == lot.Symbol) && ((lot.Qty > 0) == (loss.Qty > 0)) {
+			w.qty = t.disallow(w.index, w.qty, lot)
+		}
+		if w.qty > 0 {
+			waiting = append(waiting, w)
+		}
+	}
+	t.losses = waiting
+}
+
+// disallow marks the loss of a closed lot as wash sale and adds the disallowed loss to the cost of the replacement lot.
+// The loss of qty is disallowed for at most the qty of the replacement lot, which has not yet absorbed another loss.
+// It returns the qty of the loss, which is not disallowed.
+func (t *TaxLots) disallow(i int, qty int64, replacement *Lot) int64 {
+	available := abs(replacement.Qty) - t.replaced[replacement]
+	if available <= 0 {
+		return qty
+	}
+	matched := qty
+	if available < matched {
+		matched = available
+	}
+
+	if t.replaced == nil {
+		t.replaced = make(map[*Lot]int64)
+	}
+	t.replaced[replacement] += matched
+
+	loss := &t.closed[i]
+	disallowed := -loss.ProfitLoss * float64(matched) / float64(abs(loss.Qty))
+	loss.WashSale = true
+	loss.Disallowed = math.Round((loss.Disallowed+disallowed)*math.Pow10(DP)) / math.Pow10(DP)
+
+	// a higher cost for a long lot is a lower sale price for a short lot
+	replacement.Price += disallowed / float64(replacement.Qty)
+	return qty - matched
+}
+
+// WriteCSV writes the closed lots as csv with a header line.
+func (t *TaxLots) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := []string{"Symbol", "Opened", "Closed", "Qty", "OpenPrice", "ClosePrice",
+		"ProfitLoss", "HoldingDays", "Term", "WashSale", "Disallowed"}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, l := range t.closed {
+		term := "short"
+		if l.LongTerm {
+			term = "long"
+		}
+
+		record := []string{
+			l.Symbol,
+			l.Opened.Format(time.RFC3339),
+			l.Closed.Format(time.RFC3339),
+			strconv.FormatInt(l.Qty, 10),
+			strconv.FormatFloat(l.OpenPrice, 'f', DP, 64),
+			strconv.FormatFloat(l.ClosePrice, 'f', DP, 64),
+			strconv.FormatFloat(l.ProfitLoss, 'f', DP, 64),
+			strconv.Itoa(int(l.HoldingPeriod.Hours() / 24)),
+			term,
+			strconv.FormatBool(l.WashSale),
+			strconv.FormatFloat(l.Disallowed, 'f', DP, 64),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
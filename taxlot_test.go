@@ -0,0 +1,229 @@
+package gobacktest
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTaxLots(t *testing.T) {
+	start := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	type trade struct {
+		days  int // days since start
+		dir   Direction
+		qty   int64
+		price float64
+		cost  float64
+	}
+
+	var testCases = []struct {
+		msg       string
+		lots      *TaxLots
+		trades    []trade
+		expClosed []float64 // profit and loss of the closed lots
+		expOpen   []Lot
+	}{
+		{"testing fifo",
+			NewTaxLots(FIFO),
+			[]trade{{0, BOT, 10, 10, 0}, {1, BOT, 10, 20, 0}, {2, SLD, 15, 30, 0}},
+			[]float64{200, 50},
+			[]Lot{{Opened: start.AddDate(0, 0, 1), Qty: 5, Price: 20}},
+		},
+		{"testing lifo",
+			NewTaxLots(LIFO),
+			[]trade{{0, BOT, 10, 10, 0}, {1, BOT, 10, 20, 0}, {2, SLD, 15, 30, 0}},
+			[]float64{100, 100},
+			[]Lot{{Opened: start, Qty: 5, Price: 10}},
+		},
+		{"testing highest cost",
+			NewTaxLots(HighestCost),
+			[]trade{{0, BOT, 10, 10, 0}, {1, BOT, 10, 30, 0}, {2, BOT, 10, 20, 0}, {3, SLD, 15, 25, 0}},
+			[]float64{-50, 25},
+			[]Lot{{Opened: start, Qty: 10, Price: 10}, {Opened: start.AddDate(0, 0, 2), Qty: 5, Price: 20}},
+		},
+		{"testing highest cost of short lots",
+			NewTaxLots(HighestCost),
+			[]trade{{0, SLD, 10, 20, 0}, {1, SLD, 10, 10, 0}, {2, BOT, 10, 15, 0}},
+			[]float64{-50},
+			[]Lot{{Opened: start, Qty: -10, Price: 20}},
+		},
+		{"testing average cost",
+			NewTaxLots(AverageCost),
+			[]trade{{0, BOT, 10, 10, 0}, {1, BOT, 10, 20, 0}, {2, SLD, 15, 30, 0}},
+			[]float64{150, 75},
+			[]Lot{{Opened: start.AddDate(0, 0, 1), Qty: 5, Price: 15}},
+		},
+		{"testing short lot",
+			NewTaxLots(FIFO),
+			[]trade{{0, SLD, 10, 10, 0}, {1, BOT, 10, 8, 0}},
+			[]float64{20},
+			[]Lot{},
+		},
+		{"testing cost of fills",
+			NewTaxLots(FIFO),
+			[]trade{{0, BOT, 10, 10, 10}, {1, SLD, 10, 12, 10}},
+			[]float64{0},
+			[]Lot{},
+		},
+		{"testing flip of position",
+			NewTaxLots(FIFO),
+			[]trade{{0, BOT, 10, 10, 0}, {1, SLD, 15, 12, 0}},
+			[]float64{20},
+			[]Lot{{Opened: start.AddDate(0, 0, 1), Qty: -5, Price: 12}},
+		},
+		{"testing wash sale with replacement lot after the loss",
+			&TaxLots{Method: FIFO, WashSale: true},
+			[]trade{{0, BOT, 10, 10, 0}, {10, SLD, 10, 8, 0}, {20, BOT, 10, 9, 0}},
+			[]float64{-20},
+			[]Lot{{Opened: start.AddDate(0, 0, 20), Qty: 10, Price: 11}},
+		},
+		{"testing wash sale with replacement lot before the loss",
+			&TaxLots{Method: FIFO, WashSale: true},
+			[]trade{{0, BOT, 10, 10, 0}, {20, BOT, 10, 9, 0}, {25, SLD, 10, 8, 0}},
+			[]float64{-20},
+			[]Lot{{Opened: start.AddDate(0, 0, 20), Qty: 10, Price: 11}},
+		},
+		{"testing wash sale with smaller replacement lot",
+			&TaxLots{Method: FIFO, WashSale: true},
+			[]trade{{0, BOT, 10, 10, 0}, {10, SLD, 10, 8, 0}, {20, BOT, 5, 9, 0}},
+			[]float64{-20},
+			[]Lot{{Opened: start.AddDate(0, 0, 20), Qty: 5, Price: 11}},
+		},
+		{"testing no wash sale after the window",
+			&TaxLots{Method: FIFO, WashSale: true},
+			[]trade{{0, BOT, 10, 10, 0}, {10, SLD, 10, 8, 0}, {50, BOT, 10, 9, 0}},
+			[]float64{-20},
+			[]Lot{{Opened: start.AddDate(0, 0, 50), Qty: 10, Price: 9}},
+		},
+	}
+
+	for _, tc := range testCases {
+		for _, tr := range tc.trades {
+			tc.lots.OnFill(&Fill{
+				Event:     Event{timestamp: start.AddDate(0, 0, tr.days), symbol: "TEST.DE"},
+				direction: tr.dir, qty: tr.qty, price: tr.price, cost: tr.cost,
+			})
+		}
+
+		closed := []float64{}
+		for _, l := range tc.lots.Closed() {
+			closed = append(closed, l.ProfitLoss)
+		}
+		open := tc.lots.Open("TEST.DE")
+		for i := range open {
+			open[i].Symbol = ""
+		}
+
+		if !reflect.DeepEqual(closed, tc.expClosed) || !reflect.DeepEqual(open, tc.expOpen) {
+			t.Errorf("%v: \nexpected %v %+v, \nactual   %v %+v", tc.msg, tc.expClosed, tc.expOpen, closed, open)
+		}
+
+		tc.lots.Reset()
+		if (len(tc.lots.Closed()) != 0) || (len(tc.lots.Open("TEST.DE")) != 0) {
+			t.Errorf("%v Reset(): \nexpected %v, \nactual   %v %v", tc.msg, 0, len(tc.lots.Closed()), len(tc.lots.Open("TEST.DE")))
+		}
+	}
+}
+
+func TestTaxLotsWashSaleReplacementQty(t *testing.T) {
+	start := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	lots := &TaxLots{Method: FIFO, WashSale: true}
+
+	// two losses of 10 each wait for replacement lots, the first lot of 5 replaces half of the first loss,
+	// the second lot of 10 replaces the rest of the first and half of the second loss
+	for _, f := range []*Fill{
+		{Event: Event{timestamp: start, symbol: "TEST.DE"}, direction: BOT, qty: 20, price: 100},
+		{Event: Event{timestamp: start.AddDate(0, 0, 10), symbol: "TEST.DE"}, direction: SLD, qty: 10, price: 90},
+		{Event: Event{timestamp: start.AddDate(0, 0, 11), symbol: "TEST.DE"}, direction: SLD, qty: 10, price: 90},
+		{Event: Event{timestamp: start.AddDate(0, 0, 20), symbol: "TEST.DE"}, direction: BOT, qty: 5, price: 95},
+		{Event: Event{timestamp: start.AddDate(0, 0, 25), symbol: "TEST.DE"}, direction: BOT, qty: 10, price: 95},
+	} {
+		lots.OnFill(f)
+	}
+
+	var disallowed []float64
+	for _, l := range lots.Closed() {
+		disallowed = append(disallowed, l.Disallowed)
+	}
+	var prices []float64
+	for _, l := range lots.Open("TEST.DE") {
+		prices = append(prices, l.Price)
+	}
+
+	var testCases = []struct {
+		msg      string
+		value    interface{}
+		expValue interface{}
+	}{
+		{"testing disallowed losses", disallowed, []float64{100, 50}},
+		{"testing prices of the replacement lots", prices, []float64{105, 105}},
+		{"testing waiting loss", lots.losses, []washLoss{{index: 1, qty: 5}}},
+	}
+
+	for _, tc := range testCases {
+		if !reflect.DeepEqual(tc.value, tc.expValue) {
+			t.Errorf("%v: \nexpected %v, \nactual   %v", tc.msg, tc.expValue, tc.value)
+		}
+	}
+}
+
+func TestTaxLotsClosed(t *testing.T) {
+	start := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	lots := NewTaxLots(FIFO)
+	lots.WashSale = true
+
+	// the remaining part of the same lot is no replacement lot of the loss
+	fills := []*Fill{
+		{Event: Event{timestamp: start, symbol: "TEST.DE"}, direction: BOT, qty: 10, price: 10},
+		{Event: Event{timestamp: start, symbol: "TEST.DE"}, direction: BOT, qty: 10, price: 20},
+		{Event: Event{timestamp: start.AddDate(0, 0, 100), symbol: "TEST.DE"}, direction: SLD, qty: 15, price: 15},
+		{Event: Event{timestamp: start.AddDate(1, 0, 10), symbol: "TEST.DE"}, direction: SLD, qty: 5, price: 30},
+	}
+	for _, f := range fills {
+		lots.OnFill(f)
+	}
+
+	expClosed := []ClosedLot{
+		{Symbol: "TEST.DE", Opened: start, Closed: start.AddDate(0, 0, 100), Qty: 10, OpenPrice: 10, ClosePrice: 15,
+			ProfitLoss: 50, HoldingPeriod: 100 * 24 * time.Hour},
+		{Symbol: "TEST.DE", Opened: start, Closed: start.AddDate(0, 0, 100), Qty: 5, OpenPrice: 20, ClosePrice: 15,
+			ProfitLoss: -25, HoldingPeriod: 100 * 24 * time.Hour},
+		{Symbol: "TEST.DE", Opened: start, Closed: start.AddDate(1, 0, 10), Qty: 5, OpenPrice: 20, ClosePrice: 30,
+			ProfitLoss: 50, HoldingPeriod: 375 * 24 * time.Hour, LongTerm: true},
+	}
+	if !reflect.DeepEqual(lots.Closed(), expClosed) {
+		t.Errorf("testing closed lots: \nexpected %+v, \nactual   %+v", expClosed, lots.Closed())
+	}
+
+	var buf bytes.Buffer
+	if err := lots.WriteCSV(&buf); err != nil {
+		t.Fatalf("testing csv export: unexpected error %v", err)
+	}
+	expCSV := "Symbol,Opened,Closed,Qty,OpenPrice,ClosePrice,ProfitLoss,HoldingDays,Term,WashSale,Disallowed\n" +
+		"TEST.DE,2017-01-02T00:00:00Z,2017-04-12T00:00:00Z,10,10.0000,15.0000,50.0000,100,short,false,0.0000\n" +
+		"TEST.DE,2017-01-02T00:00:00Z,2017-04-12T00:00:00Z,5,20.0000,15.0000,-25.0000,100,short,false,0.0000\n" +
+		"TEST.DE,2017-01-02T00:00:00Z,2018-01-12T00:00:00Z,5,20.0000,30.0000,50.0000,375,long,false,0.0000\n"
+	if buf.String() != expCSV {
+		t.Errorf("testing csv export: \nexpected %v, \nactual   %v", expCSV, buf.String())
+	}
+}
+
+func TestPortfolioTaxLots(t *testing.T) {
+	p := &Portfolio{}
+	p.SetTaxLots(NewTaxLots(FIFO))
+
+	p.OnFill(&Fill{Event: Event{symbol: "TEST.DE"}, direction: BOT, qty: 10, price: 10}, nil)
+	p.OnFill(&Fill{Event: Event{symbol: "TEST.DE"}, direction: SLD, qty: 4, price: 12}, nil)
+
+	if (len(p.TaxLots().Closed()) != 1) || (len(p.TaxLots().Open("TEST.DE")) != 1) {
+		t.Errorf("testing portfolio lots: \nexpected %v %v, \nactual   %v %v",
+			1, 1, len(p.TaxLots().Closed()), len(p.TaxLots().Open("TEST.DE")))
+	}
+
+	p.Reset()
+	if len(p.TaxLots().Closed()) != 0 {
+		t.Errorf("testing portfolio Reset(): \nexpected %v, \nactual   %v", 0, len(p.TaxLots().Closed()))
+	}
+}
@@ -0,0 +1,267 @@
+package gobacktest
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TearsheetOptions configures the HTML report of a backtest.
+type TearsheetOptions struct {
+	Title       string
+	Performance PerformanceOptions
+	Config      map[string]string // configuration of the run, e.g. the strategy parameters
+}
+
+// size of the charts of the tearsheet
+const (
+	chartWidth    = 800
+	chartHeight   = 240
+	chartDrawdown = 120
+	chartMargin   = 40
+)
+
+// tearsheetView holds the prepared content of the tearsheet template.
+type tearsheetView struct {
+	Title         string
+	Start         string
+	End           string
+	EquityChart   template.HTML
+	DrawdownChart template.HTML
+	Months        []string
+	Returns       []tearsheetRow
+	Metrics       []tearsheetPair
+	Trades        []exportTrade
+	Config        []tearsheetPair
+}
+
+type tearsheetRow struct {
+	Year   int
+	Months []tearsheetCell
+	Total  tearsheetCell
+}
+
+type tearsheetCell struct {
+	Value string
+	Style template.CSS
+}
+
+type tearsheetPair struct {
+	Name  string
+	Value string
+}
+
+var tearsheetTemplate = template.Must(template.New("tearsheet").Funcs(template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"float": formatFloat,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+	body { font-family: Arial, sans-serif; font-size: 12px; margin: 20px; color: #000000; }
+	h1 { font-size: 20px; }
+	h2 { font-size: 16px; margin-top: 30px; }
+	table { border-collapse: collapse; }
+	th, td { border: 1px solid #B2B2B2; padding: 3px 6px; text-align: right; }
+	th:first-child, td:first-child { text-align: left; }
+	.grid { stroke: #B2B2B2; stroke-width: 0.5; }
+	.equity { fill: none; stroke: #009FE3; stroke-width: 1; }
+	.drawdown { fill: #E30613; fill-opacity: 0.4; stroke: #E30613; stroke-width: 0.5; }
+	.label { font-size: 10px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Start}} - {{.End}}</p>
+
+<h2>Equity</h2>
+{{.EquityChart}}
+
+<h2>Drawdown</h2>
+{{.DrawdownChart}}
+
+<h2>Metrics</h2>
+<table>
+{{- range .Metrics}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Monthly returns</h2>
+<table>
+<tr><th>Year</th>{{range .Months}}<th>{{.}}</th>{{end}}<th>Year</th></tr>
+{{- range .Returns}}
+<tr><td>{{.Year}}</td>{{range .Months}}<td{{with .Style}} style="{{.}}"{{end}}>{{.Value}}</td>{{end}}<td{{with .Total.Style}} style="{{.}}"{{end}}>{{.Total.Value}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Trades</h2>
+<table>
+<tr><th>Symbol</th><th>Direction</th><th>Entry</th><th>Exit</th><th>Qty</th><th>Entry price</th><th>Exit price</th><th>Cost</th><th>Profit/Loss</th><th>MAE</th><th>MFE</th></tr>
+{{- range .Trades}}
+<tr><td>{{.Symbol}}</td><td>{{.Direction}}</td><td>{{date .Entry}}</td><td>{{with .Exit}}{{date .}}{{else}}open{{end}}</td><td>{{.Qty}}</td><td>{{float .EntryPrice}}</td><td>{{float .ExitPrice}}</td><td>{{float .Cost}}</td><td>{{float .ProfitLoss}}</td><td>{{float .MAE}}</td><td>{{float .MFE}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Configuration</h2>
+<table>
+{{- range .Config}}
+<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// WriteTearsheet writes a self-contained HTML report with the equity and drawdown chart as inline svg,
+// a monthly returns heatmap, the metrics, the trades and the configuration of the run.
+func (s Statistic) WriteTearsheet(w io.Writer, opts TearsheetOptions) error {
+	points := s.equity
+
+	view := tearsheetView{
+		Title:  opts.Title,
+		Trades: s.exportTrades(),
+	}
+	if view.Title == "" {
+		view.Title = "Backtest report"
+	}
+	if len(points) > 0 {
+		view.Start = points[0].timestamp.Format("2006-01-02")
+		view.End = points[len(points)-1].timestamp.Format("2006-01-02")
+	}
+
+	view.EquityChart = svgChart(points, func(e EquityPoint) float64 { return e.equity }, chartHeight, false)
+	view.DrawdownChart = svgChart(points, func(e EquityPoint) float64 { return e.drawdown }, chartDrawdown, true)
+
+	for m := time.January; m <= time.December; m++ {
+		view.Months = append(view.Months, m.String()[:3])
+	}
+	table := s.ReturnTable()
+	var scale, yearScale float64
+	for _, row := range table {
+		for _, r := range row.Months {
+			scale = math.Max(scale, math.Abs(r))
+		}
+		yearScale = math.Max(yearScale, math.Abs(row.Total))
+	}
+	for _, row := range table {
+		r := tearsheetRow{Year: row.Year, Total: heatmapCell(row.Total, yearScale)}
+		for m := time.January; m <= time.December; m++ {
+			value, ok := row.Months[m]
+			if !ok {
+				r.Months = append(r.Months, tearsheetCell{})
+				continue
+			}
+			r.Months = append(r.Months, heatmapCell(value, scale))
+		}
+		view.Returns = append(view.Returns, r)
+	}
+
+	for _, m := range s.exportMetrics(opts.Performance) {
+		value := strconv.FormatFloat(m.value, 'f', -1, 64)
+		if math.IsNaN(m.value) {
+			value = "n/a"
+		}
+		view.Metrics = append(view.Metrics, tearsheetPair{Name: m.name, Value: value})
+	}
+
+	for name, value := range opts.Config {
+		view.Config = append(view.Config, tearsheetPair{Name: name, Value: value})
+	}
+	sort.Slice(view.Config, func(i, j int) bool { return view.Config[i].Name < view.Config[j].Name })
+
+	return tearsheetTemplate.Execute(w, view)
+}
+
+// ExportTearsheet writes the HTML report into the file.
+func (s Statistic) ExportTearsheet(path string, opts TearsheetOptions) error {
+	return writeFile(path, func(w io.Writer) error { return s.WriteTearsheet(w, opts) })
+}
+
+// heatmapCell returns a cell of the monthly returns, the color intensity grows with the return relative to the scale.
+func heatmapCell(r, scale float64) tearsheetCell {
+	cell := tearsheetCell{Value: strconv.FormatFloat(r*100, 'f', 2, 64) + "%"}
+	if (r == 0) || (scale == 0) {
+		return cell
+	}
+
+	alpha := 0.1 + 0.7*math.Min(math.Abs(r)/scale, 1)
+	color := "0,150,64"
+	if r < 0 {
+		color = "227,6,19"
+	}
+	cell.Style = template.CSS(fmt.Sprintf("background-color: rgba(%s,%.2f)", color, alpha))
+	return cell
+}
+
+// svgChart draws the values of the equity points over time as inline svg.
+// An area chart is filled between the values and the zero line.
+func svgChart(points []EquityPoint, value func(EquityPoint) float64, height float64, area bool) template.HTML {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg width="%d" height="%.0f" viewBox="0 0 %d %.0f">`, chartWidth, height, chartWidth, height)
+
+	if len(points) == 0 {
+		fmt.Fprintf(&b, `<text class="label" x="%d" y="%.0f">no equity points</text>`, chartMargin, height/2)
+		return template.HTML(b.String() + "</svg>")
+	}
+
+	min, max := value(points[0]), value(points[0])
+	for _, e := range points {
+		min = math.Min(min, value(e))
+		max = math.Max(max, value(e))
+	}
+	if area {
+		max = math.Max(max, 0)
+	}
+	if min == max {
+		min, max = min-1, max+1
+	}
+
+	start := points[0].timestamp
+	duration := points[len(points)-1].timestamp.Sub(start).Seconds()
+	plotWidth := float64(chartWidth - 2*chartMargin)
+	plotHeight := height - chartMargin
+	x := func(t time.Time) float64 {
+		if duration == 0 {
+			return chartMargin
+		}
+		return chartMargin + t.Sub(start).Seconds()/duration*plotWidth
+	}
+	y := func(v float64) float64 {
+		return chartMargin/2 + (max-v)/(max-min)*plotHeight
+	}
+
+	// grid with the min and max value, the start and end date
+	for _, v := range []float64{min, max} {
+		fmt.Fprintf(&b, `<line class="grid" x1="%d" y1="%.1f" x2="%d" y2="%.1f"/>`, chartMargin, y(v), chartWidth-chartMargin, y(v))
+		fmt.Fprintf(&b, `<text class="label" x="2" y="%.1f">%s</text>`, y(v)+3, strconv.FormatFloat(v, 'f', 2, 64))
+	}
+	fmt.Fprintf(&b, `<text class="label" x="%d" y="%.0f">%s</text>`, chartMargin, height-4, start.Format("2006-01-02"))
+	fmt.Fprintf(&b, `<text class="label" x="%d" y="%.0f" text-anchor="end">%s</text>`,
+		chartWidth-chartMargin, height-4, points[len(points)-1].timestamp.Format("2006-01-02"))
+
+	var path bytes.Buffer
+	for i, e := range points {
+		cmd := "L"
+		if i == 0 {
+			cmd = "M"
+		}
+		fmt.Fprintf(&path, "%s%.1f %.1f ", cmd, x(e.timestamp), y(value(e)))
+	}
+
+	class := "equity"
+	if area {
+		class = "drawdown"
+		fmt.Fprintf(&path, "L%.1f %.1f L%.1f %.1f Z", x(points[len(points)-1].timestamp), y(0), x(start), y(0))
+	}
+	fmt.Fprintf(&b, `<path class="%s" d="%s"/>`, class, bytes.TrimSpace(path.Bytes()))
+
+	return template.HTML(b.String() + "</svg>")
+}
@@ -0,0 +1,52 @@
+package gobacktest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteTearsheet(t *testing.T) {
+	stat := testHelperReturnCurve()
+	stat.ledger = testHelperExportStatistic().ledger
+
+	var b bytes.Buffer
+	err := stat.WriteTearsheet(&b, TearsheetOptions{
+		Title:  "<MA Cross>",
+		Config: map[string]string{"symbols": "A", "long": "50", "short": "10"},
+	})
+	if err != nil {
+		t.Fatalf("testing tearsheet: \nexpected %v, \nactual   %v", nil, err)
+	}
+	html := b.String()
+
+	var testCases = []struct {
+		msg      string
+		contains string
+		expOk    bool
+	}{
+		{"testing escaped title", "<h1>&lt;MA Cross&gt;</h1>", true},
+		{"testing period", "<p>2017-12-30 - 2019-01-03</p>", true},
+		{"testing equity chart", `<path class="equity" d="M40.0 210.5 L45.9 115.2 L102.4 220.0`, true},
+		{"testing drawdown chart", `<path class="drawdown"`, true},
+		{"testing positive month", `<td style="background-color: rgba(0,150,64,0.80)">10.00%</td>`, true},
+		{"testing negative month", `<td style="background-color: rgba(227,6,19,0.17)">-1.00%</td>`, true},
+		{"testing month without return", `-0.83%</td><td></td>`, true},
+		{"testing metric", "<tr><td>total_return</td><td>0.08</td></tr>", true},
+		{"testing closed trade", "<tr><td>A</td><td>BOT</td><td>2018-01-01</td><td>2018-01-02</td><td>10</td>", true},
+		{"testing open trade", "<td>2018-01-02</td><td>open</td>", true},
+		{"testing sorted config", "<tr><td>long</td><td>50</td></tr>\n<tr><td>short</td><td>10</td></tr>\n<tr><td>symbols</td><td>A</td></tr>", true},
+		{"testing no external resources", "http", false},
+	}
+
+	for _, tc := range testCases {
+		if strings.Contains(html, tc.contains) != tc.expOk {
+			t.Errorf("%v: \nexpected %v %v, \nactual   %v", tc.msg, tc.contains, tc.expOk, html)
+		}
+	}
+
+	b.Reset()
+	if err := (Statistic{}).WriteTearsheet(&b, TearsheetOptions{}); (err != nil) || !strings.Contains(b.String(), "no equity points") {
+		t.Errorf("testing tearsheet without equity: \nexpected %v, \nactual   %v %v", "no equity points", b.String(), err)
+	}
+}
@@ -0,0 +1,170 @@
+package gobacktest
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Trade is a round trip of a position, from the first entry fill until the position is closed again.
+// Fills which increase the position are scale-ins, fills which reduce it are partial exits.
+type Trade struct {
+	Symbol     string
+	Direction  Direction // BOT for a long trade, SLD for a short trade
+	Entry      time.Time
+	Exit       time.Time
+	Qty        int64   // maximum qty of the position
+	EntryPrice float64 // average price of the entry fills
+	ExitPrice  float64 // average price of the exit fills
+	Cost       float64 // cost of all fills
+	ProfitLoss float64 // realised profit and loss after cost
+	MAE        float64 // maximum adverse excursion, the lowest profit and loss while the trade was open
+	MFE        float64 // maximum favourable excursion, the highest profit and loss while the trade was open
+	Fills      int     // number of fills
+	qty        int64   // open qty
+	entryQty   int64
+	entryValue float64
+	exitQty    int64
+	exitValue  float64
+}
+
+// HoldingPeriod returns the time between the entry and the exit of the trade.
+func (t Trade) HoldingPeriod() time.Duration {
+	return t.Exit.Sub(t.Entry)
+}
+
+// EntryQty returns the qty of all entry fills of the trade.
+func (t Trade) EntryQty() int64 {
+	return t.entryQty
+}
+
+// ExitQty returns the qty of all exit fills of the trade.
+func (t Trade) ExitQty() int64 {
+	return t.exitQty
+}
+
+// IsOpen returns true if the position of the trade is not closed.
+func (t Trade) IsOpen() bool {
+	return t.qty != 0
+}
+
+// profitLoss returns the profit and loss of the trade after cost, with the open qty valued at the price.
+func (t Trade) profitLoss(price float64) float64 {
+	pl := t.exitValue + float64(t.qty)*price - t.entryValue
+	if t.Direction == SLD {
+		pl = -pl
+	}
+	return pl - t.Cost
+}
+
+// excursion updates the maximum adverse and favourable excursion with the worst and best price of the open qty.
+func (t *Trade) excursion(worst, best float64) {
+	if low := t.profitLoss(worst); low < t.MAE {
+		t.MAE = math.Round(low*math.Pow10(DP)) / math.Pow10(DP)
+	}
+	if high := t.profitLoss(best); high > t.MFE {
+		t.MFE = math.Round(high*math.Pow10(DP)) / math.Pow10(DP)
+	}
+}
+
+// TradeLedger pairs the entry and exit fills of each symbol into round trip trades
+// and records the excursions of the open trades from the data stream.
+type TradeLedger struct {
+	open   map[string]*Trade
+	closed []Trade
+}
+
+// Trades returns all closed trades in the order they were closed.
+func (l TradeLedger) Trades() []Trade {
+	return l.closed
+}
+
+// OpenTrades returns the open trades sorted by symbol, with the average price of the entry fills so far.
+func (l TradeLedger) OpenTrades() []Trade {
+	trades := make([]Trade, 0, len(l.open))
+	for _, t := range l.open {
+		trade := *t
+		if trade.entryQty > 0 {
+			trade.EntryPrice = math.Round(trade.entryValue/float64(trade.entryQty)*math.Pow10(DP)) / math.Pow10(DP)
+		}
+		trades = append(trades, trade)
+	}
+	sort.Slice(trades, func(i, j int) bool { return trades[i].Symbol < trades[j].Symbol })
+	return trades
+}
+
+// Reset implements Reseter to remove all trades.
+func (l *TradeLedger) Reset() error {
+	l.open = nil
+	l.closed = nil
+	return nil
+}
+
+// Update records the excursion of the open trade of the symbol with the price range of the data event.
+func (l *TradeLedger) Update(d DataEvent) {
+	t, ok := l.open[d.Symbol()]
+	if !ok {
+		return
+	}
+
+	_, high, low := priceRange(d)
+	if t.Direction == SLD {
+		t.excursion(high, low)
+		return
+	}
+	t.excursion(low, high)
+}
+
+// OnFill adds a fill to the open trade of its symbol or opens a new trade.
+// A fill, which reverses the position, closes the trade and opens a new one with the remaining qty.
+func (l *TradeLedger) OnFill(fill FillEvent) {
+	if (fill.Qty() == 0) || ((fill.Direction() != BOT) && (fill.Direction() != SLD)) {
+		return
+	}
+	if l.open == nil {
+		l.open = make(map[string]*Trade)
+	}
+
+	t, ok := l.open[fill.Symbol()]
+	if !ok {
+		t = &Trade{Symbol: fill.Symbol(), Direction: fill.Direction(), Entry: fill.Time()}
+		l.open[fill.Symbol()] = t
+	}
+
+	// split a fill, which reverses the position
+	if (fill.Direction() != t.Direction) && (fill.Qty() > abs(t.qty)) {
+		closing, opening := splitFill(fill, abs(t.qty))
+		l.OnFill(closing)
+		l.OnFill(opening)
+		return
+	}
+
+	t.Fills++
+	t.Cost += fill.Cost()
+	if fill.Direction() == t.Direction {
+		t.entryQty += fill.Qty()
+		t.entryValue += fill.Value()
+		t.qty += fill.Qty()
+		if t.qty > t.Qty {
+			t.Qty = t.qty
+		}
+	} else {
+		t.exitQty += fill.Qty()
+		t.exitValue += fill.Value()
+		t.qty -= fill.Qty()
+	}
+	t.excursion(fill.Price(), fill.Price())
+
+	if t.qty > 0 {
+		return
+	}
+
+	// position is closed
+	t.Exit = fill.Time()
+	t.EntryPrice = math.Round(t.entryValue/float64(t.entryQty)*math.Pow10(DP)) / math.Pow10(DP)
+	t.ExitPrice = math.Round(t.exitValue/float64(t.exitQty)*math.Pow10(DP)) / math.Pow10(DP)
+	t.ProfitLoss = math.Round(t.profitLoss(0)*math.Pow10(DP)) / math.Pow10(DP)
+	t.Cost = math.Round(t.Cost*math.Pow10(DP)) / math.Pow10(DP)
+	l.closed = append(l.closed, *t)
+	delete(l.open, fill.Symbol())
+}
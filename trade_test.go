@@ -0,0 +1,172 @@
+package gobacktest
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTradeLedger(t *testing.T) {
+	start := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	// a step is either a fill or a bar, if the high is set
+	type step struct {
+		days      int // days since start
+		dir       Direction
+		qty       int64
+		price     float64
+		cost      float64
+		high, low float64
+	}
+
+	var testCases = []struct {
+		msg       string
+		steps     []step
+		expTrades []Trade
+		expOpen   int
+	}{
+		{"testing long round trip",
+			[]step{
+				{days: 0, dir: BOT, qty: 10, price: 10, cost: 1},
+				{days: 1, high: 12, low: 8},
+				{days: 2, dir: SLD, qty: 10, price: 11, cost: 1},
+			},
+			[]Trade{{Symbol: "TEST.DE", Direction: BOT, Entry: start, Exit: start.AddDate(0, 0, 2), Qty: 10,
+				EntryPrice: 10, ExitPrice: 11, Cost: 2, ProfitLoss: 8, MAE: -21, MFE: 19, Fills: 2}},
+			0,
+		},
+		{"testing short round trip",
+			[]step{
+				{days: 0, dir: SLD, qty: 10, price: 10},
+				{days: 1, high: 13, low: 9},
+				{days: 2, dir: BOT, qty: 10, price: 8},
+			},
+			[]Trade{{Symbol: "TEST.DE", Direction: SLD, Entry: start, Exit: start.AddDate(0, 0, 2), Qty: 10,
+				EntryPrice: 10, ExitPrice: 8, ProfitLoss: 20, MAE: -30, MFE: 20, Fills: 2}},
+			0,
+		},
+		{"testing scale-in and partial exits",
+			[]step{
+				{days: 0, dir: BOT, qty: 10, price: 10},
+				{days: 1, dir: BOT, qty: 10, price: 12},
+				{days: 2, dir: SLD, qty: 5, price: 14},
+				{days: 3, dir: SLD, qty: 15, price: 13},
+			},
+			[]Trade{{Symbol: "TEST.DE", Direction: BOT, Entry: start, Exit: start.AddDate(0, 0, 3), Qty: 20,
+				EntryPrice: 11, ExitPrice: 13.25, ProfitLoss: 45, MFE: 60, Fills: 4}},
+			0,
+		},
+		{"testing reversal of the position",
+			[]step{
+				{days: 0, dir: BOT, qty: 10, price: 10},
+				{days: 1, dir: SLD, qty: 15, price: 12, cost: 3},
+			},
+			[]Trade{{Symbol: "TEST.DE", Direction: BOT, Entry: start, Exit: start.AddDate(0, 0, 1), Qty: 10,
+				EntryPrice: 10, ExitPrice: 12, Cost: 2, ProfitLoss: 18, MFE: 18, Fills: 2}},
+			1,
+		},
+		{"testing open trade",
+			[]step{
+				{days: 0, dir: BOT, qty: 10, price: 10},
+			},
+			nil,
+			1,
+		},
+	}
+
+	for _, tc := range testCases {
+		ledger := &TradeLedger{}
+		for _, s := range tc.steps {
+			event := Event{timestamp: start.AddDate(0, 0, s.days), symbol: "TEST.DE"}
+			if s.high > 0 {
+				ledger.Update(&Bar{Event: event, High: s.high, Low: s.low, Close: s.high})
+				continue
+			}
+			ledger.OnFill(&Fill{Event: event, direction: s.dir, qty: s.qty, price: s.price, cost: s.cost})
+		}
+
+		trades := ledger.Trades()
+		for i := range trades {
+			trades[i] = Trade{Symbol: trades[i].Symbol, Direction: trades[i].Direction, Entry: trades[i].Entry,
+				Exit: trades[i].Exit, Qty: trades[i].Qty, EntryPrice: trades[i].EntryPrice, ExitPrice: trades[i].ExitPrice,
+				Cost: trades[i].Cost, ProfitLoss: trades[i].ProfitLoss, MAE: trades[i].MAE, MFE: trades[i].MFE, Fills: trades[i].Fills}
+		}
+
+		if !reflect.DeepEqual(trades, tc.expTrades) || (len(ledger.OpenTrades()) != tc.expOpen) {
+			t.Errorf("%v: \nexpected %+v %v, \nactual   %+v %v", tc.msg, tc.expTrades, tc.expOpen, trades, len(ledger.OpenTrades()))
+		}
+	}
+}
+
+func TestTradeEntryExitQty(t *testing.T) {
+	start := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	// the position is scaled out and in again, the qty of the fills exceeds the maximum qty of the position
+	ledger := &TradeLedger{}
+	for i, f := range []struct {
+		dir   Direction
+		qty   int64
+		price float64
+	}{
+		{BOT, 10, 10},
+		{SLD, 5, 12},
+		{BOT, 5, 11},
+		{SLD, 10, 13},
+	} {
+		event := Event{timestamp: start.AddDate(0, 0, i), symbol: "TEST.DE"}
+		ledger.OnFill(&Fill{Event: event, direction: f.dir, qty: f.qty, price: f.price})
+	}
+
+	trades := ledger.Trades()
+	if (len(trades) != 1) || (trades[0].Qty != 10) || (trades[0].EntryQty() != 15) || (trades[0].ExitQty() != 15) {
+		t.Fatalf("testing entry and exit qty: \nexpected %v %v %v, \nactual   %+v", 10, 15, 15, trades)
+	}
+}
+
+func TestTradeStatistics(t *testing.T) {
+	start := time.Date(2018, 7, 2, 0, 0, 0, 0, time.UTC)
+
+	var testCases = []struct {
+		msg              string
+		profits          []float64 // profit and loss of the closed trades, each held one day
+		expWinRate       float64
+		expAvgWin        float64
+		expAvgLoss       float64
+		expProfitFactor  float64
+		expExpectancy    float64
+		expHolding       time.Duration
+		expMaxLossStreak int
+	}{
+		{"testing mixed trades",
+			[]float64{100, -50, -25, 200, -25, 0},
+			0.3333, 150, -33.3333, 3, 33.3333, 24 * time.Hour, 2,
+		},
+		{"testing only winning trades",
+			[]float64{100, 50},
+			1, 75, 0, math.Inf(1), 75, 24 * time.Hour, 0,
+		},
+		{"testing no trades",
+			nil,
+			0, 0, 0, 0, 0, 0, 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		stat := &Statistic{}
+		for i, p := range tc.profits {
+			entry := Event{timestamp: start.AddDate(0, 0, 2*i), symbol: "TEST.DE"}
+			exit := Event{timestamp: start.AddDate(0, 0, 2*i+1), symbol: "TEST.DE"}
+			stat.TrackTransaction(&Fill{Event: entry, direction: BOT, qty: 1, price: 1000})
+			stat.TrackTransaction(&Fill{Event: exit, direction: SLD, qty: 1, price: 1000 + p})
+		}
+
+		if (stat.WinRate() != tc.expWinRate) || (stat.AverageWin() != tc.expAvgWin) || (stat.AverageLoss() != tc.expAvgLoss) ||
+			(stat.ProfitFactor() != tc.expProfitFactor) || (stat.Expectancy() != tc.expExpectancy) ||
+			(stat.AverageHoldingTime() != tc.expHolding) || (stat.MaxConsecutiveLosses() != tc.expMaxLossStreak) {
+			t.Errorf("%v: \nexpected %v %v %v %v %v %v %v, \nactual   %v %v %v %v %v %v %v", tc.msg,
+				tc.expWinRate, tc.expAvgWin, tc.expAvgLoss, tc.expProfitFactor, tc.expExpectancy, tc.expHolding, tc.expMaxLossStreak,
+				stat.WinRate(), stat.AverageWin(), stat.AverageLoss(), stat.ProfitFactor(), stat.Expectancy(), stat.AverageHoldingTime(), stat.MaxConsecutiveLosses())
+		}
+	}
+}